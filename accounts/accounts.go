@@ -2,24 +2,168 @@ package accounts
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"strings"
+)
+
+// DefaultMnemonic is the well-known Hardhat/Anvil test mnemonic. Never fund it on a real network.
+const DefaultMnemonic = "test test test test test test test test test test test junk"
+
+// DefaultBasePath is the BIP-44 Ethereum path, account i lives at DefaultBasePath/i
+const DefaultBasePath = "m/44'/60'/0'/0"
+
+var DefaultNames = []string{"alice", "bob", "charlie", "diana", "eric", "frank", "grace", "helen", "ivan", "judy"}
+
+const (
+	SignerMemory   = "memory"   // keys derived from the mnemonic, held in memory
+	SignerKeystore = "keystore" // encrypted keystore files unlocked at startup
+	SignerExternal = "external" // a clef-compatible signer process over JSON-RPC
 )
 
 type TestAccount struct {
 	Address common.Address
 	Name    string
-	PrivKey *ecdsa.PrivateKey
-}
-
-var rawAccounts = map[string]string{
-	"alice":   "0x4f3edf983ac636a65a842ce7c78d9aa706d3b113b37b1e1a7e3975d3fef1fdc3",
-	"bob":     "0x6c8750b42f08aabb2645f65f38d013ce59b2a2b3d2a2b9c54b541714aa30353b",
-	"charlie": "0x5c7d2381d08d6579e9af64a2b7d00e33a1f519896f17f0bb3e2ff894f9b8c67f",
-	"diana":   "0x646f1ce2b44b655e5d34f4313ffb4c7b06d93b219a22993b7824b8b36aa5488e",
-	"eric":    "0xadd53f9a7e588d21953d7cc5c5e1e89f9dfd4e0f8ee0b0b98f71f5b38d9e3a3e",
-	"frank":   "0x395df67f0c2f20f3c3f4817c88c7b4c2dbd0493a34f52fd0e9f6f0017cba94d7",
-	"grace":   "0xe485d098507f9e4cdbf3d0107c4b74757b6a12a7b1300a23a1f2fffae6d1c0a7",
-	"helen":   "0xa453611d9419d0e3fdae7d16b1b5c470fe15f0ff9891753c603a00f226b84aa1",
-	"ivan":    "0x69ee0de5eae9c6f3b674e41c2c425b6f1d50dfe2e6ec8ab3403d0fd9e3f84b07",
-	"judy":    "0xdbda1821b80551c9e1c03fa0c27f14f85f3bdd8e991b52e3b0a38a42f4c1d2a3",
+	Signer  Signer
+	Path    string // derivation path, empty for random or imported keys
+}
+
+// PrivateKey returns the raw key of in-memory accounts; keystore and external accounts never expose one
+func (acc *TestAccount) PrivateKey() (*ecdsa.PrivateKey, bool) {
+	if keySigner, ok := acc.Signer.(*KeySigner); ok {
+		return keySigner.PrivateKey(), true
+	}
+	return nil, false
+}
+
+type Config struct {
+	Mnemonic         string
+	Passphrase       string
+	BasePath         string
+	Count            int
+	Names            []string // accounts past the end of the list are named account<i>
+	SignerMode       string   // memory, keystore or external
+	KeystoreDir      string
+	KeystorePassword string
+	ExternalSigner   string // URL or IPC path
+	ExposeKeys       bool   // whether HTTP APIs may return in-memory private keys
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Mnemonic:   DefaultMnemonic,
+		BasePath:   DefaultBasePath,
+		Count:      len(DefaultNames),
+		Names:      DefaultNames,
+		SignerMode: SignerMemory,
+		ExposeKeys: true,
+	}
+}
+
+// Open returns the accounts of the configured signer mode. Keystore and external accounts are
+// named in the order the backend lists them; Count only applies to the mnemonic.
+func Open(cfg Config) ([]*TestAccount, error) {
+	var signers []Signer
+	var err error
+	switch cfg.SignerMode {
+	case "", SignerMemory:
+		return Derive(cfg)
+	case SignerKeystore:
+		if cfg.KeystoreDir == "" {
+			return nil, fmt.Errorf("keystore signer needs a keystore directory")
+		}
+		signers, err = OpenKeystore(cfg.KeystoreDir, cfg.KeystorePassword)
+	case SignerExternal:
+		if cfg.ExternalSigner == "" {
+			return nil, fmt.Errorf("external signer needs an endpoint")
+		}
+		signers, err = DialExternalSigner(cfg.ExternalSigner)
+	default:
+		return nil, fmt.Errorf("unknown signer mode %q", cfg.SignerMode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*TestAccount, 0, len(signers))
+	for i, signer := range signers {
+		list = append(list, &TestAccount{
+			Address: signer.Address(),
+			Name:    cfg.Name(i),
+			Signer:  signer,
+		})
+	}
+	return list, nil
+}
+
+// Name returns the configured name of account i
+func (cfg Config) Name(i int) string {
+	if i < len(cfg.Names) && strings.TrimSpace(cfg.Names[i]) != "" {
+		return strings.TrimSpace(cfg.Names[i])
+	}
+	return fmt.Sprintf("account%d", i)
+}
+
+// Derive returns the configured accounts in derivation order
+func Derive(cfg Config) ([]*TestAccount, error) {
+	list := make([]*TestAccount, 0, cfg.Count)
+	seen := make(map[string]bool, cfg.Count)
+	for i := 0; i < cfg.Count; i++ {
+		name := cfg.Name(i)
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate account name %q", name)
+		}
+		seen[name] = true
+
+		acc, err := DeriveAccount(cfg, name, i)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, acc)
+	}
+	return list, nil
+}
+
+// DeriveAccount derives the account at index i of the configured base path
+func DeriveAccount(cfg Config, name string, i int) (*TestAccount, error) {
+	path := fmt.Sprintf("%s/%d", strings.TrimSuffix(cfg.BasePath, "/"), i)
+	key, err := DeriveKey(cfg.Mnemonic, cfg.Passphrase, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive %s (%s): %w", name, path, err)
+	}
+	acc := NewTestAccount(name, key)
+	acc.Path = path
+	return acc, nil
+}
+
+// NewTestAccount wraps a key that was not derived from the mnemonic (random or imported)
+func NewTestAccount(name string, key *ecdsa.PrivateKey) *TestAccount {
+	return &TestAccount{
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+		Name:    name,
+		Signer:  NewKeySigner(key),
+	}
+}
+
+// Load derives the configured accounts keyed by name
+func Load(cfg Config) (*map[string]*TestAccount, error) {
+	list, err := Derive(cfg)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*TestAccount, len(list))
+	for _, acc := range list {
+		byName[acc.Name] = acc
+	}
+	return &byName, nil
+}
+
+// MustDerive is Derive for tools and tests, where a bad configuration is fatal anyway
+func MustDerive(cfg Config) []*TestAccount {
+	list, err := Derive(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return list
 }
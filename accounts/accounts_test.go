@@ -1,7 +1,10 @@
 package accounts
 
 import (
+	"encoding/hex"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
 	"log"
 	"math/big"
 	"testing"
@@ -47,3 +50,34 @@ func TestHexToPrivateKey(t *testing.T) {
 	address := crypto.PubkeyToAddress(key.PublicKey)
 	log.Printf("✅ address: %v", address)
 }
+
+func TestDeriveDefaultMnemonic(t *testing.T) {
+	list, err := Derive(DefaultConfig())
+	require.NoError(t, err)
+	require.Len(t, list, 10)
+
+	// Well-known first two accounts of the Hardhat/Anvil mnemonic
+	require.Equal(t, "alice", list[0].Name)
+	require.Equal(t, "m/44'/60'/0'/0/0", list[0].Path)
+	require.Equal(t, common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), list[0].Address)
+	key, ok := list[0].PrivateKey()
+	require.True(t, ok)
+	require.Equal(t, "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80", hex.EncodeToString(crypto.FromECDSA(key)))
+	require.Equal(t, common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), list[1].Address)
+}
+
+func TestDeriveCountAndNames(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Names = []string{"ops", "treasury"}
+	cfg.Count = 3
+
+	byName, err := Load(cfg)
+	require.NoError(t, err)
+	require.Len(t, *byName, 3)
+	require.Contains(t, *byName, "account2")
+	require.Equal(t, "m/44'/60'/0'/0/1", (*byName)["treasury"].Path)
+
+	cfg.Mnemonic = "not a valid mnemonic"
+	_, err = Derive(cfg)
+	require.Error(t, err)
+}
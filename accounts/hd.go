@@ -0,0 +1,75 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"math/big"
+)
+
+// DeriveKey derives the private key at a BIP-32 path (e.g. m/44'/60'/0'/0/0) from a BIP-39 mnemonic
+func DeriveKey(mnemonic, passphrase, path string) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	derivationPath, err := gethaccounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return deriveFromSeed(seed, derivationPath)
+}
+
+func deriveFromSeed(seed []byte, path gethaccounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key, chainCode := hmacSHA512([]byte("Bitcoin seed"), seed)
+	for _, index := range path {
+		var err error
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return crypto.ToECDSA(key)
+}
+
+// deriveChild implements BIP-32 private parent → private child derivation
+func deriveChild(parentKey, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= 0x80000000 {
+		data = append(data, 0x00)
+		data = append(data, parentKey...)
+	} else {
+		parent, err := crypto.ToECDSA(parentKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&parent.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	il, childChainCode := hmacSHA512(chainCode, data)
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(il)
+	if tweak.Cmp(n) >= 0 {
+		return nil, nil, errors.New("derived key is out of range, try the next index")
+	}
+	child := tweak.Add(tweak, new(big.Int).SetBytes(parentKey))
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, errors.New("derived key is zero, try the next index")
+	}
+	return child.FillBytes(make([]byte, 32)), childChainCode, nil
+}
+
+func hmacSHA512(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
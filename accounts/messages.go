@@ -0,0 +1,52 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RecoverText returns the address that produced an EIP-191 personal_sign signature
+func RecoverText(text, signature []byte) (common.Address, error) {
+	return recoverHash(gethaccounts.TextHash(text), signature)
+}
+
+// RecoverTypedData returns the address that produced an EIP-712 signature
+func RecoverTypedData(data apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid typed data: %w", err)
+	}
+	return recoverHash(hash, signature)
+}
+
+func recoverHash(hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes", crypto.SignatureLength)
+	}
+	// Wallets hand out V as 27/28, crypto.SigToPub wants 0/1
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return common.Address{}, errors.New("invalid signature recovery id")
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// toEthereumV turns crypto.Sign's 0/1 recovery id into the 27/28 that wallets and ecrecover use
+func toEthereumV(signature []byte) []byte {
+	if len(signature) == crypto.SignatureLength && signature[64] < 27 {
+		signature[64] += 27
+	}
+	return signature
+}
@@ -0,0 +1,69 @@
+package accounts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+// The "Mail" example from the EIP-712 specification
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+		"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestSignTypedDataMatchesSpecVector(t *testing.T) {
+	var data apitypes.TypedData
+	require.NoError(t, json.Unmarshal([]byte(mailTypedData), &data))
+
+	signer := NewKeySigner(crypto.ToECDSAUnsafe(crypto.Keccak256([]byte("cow"))))
+	require.Equal(t, common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer.Address())
+
+	signature, err := signer.SignTypedData(data)
+	require.NoError(t, err)
+	require.Equal(t, "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"+
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"+"1c", hexutil.Encode(signature))
+
+	recovered, err := RecoverTypedData(data, signature)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), recovered)
+}
+
+func TestSignTextRoundTrip(t *testing.T) {
+	alice := MustDerive(DefaultConfig())[0]
+
+	signature, err := alice.Signer.SignText([]byte("hello world"))
+	require.NoError(t, err)
+	require.Contains(t, []byte{27, 28}, signature[64])
+
+	recovered, err := RecoverText([]byte("hello world"), signature)
+	require.NoError(t, err)
+	require.Equal(t, alice.Address, recovered)
+
+	other, err := RecoverText([]byte("hello world!"), signature)
+	require.NoError(t, err)
+	require.NotEqual(t, alice.Address, other)
+
+	_, err = RecoverText([]byte("hello world"), signature[:64])
+	require.Error(t, err)
+}
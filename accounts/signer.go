@@ -0,0 +1,168 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+)
+
+// Signer signs on behalf of one address without necessarily handing out its key
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignText signs an EIP-191 personal message (personal_sign)
+	SignText(text []byte) ([]byte, error)
+	// SignTypedData signs EIP-712 typed data (eth_signTypedData_v4)
+	SignTypedData(data apitypes.TypedData) ([]byte, error)
+}
+
+// KeySigner holds a dev key in memory
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewPragueSigner(chainID), s.key)
+}
+
+func (s *KeySigner) SignText(text []byte) ([]byte, error) {
+	return s.signHash(gethaccounts.TextHash(text))
+}
+
+func (s *KeySigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	return s.signHash(hash)
+}
+
+func (s *KeySigner) signHash(hash []byte) ([]byte, error) {
+	signature, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	return toEthereumV(signature), nil
+}
+
+func (s *KeySigner) PrivateKey() *ecdsa.PrivateKey {
+	return s.key
+}
+
+// WalletSigner signs through a go-ethereum wallet: an unlocked keystore or an external signer such as clef
+type WalletSigner struct {
+	wallet  gethaccounts.Wallet
+	account gethaccounts.Account
+}
+
+func NewWalletSigner(wallet gethaccounts.Wallet, account gethaccounts.Account) *WalletSigner {
+	return &WalletSigner{wallet: wallet, account: account}
+}
+
+func (s *WalletSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *WalletSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.wallet.SignTx(s.account, tx, chainID)
+}
+
+func (s *WalletSigner) SignText(text []byte) ([]byte, error) {
+	signature, err := s.wallet.SignText(s.account, text)
+	if err != nil {
+		return nil, err
+	}
+	return toEthereumV(signature), nil
+}
+
+// SignTypedData hands the "\x19\x01" ‖ domainSeparator ‖ hashStruct(message) preimage to the wallet,
+// which signs its keccak256. Keystores support this; external signers use ExternalSigner instead.
+func (s *WalletSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	_, preimage, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	signature, err := s.wallet.SignData(s.account, gethaccounts.MimetypeTypedData, []byte(preimage))
+	if err != nil {
+		return nil, err
+	}
+	return toEthereumV(signature), nil
+}
+
+// ExternalSigner signs through a clef-compatible signer. Clef does not accept a typed data preimage,
+// so typed data goes to its account_signTypedData method instead.
+type ExternalSigner struct {
+	*WalletSigner
+	client *rpc.Client
+}
+
+func (s *ExternalSigner) SignTypedData(data apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.client.Call(&signature, "account_signTypedData", common.NewMixedcaseAddress(s.Address()), data); err != nil {
+		return nil, err
+	}
+	return toEthereumV(signature), nil
+}
+
+// OpenKeystore unlocks every account of an encrypted keystore directory with the same passphrase.
+// Keys stay inside the keystore, callers only ever see signers.
+func OpenKeystore(dir, passphrase string) ([]Signer, error) {
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	wallets := ks.Wallets()
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("no keystore files in %s", dir)
+	}
+
+	// Every keystore file is its own wallet holding exactly one account
+	signers := make([]Signer, 0, len(wallets))
+	for _, wallet := range wallets {
+		account := wallet.Accounts()[0]
+		if err := ks.Unlock(account, passphrase); err != nil {
+			return nil, fmt.Errorf("failed to unlock %s: %w", account.Address.Hex(), err)
+		}
+		signers = append(signers, NewWalletSigner(wallet, account))
+	}
+	return signers, nil
+}
+
+// DialExternalSigner connects to a clef-compatible signer (account_list / account_signTransaction /
+// account_signTypedData) at an http(s) URL or IPC path and returns one signer per account it manages
+func DialExternalSigner(endpoint string) ([]Signer, error) {
+	wallet, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach external signer: %w", err)
+	}
+	managed := wallet.Accounts()
+	if len(managed) == 0 {
+		return nil, errors.New("external signer manages no accounts")
+	}
+
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach external signer: %w", err)
+	}
+
+	signers := make([]Signer, 0, len(managed))
+	for _, account := range managed {
+		signers = append(signers, &ExternalSigner{WalletSigner: NewWalletSigner(wallet, account), client: client})
+	}
+	return signers, nil
+}
@@ -0,0 +1,122 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+func unsignedTx() *types.Transaction {
+	to := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1_000_000_000),
+		Gas:       21_000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	})
+}
+
+func requireSignedBy(t *testing.T, signer Signer, tx *types.Transaction) {
+	signed, err := signer.SignTx(tx, big.NewInt(1337))
+	require.NoError(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1337)), signed)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), sender)
+}
+
+func TestOpenKeystore(t *testing.T) {
+	dir := t.TempDir()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP).ImportECDSA(key, "secret")
+	require.NoError(t, err)
+
+	_, err = OpenKeystore(dir, "wrong")
+	require.Error(t, err)
+
+	signers, err := OpenKeystore(dir, "secret")
+	require.NoError(t, err)
+	require.Len(t, signers, 1)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signers[0].Address())
+	requireSignedBy(t, signers[0], unsignedTx())
+
+	list, err := Open(Config{SignerMode: SignerKeystore, KeystoreDir: dir, KeystorePassword: "secret", Names: []string{"vault"}})
+	require.NoError(t, err)
+	require.Equal(t, "vault", list[0].Name)
+	_, ok := list[0].PrivateKey()
+	require.False(t, ok, "keystore accounts never hand out their key")
+}
+
+// clefService implements the account_* methods DialExternalSigner relies on
+type clefService struct {
+	signer *KeySigner
+}
+
+func (s *clefService) Version() string { return "6.0.0" }
+
+func (s *clefService) List() []common.Address { return []common.Address{s.signer.Address()} }
+
+func (s *clefService) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := s.signer.SignTx(tx, (*big.Int)(args.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+// SignTypedData answers like clef: the signature with V as 27 or 28
+func (s *clefService) SignTypedData(addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+	if addr.Address() != s.signer.Address() {
+		return nil, errors.New("unknown account")
+	}
+	return s.signer.SignTypedData(data)
+}
+
+func TestDialExternalSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", &clefService{signer: NewKeySigner(key)}))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	signers, err := DialExternalSigner(httpServer.URL)
+	require.NoError(t, err)
+	require.Len(t, signers, 1)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signers[0].Address())
+	requireSignedBy(t, signers[0], unsignedTx())
+
+	var data apitypes.TypedData
+	require.NoError(t, json.Unmarshal([]byte(mailTypedData), &data))
+	signature, err := signers[0].SignTypedData(data)
+	require.NoError(t, err, "typed data goes through account_signTypedData")
+	recovered, err := RecoverTypedData(data, signature)
+	require.NoError(t, err)
+	require.Equal(t, signers[0].Address(), recovered)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"eth-toy-client/accounts"
+	"eth-toy-client/core/siwe"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SignIn runs the Sign-In with Ethereum flow for signer and keeps the session token for later calls.
+// It is only needed when the server runs with SIWE_ENABLED.
+func (b *base) SignIn(ctx context.Context, signer accounts.Signer, chainID uint64) (*toytypes.SiweSessionResponse, error) {
+	nonce, err := call[toytypes.SiweNonceResponse](ctx, b, http.MethodGet, siwe.NoncePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(b.BaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	msg := (&siwe.Message{
+		Domain:    baseURL.Host,
+		Address:   signer.Address(),
+		Statement: "Sign in to the eth-toy-client dev servers",
+		URI:       b.BaseURL(),
+		Version:   "1",
+		ChainID:   chainID,
+		Nonce:     nonce.Nonce,
+		IssuedAt:  time.Now(),
+	}).String()
+	signature, err := signer.SignText([]byte(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := call[toytypes.SiweSessionResponse](ctx, b, http.MethodPost, siwe.VerifyPath, toytypes.SiweVerifyRequest{
+		Message:   msg,
+		Signature: hexutil.Encode(signature),
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.SetToken(session.Token)
+	return session, nil
+}
+
+func (b *base) SignOut(ctx context.Context) error {
+	_, err := call[struct{}](ctx, b, http.MethodPost, siwe.LogoutPath, nil)
+	b.SetToken("")
+	return err
+}
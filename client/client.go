@@ -0,0 +1,67 @@
+// Package client is a typed Go SDK for DevServer and LogServer. Every endpoint is a context-aware
+// method on top of httpapi.Client, so error envelopes and other non-2xx answers come back as *Error.
+package client
+
+import (
+	"context"
+	"eth-toy-client/config"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config configures the transport, see httpapi.ClientConfig
+type Config = httpapi.ClientConfig
+
+// Error is a non-2xx answer from a server
+type Error = httpapi.ResponseError
+
+// DefaultConfig points at the local port of the given server
+func DefaultConfig(name config.ServerName) Config {
+	return Config{
+		BaseURL:      strings.TrimSuffix(name.GetServerConfig().GetServerUrl(""), "/"),
+		Timeout:      30 * time.Second,
+		Retries:      2,
+		RetryBackoff: 200 * time.Millisecond,
+	}
+}
+
+// base holds what DevServerClient and LogServerClient share
+type base struct {
+	*httpapi.Client
+}
+
+func newBase(cfg Config) *base {
+	return &base{Client: httpapi.NewClient(cfg)}
+}
+
+// Ping returns the server's pong line
+func (b *base) Ping(ctx context.Context) (string, error) {
+	return httpapi.GetText(ctx, b.Client, "/ping")
+}
+
+// Health returns the liveness report of /healthz
+func (b *base) Health(ctx context.Context) (*toytypes.HealthResponse, error) {
+	return call[toytypes.HealthResponse](ctx, b, http.MethodGet, "/healthz", nil)
+}
+
+// Ready returns the readiness report of /readyz; an unready server answers with an Unavailable *Error
+func (b *base) Ready(ctx context.Context) (*toytypes.HealthResponse, error) {
+	return call[toytypes.HealthResponse](ctx, b, http.MethodGet, "/readyz", nil)
+}
+
+// LogLevel returns the server's current log level
+func (b *base) LogLevel(ctx context.Context) (*toytypes.LogLevelResponse, error) {
+	return call[toytypes.LogLevelResponse](ctx, b, http.MethodGet, "/loglevel", nil)
+}
+
+// SetLogLevel changes the server's log level to debug, info, warn or error
+func (b *base) SetLogLevel(ctx context.Context, level string) (*toytypes.LogLevelResponse, error) {
+	return call[toytypes.LogLevelResponse](ctx, b, http.MethodPut, "/loglevel", &toytypes.LogLevelRequest{Level: level})
+}
+
+func call[T any](ctx context.Context, b *base, method, path string, payload any) (*T, error) {
+	return httpapi.Do[T](ctx, b.Client, method, path, payload)
+}
@@ -0,0 +1,162 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"eth-toy-client/accounts"
+	"eth-toy-client/client"
+	"eth-toy-client/config"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/siwe"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/logserver/logserver"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[{"type":"function","name":"count","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`
+
+func testConfig(url string) client.Config {
+	return client.Config{BaseURL: url, Timeout: time.Second, Retries: 2, RetryBackoff: time.Millisecond}
+}
+
+func newLogServer(t *testing.T, wrap func(http.Handler) http.Handler) *client.LogServerClient {
+	var handler http.Handler = logserver.SetupRoutes(config.Servers.LogServer.GetServerConfig(), contract.NewRegistry())
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return client.NewLogServerClient(testConfig(server.URL))
+}
+
+func TestLogServerRegisterAndFetchContract(t *testing.T) {
+	logServer := newLogServer(t, nil)
+	ctx := context.Background()
+
+	pong, err := logServer.Ping(ctx)
+	require.NoError(t, err)
+	require.Contains(t, pong, "pong")
+
+	meta := contract.DeployedContractMetaJSON{
+		Alias:   "counter",
+		Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		TxHash:  "0x01",
+		ABI:     testABI,
+	}
+	res, err := logServer.RegisterContract(ctx, meta)
+	require.NoError(t, err)
+	require.Equal(t, "counter", res.Alias)
+
+	info, err := logServer.Contract(ctx, meta.Address)
+	require.NoError(t, err)
+	require.Equal(t, meta.Address, info.Address.Address)
+	require.Equal(t, testABI, info.ABI)
+
+	_, err = logServer.Contract(ctx, "0x0000000000000000000000000000000000000001")
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.Status)
+}
+
+func TestRetriesOnlyIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "warming up", http.StatusServiceUnavailable)
+			return
+		}
+		httpapi.WriteOK(w, &toytypes.NonceResponse{Nonce: 7})
+	}))
+	defer server.Close()
+	devServer := client.NewDevServerClient(testConfig(server.URL))
+
+	nonce, err := devServer.Nonce(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nonce.Nonce)
+	require.Equal(t, int32(3), calls.Load())
+
+	// A POST might have had side effects, so it is never repeated
+	calls.Store(0)
+	_, err = devServer.SendTx(context.Background(), toytypes.SignTxRequest{From: "alice", To: "bob"})
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.Status)
+	require.Equal(t, "warming up", apiErr.Message)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.Timeout, cfg.Retries = 20*time.Millisecond, 0
+	_, err := client.NewDevServerClient(cfg).ChainCapabilities(context.Background())
+	require.Error(t, err)
+	var apiErr *client.Error
+	require.False(t, errors.As(err, &apiErr), "a timeout is a transport error, not a server answer")
+}
+
+func TestDevServerPaths(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/accounts/{alias}/balance", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, &toytypes.BalanceResponse{Alias: r.PathValue("alias"), Wei: r.URL.Query().Get("block")})
+	})
+	mux.HandleFunc("POST /api/chain/mine", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, &toytypes.ChainHeadResponse{Block: 3})
+	})
+	mux.HandleFunc("DELETE /api/accounts/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.Fail(w, httpapi.ErrNotFound, "Account '"+r.PathValue("alias")+"' not found")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	devServer := client.NewDevServerClient(testConfig(server.URL))
+	ctx := context.Background()
+
+	balance, err := devServer.Balance(ctx, "alice", "0x10")
+	require.NoError(t, err)
+	require.Equal(t, "alice", balance.Alias)
+	require.Equal(t, "0x10", balance.Wei, "block query is forwarded")
+
+	head, err := devServer.Mine(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), head.Block)
+
+	err = devServer.DeleteAccount(ctx, "mallory")
+	require.ErrorIs(t, err, httpapi.ErrNotFound)
+	require.NotErrorIs(t, err, httpapi.ErrConflict)
+}
+
+func TestSignIn(t *testing.T) {
+	testAccounts := accounts.MustDerive(accounts.DefaultConfig())
+	alice := testAccounts[0]
+	auth := siwe.NewAuthenticator(siwe.Config{Allowlist: []common.Address{alice.Address}})
+	logServer := newLogServer(t, auth.Protect)
+	ctx := context.Background()
+
+	_, err := logServer.Contract(ctx, "0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.Status)
+
+	session, err := logServer.SignIn(ctx, alice.Signer, 1337)
+	require.NoError(t, err)
+	require.Equal(t, alice.Address.Hex(), session.Address)
+
+	_, err = logServer.Contract(ctx, "0x5FbDB2315678afecb367f032d93F642f64180aa3")
+	require.ErrorIs(t, err, httpapi.ErrNotFound, "signed in, the route itself answers")
+
+	require.NoError(t, logServer.SignOut(ctx))
+	_, err = logServer.SignIn(ctx, testAccounts[1].Signer, 1337)
+	require.ErrorIs(t, err, httpapi.ErrUnauthorized)
+}
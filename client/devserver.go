@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"eth-toy-client/config"
+	contract "eth-toy-client/core/contracts"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+	"net/url"
+)
+
+type DevServerClient struct {
+	*base
+}
+
+func NewDevServerClient(cfg Config) *DevServerClient {
+	return &DevServerClient{base: newBase(cfg)}
+}
+
+// DefaultDevServerClient talks to the DevServer on its local port
+func DefaultDevServerClient() *DevServerClient {
+	return NewDevServerClient(DefaultConfig(config.Servers.DevServer))
+}
+
+// 🧾 Node and accounts
+
+func (c *DevServerClient) Info(ctx context.Context) (*toytypes.DevInfoResponse, error) {
+	return call[toytypes.DevInfoResponse](ctx, c.base, http.MethodGet, "/info", nil)
+}
+
+func (c *DevServerClient) DevAccount(ctx context.Context) (*toytypes.DevAccountResponse, error) {
+	return call[toytypes.DevAccountResponse](ctx, c.base, http.MethodGet, "/dev-account", nil)
+}
+
+// TestAccounts lists the named accounts including private keys where the server exposes them
+func (c *DevServerClient) TestAccounts(ctx context.Context) ([]toytypes.TestAccountResponse, error) {
+	list, err := call[[]toytypes.TestAccountResponse](ctx, c.base, http.MethodGet, "/accounts", nil)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (c *DevServerClient) Accounts(ctx context.Context) ([]toytypes.AccountInfo, error) {
+	list, err := call[[]toytypes.AccountInfo](ctx, c.base, http.MethodGet, "/api/accounts", nil)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (c *DevServerClient) CreateAccount(ctx context.Context, req toytypes.CreateAccountRequest) (*toytypes.CreateAccountResponse, error) {
+	return call[toytypes.CreateAccountResponse](ctx, c.base, http.MethodPost, "/api/accounts", req)
+}
+
+func (c *DevServerClient) DeleteAccount(ctx context.Context, alias string) error {
+	_, err := call[toytypes.AccountInfo](ctx, c.base, http.MethodDelete, accountPath(alias, ""), nil)
+	return err
+}
+
+func (c *DevServerClient) FundAccount(ctx context.Context, alias string, req toytypes.FundAccountRequest) (*toytypes.FundAccountResponse, error) {
+	return call[toytypes.FundAccountResponse](ctx, c.base, http.MethodPost, accountPath(alias, "/fund"), req)
+}
+
+// Balance reads the ETH balance of an alias or address; an empty block means latest
+func (c *DevServerClient) Balance(ctx context.Context, alias, block string) (*toytypes.BalanceResponse, error) {
+	return call[toytypes.BalanceResponse](ctx, c.base, http.MethodGet, accountPath(alias, "/balance")+blockQuery(block), nil)
+}
+
+func (c *DevServerClient) TokenBalances(ctx context.Context, alias, block string) (*toytypes.TokenBalancesResponse, error) {
+	return call[toytypes.TokenBalancesResponse](ctx, c.base, http.MethodGet, accountPath(alias, "/tokens")+blockQuery(block), nil)
+}
+
+func (c *DevServerClient) Nonce(ctx context.Context, alias string) (*toytypes.NonceResponse, error) {
+	return call[toytypes.NonceResponse](ctx, c.base, http.MethodGet, accountPath(alias, "/nonce"), nil)
+}
+
+// ParseAmount converts "1.5 ether", "20 gwei" or "100 USDC" using the server's registered tokens
+func (c *DevServerClient) ParseAmount(ctx context.Context, amount string) (*toytypes.ParseAmountResponse, error) {
+	return call[toytypes.ParseAmountResponse](ctx, c.base, http.MethodGet, "/api/units/parse?amount="+url.QueryEscape(amount), nil)
+}
+
+func (c *DevServerClient) PendingNonce(ctx context.Context, alias string) (*toytypes.PendingNonceResponse, error) {
+	return call[toytypes.PendingNonceResponse](ctx, c.base, http.MethodPost, "/api/pending-nonce", toytypes.PendingNonceRequest{Alias: alias})
+}
+
+// 📤 Transactions
+
+func (c *DevServerClient) SignTx(ctx context.Context, req toytypes.SignTxRequest) (*toytypes.SignTxAPIResponse, error) {
+	return call[toytypes.SignTxAPIResponse](ctx, c.base, http.MethodPost, "/api/sign-tx", req)
+}
+
+func (c *DevServerClient) SendTx(ctx context.Context, req toytypes.SignTxRequest) (*toytypes.SendTxAPIResponse, error) {
+	return call[toytypes.SendTxAPIResponse](ctx, c.base, http.MethodPost, "/api/send-tx", req)
+}
+
+func (c *DevServerClient) SendBatch(ctx context.Context, req toytypes.SendBatchRequest) (*toytypes.SendBatchResponse, error) {
+	return call[toytypes.SendBatchResponse](ctx, c.base, http.MethodPost, "/api/send-batch", req)
+}
+
+func (c *DevServerClient) SpeedUp(ctx context.Context, txHash string, req toytypes.ReplaceTxRequest) (*toytypes.ReplaceTxResponse, error) {
+	return call[toytypes.ReplaceTxResponse](ctx, c.base, http.MethodPost, txPath(txHash, "/speedup"), req)
+}
+
+func (c *DevServerClient) Cancel(ctx context.Context, txHash string, req toytypes.ReplaceTxRequest) (*toytypes.ReplaceTxResponse, error) {
+	return call[toytypes.ReplaceTxResponse](ctx, c.base, http.MethodPost, txPath(txHash, "/cancel"), req)
+}
+
+func (c *DevServerClient) Txs(ctx context.Context) ([]toytypes.TxRecord, error) {
+	list, err := call[[]toytypes.TxRecord](ctx, c.base, http.MethodGet, "/api/txs", nil)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (c *DevServerClient) Receipt(ctx context.Context, txHash string) (*toytypes.TxReceiptResponse, error) {
+	return call[toytypes.TxReceiptResponse](ctx, c.base, http.MethodGet, txPath(txHash, "/receipt"), nil)
+}
+
+func (c *DevServerClient) Trace(ctx context.Context, txHash string) (*toytypes.TxTraceResponse, error) {
+	return call[toytypes.TxTraceResponse](ctx, c.base, http.MethodGet, txPath(txHash, "/trace"), nil)
+}
+
+func (c *DevServerClient) StateDiff(ctx context.Context, txHash string) (*toytypes.StateDiffResponse, error) {
+	return call[toytypes.StateDiffResponse](ctx, c.base, http.MethodGet, txPath(txHash, "/state-diff"), nil)
+}
+
+func (c *DevServerClient) Simulate(ctx context.Context, req toytypes.SimulateTxRequest) (*toytypes.SimulateTxResponse, error) {
+	return call[toytypes.SimulateTxResponse](ctx, c.base, http.MethodPost, "/api/simulate", req)
+}
+
+// ✍️ Signatures
+
+func (c *DevServerClient) SignMessage(ctx context.Context, req toytypes.SignMessageRequest) (*toytypes.SignatureResponse, error) {
+	return call[toytypes.SignatureResponse](ctx, c.base, http.MethodPost, "/api/sign-message", req)
+}
+
+func (c *DevServerClient) SignTypedData(ctx context.Context, req toytypes.SignTypedDataRequest) (*toytypes.SignatureResponse, error) {
+	return call[toytypes.SignatureResponse](ctx, c.base, http.MethodPost, "/api/sign-typed-data", req)
+}
+
+func (c *DevServerClient) VerifySignature(ctx context.Context, req toytypes.VerifySignatureRequest) (*toytypes.VerifySignatureResponse, error) {
+	return call[toytypes.VerifySignatureResponse](ctx, c.base, http.MethodPost, "/api/verify-signature", req)
+}
+
+// 📦 Contracts
+
+func (c *DevServerClient) DeployContract(ctx context.Context, req toytypes.DeployContractRequest) (*toytypes.ContractDeploymentResponse, error) {
+	return call[toytypes.ContractDeploymentResponse](ctx, c.base, http.MethodPost, "/api/deploy-contract", req)
+}
+
+func (c *DevServerClient) RegisterAlias(ctx context.Context, meta contract.DeployedContractMetaJSON) (*toytypes.AliasRegisterResponse, error) {
+	return call[toytypes.AliasRegisterResponse](ctx, c.base, http.MethodPost, "/api/register-alias", meta)
+}
+
+func (c *DevServerClient) Contracts(ctx context.Context) ([]contract.DeployedContractInfo, error) {
+	list, err := call[[]contract.DeployedContractInfo](ctx, c.base, http.MethodGet, "/api/contracts", nil)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (c *DevServerClient) Contract(ctx context.Context, address string) (*contract.DeployedContractInfo, error) {
+	return call[contract.DeployedContractInfo](ctx, c.base, http.MethodGet, "/api/contracts/"+url.PathEscape(address), nil)
+}
+
+// ⛓️ Chain control
+
+func (c *DevServerClient) ChainCapabilities(ctx context.Context) (*toytypes.ChainCapabilities, error) {
+	return call[toytypes.ChainCapabilities](ctx, c.base, http.MethodGet, "/api/chain/capabilities", nil)
+}
+
+func (c *DevServerClient) Snapshot(ctx context.Context) (*toytypes.ChainSnapshotResponse, error) {
+	return call[toytypes.ChainSnapshotResponse](ctx, c.base, http.MethodPost, "/api/chain/snapshot", nil)
+}
+
+func (c *DevServerClient) Revert(ctx context.Context, snapshotID string) (*toytypes.ChainHeadResponse, error) {
+	return call[toytypes.ChainHeadResponse](ctx, c.base, http.MethodPost, "/api/chain/revert", toytypes.ChainRevertRequest{ID: snapshotID})
+}
+
+func (c *DevServerClient) Mine(ctx context.Context, blocks uint64) (*toytypes.ChainHeadResponse, error) {
+	return call[toytypes.ChainHeadResponse](ctx, c.base, http.MethodPost, "/api/chain/mine", toytypes.ChainMineRequest{Blocks: blocks})
+}
+
+func (c *DevServerClient) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (*toytypes.ChainHeadResponse, error) {
+	return call[toytypes.ChainHeadResponse](ctx, c.base, http.MethodPost, "/api/chain/next-timestamp", toytypes.ChainTimeRequest{Timestamp: timestamp})
+}
+
+func (c *DevServerClient) IncreaseTime(ctx context.Context, seconds uint64) (*toytypes.ChainHeadResponse, error) {
+	return call[toytypes.ChainHeadResponse](ctx, c.base, http.MethodPost, "/api/chain/increase-time", toytypes.ChainTimeRequest{Seconds: seconds})
+}
+
+func accountPath(alias, suffix string) string {
+	return "/api/accounts/" + url.PathEscape(alias) + suffix
+}
+
+func txPath(txHash, suffix string) string {
+	return "/api/tx/" + url.PathEscape(txHash) + suffix
+}
+
+func blockQuery(block string) string {
+	if block == "" {
+		return ""
+	}
+	return "?block=" + url.QueryEscape(block)
+}
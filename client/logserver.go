@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"eth-toy-client/config"
+	contract "eth-toy-client/core/contracts"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+	"net/url"
+)
+
+type LogServerClient struct {
+	*base
+}
+
+func NewLogServerClient(cfg Config) *LogServerClient {
+	return &LogServerClient{base: newBase(cfg)}
+}
+
+// DefaultLogServerClient talks to the LogServer on its local port
+func DefaultLogServerClient() *LogServerClient {
+	return NewLogServerClient(DefaultConfig(config.Servers.LogServer))
+}
+
+func (c *LogServerClient) RegisterContract(ctx context.Context, meta contract.DeployedContractMetaJSON) (*toytypes.AliasRegisterResponse, error) {
+	return call[toytypes.AliasRegisterResponse](ctx, c.base, http.MethodPost, "/api/register-contract", meta)
+}
+
+func (c *LogServerClient) Contract(ctx context.Context, address string) (*contract.DeployedContractInfo, error) {
+	return call[contract.DeployedContractInfo](ctx, c.base, http.MethodGet, "/api/contract/"+url.PathEscape(address), nil)
+}
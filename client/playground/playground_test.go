@@ -1,15 +1,13 @@
 package playground
 
 import (
-	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
-	"eth-toy-client/core/consts"
 	contract "eth-toy-client/core/contracts"
 	"eth-toy-client/core/devutil"
 	"eth-toy-client/core/httpapi"
-	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/tracing"
 	toytypes "eth-toy-client/core/types"
 	"eth-toy-client/sol/out/counter"
 	"fmt"
@@ -19,43 +17,31 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/require"
-	"io"
 	"log"
 	"math/big"
-	"net/http"
 	"strings"
 	"testing"
 	"time"
 )
 
 func GetInfoResponse(t *testing.T, urls devutil.Urls) devutil.InfoResponse {
-	resp, err := http.Get(urls.InfoURL)
+	info, err := urls.DevServerClient().Info(context.Background())
 	require.NoError(t, err)
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	require.NoError(t, err)
-
-	var info devutil.InfoResponse
-	err = json.Unmarshal(body, &info)
-	require.NoError(t, err)
-	return info
+	return *info
 }
 
 func GetAccounts(t *testing.T, urls devutil.Urls) map[string]devutil.ClientTestAccount {
-	resp, err := http.Get(urls.AccountsURL)
-	require.NoError(t, err)
-	defer resp.Body.Close()
-
-	var accounts []devutil.ClientTestAccount
-
-	err = json.NewDecoder(resp.Body).Decode(&accounts)
+	accounts, err := urls.DevServerClient().TestAccounts(context.Background())
 	require.NoError(t, err)
 
 	accountsMap := make(map[string]devutil.ClientTestAccount)
 	for _, acc := range accounts {
-		acc.CommonAddress = common.HexToAddress(acc.Address)
-		accountsMap[acc.Name] = acc
+		accountsMap[acc.Name] = devutil.ClientTestAccount{
+			Name:          acc.Name,
+			Address:       acc.Address,
+			PrivateKey:    acc.PrivateKey,
+			CommonAddress: common.HexToAddress(acc.Address),
+		}
 	}
 	return accountsMap
 }
@@ -263,16 +249,11 @@ func TestSignTxFromAlice(t *testing.T) {
 		"value": "10000000000000000", // 0.01 ETH
 	}
 
-	body, _ := json.Marshal(payload)
-	resp, err := http.Post(urls.ServerURL+"/sign-tx", "application/json", bytes.NewReader(body))
-	require.NoError(t, err)
-	defer resp.Body.Close()
-
-	var result struct {
+	result, apiErr, err := httpapi.PostWithAPIResponse[struct {
 		Tx string `json:"tx"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	}](urls.ServerURL+"/sign-tx", payload)
 	require.NoError(t, err)
+	require.Nil(t, apiErr)
 
 	t.Logf("🖋️ Signed Tx: %s", result.Tx)
 	require.True(t, strings.HasPrefix(result.Tx, "0x"), "Expected hex-encoded tx")
@@ -293,17 +274,11 @@ func TestSendTxViaDevServer(t *testing.T) {
 		"from": alice.Name,
 		"to":   bob.Name,
 	}
-	data, _ := json.Marshal(payload)
-	resp, err := http.Post(urls.ServerURL+"/send-tx", "application/json", bytes.NewReader(data))
-	require.NoError(t, err)
-	defer resp.Body.Close()
-	require.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var txResp struct {
+	txResp, apiErr, err := httpapi.PostWithAPIResponse[struct {
 		TxHash string `json:"txHash"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&txResp)
+	}](urls.ServerURL+"/send-tx", payload)
 	require.NoError(t, err)
+	require.Nil(t, apiErr)
 	t.Logf("📤 Sent tx from alice to bob via dev server: %s", txResp.TxHash)
 
 	// ⏳ Wait for mining
@@ -328,7 +303,7 @@ func TestSignTxViaDevServerAPI(t *testing.T) {
 	req := map[string]string{
 		"from":  alice.Name,
 		"to":    bob.Name,
-		"value": consts.ETH.Point01.String(), // 0.01 ETH
+		"value": "0.01 ether",
 	}
 
 	apiResp, apiErr, err := httpapi.PostWithAPIResponse[toytypes.SignTxAPIResponse](urls.ServerURL+"/api/sign-tx", req)
@@ -388,26 +363,25 @@ func TestRetrieveBlockContentByNumber(t *testing.T) {
 	blockNumber := big.NewInt(8)
 	block, err := client.BlockByNumber(context.Background(), blockNumber)
 	if err != nil {
-		logutil.Errorf("❌ Failed to get block by number %d: %v", blockNumber.Int64(), err)
-		t.FailNow()
+		t.Fatalf("❌ Failed to get block by number %d: %v", blockNumber.Int64(), err)
 	}
 
-	logutil.Infof("📦 Block #%d", block.Number().Uint64())
-	logutil.Infof("🔗 Hash       : %s", block.Hash().Hex())
-	logutil.Infof("🔗 Parent Hash: %s", block.ParentHash().Hex())
-	logutil.Infof("⛽️ Gas Used   : %d / %d", block.GasUsed(), block.GasLimit())
-	logutil.Infof("💥 Transactions: %d", len(block.Transactions()))
+	t.Logf("📦 Block #%d", block.Number().Uint64())
+	t.Logf("🔗 Hash       : %s", block.Hash().Hex())
+	t.Logf("🔗 Parent Hash: %s", block.ParentHash().Hex())
+	t.Logf("⛽️ Gas Used   : %d / %d", block.GasUsed(), block.GasLimit())
+	t.Logf("💥 Transactions: %d", len(block.Transactions()))
 
 	for i, tx := range block.Transactions() {
-		logutil.Infof("  ➤ Tx #%d: %s", i, tx.Hash().Hex())
+		t.Logf("  ➤ Tx #%d: %s", i, tx.Hash().Hex())
 
 		if tx.To() == nil {
-			logutil.Infof("     📦 Contract creation")
+			t.Logf("     📦 Contract creation")
 		} else {
-			logutil.Infof("     📬 To: %s", tx.To().Hex())
+			t.Logf("     📬 To: %s", tx.To().Hex())
 		}
 
-		logutil.Infof("     🔢 Nonce: %d | ⛽ Gas: %d | 💰 Value: %s", tx.Nonce(), tx.Gas(), tx.Value().String())
+		t.Logf("     🔢 Nonce: %d | ⛽ Gas: %d | 💰 Value: %s", tx.Nonce(), tx.Gas(), tx.Value().String())
 	}
 }
 
@@ -418,35 +392,26 @@ func TestDebugTraceTransaction(t *testing.T) {
 
 	txHash := common.HexToHash("0xfe196a1de723b21a066c5d0062d61114059b726b90c835318dfd141bbb9713ed")
 
-	// ⚙️ Manual raw RPC call since `debug_traceTransaction` is not part of ethclient
-	var result map[string]interface{}
-	rpcClient := client.Client() // This gives us *rpc.Client
+	// ⚙️ The tracer runs debug_traceTransaction with the callTracer for us
+	tracer := tracing.NewTracer(client.Client(), contract.NewRegistry())
 
-	logutil.Infof("🔍 Tracing transaction: %s", txHash.Hex())
+	t.Logf("🔍 Tracing transaction: %s", txHash.Hex())
 
-	err := rpcClient.CallContext(
-		context.Background(),
-		&result,
-		"debug_traceTransaction",
-		txHash,
-		map[string]interface{}{}, // default config
-	)
+	trace, err := tracer.TraceTransaction(context.Background(), txHash)
 	if err != nil {
-		logutil.Errorf("❌ Failed to trace transaction: %v", err)
-		t.FailNow()
+		t.Fatalf("❌ Failed to trace transaction: %v", err)
 	}
 
 	// 🧠 Print high-level info
-	if output, ok := result["output"]; ok {
-		logutil.Infof("🧾 Output: %v", output)
-	}
-	if failed, ok := result["failed"]; ok {
-		logutil.Infof("💥 Failed: %v", failed)
+	t.Logf("🧾 Output: %v", trace.Root.Output)
+	if trace.Root.Error != "" {
+		t.Logf("💥 Failed: %v", trace.Root.Error)
 	}
+	t.Logf("🪆 Internal transactions: %d", trace.InternalTransactions)
 
 	// 🧬 Optional: full dump
-	traceBytes, _ := json.MarshalIndent(result, "", "  ")
-	logutil.Infof("🧬 Full Trace:\n%s", string(traceBytes))
+	traceBytes, _ := json.MarshalIndent(trace, "", "  ")
+	t.Logf("🧬 Full Trace:\n%s", string(traceBytes))
 }
 
 func TestTraceFailedDeployment(t *testing.T) {
@@ -460,7 +425,7 @@ func TestTraceFailedDeployment(t *testing.T) {
 	var result map[string]interface{}
 	rpcClient := client.Client()
 
-	logutil.Infof("🔍 Tracing transaction: %s", txHash.Hex())
+	t.Logf("🔍 Tracing transaction: %s", txHash.Hex())
 
 	err := rpcClient.CallContext(
 		context.Background(),
@@ -470,17 +435,16 @@ func TestTraceFailedDeployment(t *testing.T) {
 		map[string]interface{}{}, // default config
 	)
 	if err != nil {
-		logutil.Errorf("❌ Trace failed: %v", err)
-		t.FailNow()
+		t.Fatalf("❌ Trace failed: %v", err)
 	}
 
 	// 🧾 Inspect logs emitted
 	if logs, ok := result["structLogs"]; ok {
-		logutil.Infof("📜 structLogs present with %d entries", len(logs.([]interface{})))
+		t.Logf("📜 structLogs present with %d entries", len(logs.([]interface{})))
 	}
 
 	traceBytes, _ := json.MarshalIndent(result, "", "  ")
-	logutil.Infof("🧬 Full Trace:\n%s", string(traceBytes))
+	t.Logf("🧬 Full Trace:\n%s", string(traceBytes))
 }
 
 func TestGetDeploymentLogs(t *testing.T) {
@@ -511,13 +475,13 @@ func TestGetDeploymentLogs(t *testing.T) {
 
 	// Third: Display logs
 	if len(logs) == 0 {
-		logutil.Warnf("🫥 No logs found in block #%d", blockNum.Uint64())
+		t.Logf("🫥 No logs found in block #%d", blockNum.Uint64())
 	} else {
-		logutil.Infof("📝 Found %d logs in block #%d", len(logs), blockNum.Uint64())
+		t.Logf("📝 Found %d logs in block #%d", len(logs), blockNum.Uint64())
 		for i, logEntry := range logs {
-			logutil.Infof("🔹 Log %d: Contract=%s", i, logEntry.Address.Hex())
-			logutil.Infof("   ➤ Topics: %v", logEntry.Topics)
-			logutil.Infof("   ➤ Data  : %x", logEntry.Data)
+			t.Logf("🔹 Log %d: Contract=%s", i, logEntry.Address.Hex())
+			t.Logf("   ➤ Topics: %v", logEntry.Topics)
+			t.Logf("   ➤ Data  : %x", logEntry.Data)
 		}
 	}
 }
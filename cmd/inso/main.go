@@ -0,0 +1,46 @@
+// Command inso hosts DevServer and LogServer in one process, sharing one node connection, contract
+// registry and log broadcaster. The services config key selects which run, so
+//
+//	inso                           runs both
+//	inso -services devserver       runs only DevServer, e.g. next to `inso -services logserver`
+//	inso -config inso.yaml         takes the selection, ports and node URLs from a file
+//
+// Every other setting of the config package can be given as a flag too, see `inso -h`.
+package main
+
+import (
+	"errors"
+	"eth-toy-client/config"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/servers/devserver/devserver"
+	"eth-toy-client/servers/logserver/logserver"
+	"eth-toy-client/servers/servers"
+	"flag"
+	"os"
+)
+
+var logger = logutil.For("inso")
+
+var services = map[config.ServerName]func() servers.MicroService{
+	config.Servers.DevServer: func() servers.MicroService { return &devserver.DevServer{} },
+	config.Servers.LogServer: func() servers.MicroService { return &logserver.LogServer{} },
+}
+
+func main() {
+	cfg, err := config.Init(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logutil.Fatal(logger, "❌ Invalid config", "error", err)
+	}
+
+	var selected []servers.MicroService
+	for _, name := range cfg.ServerNames() {
+		selected = append(selected, services[name]())
+	}
+	logger.Info("🚀 inso starting", "services", cfg.ServerNames())
+	if err := servers.RunUntilSignal(selected...); err != nil {
+		logutil.Fatal(logger, "❌ inso stopped", "error", err)
+	}
+}
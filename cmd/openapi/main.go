@@ -0,0 +1,36 @@
+// Command openapi writes the servers' OpenAPI documents for the swagger package to embed:
+//
+//	go generate ./swagger
+package main
+
+import (
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/openapi"
+	"eth-toy-client/servers/devserver/devserver"
+	"eth-toy-client/servers/logserver/logserver"
+	"flag"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	out := flag.String("out", "swagger", "directory to write the documents to")
+	flag.Parse()
+
+	logger := logutil.For("openapi")
+	docs := map[string]*openapi.Document{
+		devserver.SpecFile: devserver.OpenAPI(),
+		logserver.SpecFile: logserver.OpenAPI(),
+	}
+	for name, doc := range docs {
+		data, err := openapi.Marshal(doc)
+		if err != nil {
+			logutil.Fatal(logger, "❌ Failed to encode document", "file", name, "error", err)
+		}
+		path := filepath.Join(*out, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			logutil.Fatal(logger, "❌ Failed to write document", "file", path, "error", err)
+		}
+		logger.Info("📝 Wrote OpenAPI document", "file", path, "paths", len(doc.Paths))
+	}
+}
@@ -0,0 +1,530 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"eth-toy-client/accounts"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/units"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"eth-toy-client/core/siwe"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the effective configuration of the node connection and every server. It is built in
+// layers: Default, then a YAML/TOML/JSON file, then env vars, then flags.
+//
+// Each setting declares its env var and flag in struct tags; settings tagged secret are redacted
+// by Redacted, those tagged secret:"url" only lose their credentials, path and query.
+type Config struct {
+	Services []string       `yaml:"services" json:"services" env:"SERVICES" flag:"services"` // servers the inso binary hosts
+	Node     NodeConfig     `yaml:"node" json:"node"`
+	Servers  ServersConfig  `yaml:"servers" json:"servers"`
+	Accounts AccountsConfig `yaml:"accounts" json:"accounts"`
+	Funding  FundingConfig  `yaml:"funding" json:"funding"`
+	Gas      GasConfig      `yaml:"gas" json:"gas"`
+	Storage  StorageConfig  `yaml:"storage" json:"storage"`
+	Log      LogConfig      `yaml:"log" json:"log"`
+	RPCProxy RPCProxyConfig `yaml:"rpcProxy" json:"rpcProxy"`
+	SIWE     SIWEConfig     `yaml:"siwe" json:"siwe" env:"SIWE" flag:"siwe"`
+}
+
+// NodeConfig is where the node is reached. Hosted RPC URLs often embed an API key in the path or
+// the credentials, so both are redacted.
+type NodeConfig struct {
+	HTTPURL string `yaml:"httpUrl" json:"httpUrl" env:"NODE_HTTP_URL" flag:"node.http-url" secret:"url"`
+	WSURL   string `yaml:"wsUrl" json:"wsUrl" env:"NODE_WS_URL" flag:"node.ws-url" secret:"url"`
+}
+
+type ServersConfig struct {
+	DevServer ServiceConfig `yaml:"devServer" json:"devServer" env:"DEVSERVER" flag:"devserver"`
+	LogServer ServiceConfig `yaml:"logServer" json:"logServer" env:"LOGSERVER" flag:"logserver"`
+}
+
+// ServiceConfig is where a server listens and the base URL clients reach it at
+type ServiceConfig struct {
+	Port string `yaml:"port" json:"port" env:"PORT" flag:"port"`
+	URL  string `yaml:"url" json:"url" env:"URL" flag:"url"` // defaults to http://localhost:<port>
+}
+
+// AccountsConfig mirrors accounts.Config; the env vars are the historical TEST_* ones
+type AccountsConfig struct {
+	Mnemonic         string   `yaml:"mnemonic" json:"mnemonic" env:"TEST_MNEMONIC" flag:"accounts.mnemonic" secret:"true"`
+	Passphrase       string   `yaml:"passphrase" json:"passphrase" env:"TEST_MNEMONIC_PASSPHRASE" flag:"accounts.passphrase" secret:"true"`
+	BasePath         string   `yaml:"basePath" json:"basePath" env:"TEST_ACCOUNT_PATH" flag:"accounts.path"`
+	Count            int      `yaml:"count" json:"count" env:"TEST_ACCOUNT_COUNT" flag:"accounts.count"` // 0 derives one account per name
+	Names            []string `yaml:"names" json:"names" env:"TEST_ACCOUNT_NAMES" flag:"accounts.names"`
+	Signer           string   `yaml:"signer" json:"signer" env:"TEST_SIGNER" flag:"accounts.signer"`
+	KeystorePassword string   `yaml:"keystorePassword" json:"keystorePassword" env:"TEST_KEYSTORE_PASSWORD" flag:"accounts.keystore-password" secret:"true"`
+	ExternalSigner   string   `yaml:"externalSigner" json:"externalSigner" env:"TEST_EXTERNAL_SIGNER" flag:"accounts.external-signer"`
+	ExposeKeys       bool     `yaml:"exposeKeys" json:"exposeKeys" env:"TEST_EXPOSE_KEYS" flag:"accounts.expose-keys"`
+}
+
+// FundingConfig is what DevServer sends every test account at startup and by default on /fund
+type FundingConfig struct {
+	Amount string `yaml:"amount" json:"amount" env:"FUNDING_AMOUNT" flag:"funding.amount"`
+}
+
+// GasConfig is the fee policy of the txs DevServer builds. Fees accept units, e.g. "2 gwei".
+type GasConfig struct {
+	TipCap string `yaml:"tipCap" json:"tipCap" env:"GAS_TIP_CAP" flag:"gas.tip-cap"`
+	FeeCap string `yaml:"feeCap" json:"feeCap" env:"GAS_FEE_CAP" flag:"gas.fee-cap"`
+	Limit  uint64 `yaml:"limit" json:"limit" env:"GAS_LIMIT" flag:"gas.limit"`
+}
+
+type StorageConfig struct {
+	KeystoreDir string `yaml:"keystoreDir" json:"keystoreDir" env:"TEST_KEYSTORE_DIR" flag:"storage.keystore-dir"`
+}
+
+// LogConfig sets the initial log output; the level can change at runtime through /loglevel
+type LogConfig struct {
+	Level  string `yaml:"level" json:"level" env:"LOG_LEVEL" flag:"log.level"`     // debug, info, warn or error
+	Format string `yaml:"format" json:"format" env:"LOG_FORMAT" flag:"log.format"` // console or json
+}
+
+// RPCProxyConfig is DevServer's JSON-RPC proxy on /rpc
+type RPCProxyConfig struct {
+	RecordFile string `yaml:"recordFile" json:"recordFile" env:"RPC_RECORD_FILE" flag:"rpc-proxy.record-file"` // JSON Lines recording of the proxied calls, off when empty
+}
+
+// SIWEConfig turns on Sign-In with Ethereum in front of every server
+type SIWEConfig struct {
+	Enabled    bool     `yaml:"enabled" json:"enabled" env:"ENABLED" flag:"enabled"`
+	Domain     string   `yaml:"domain" json:"domain" env:"DOMAIN" flag:"domain"`             // expected message domain, defaults to the request's Host
+	ChainID    uint64   `yaml:"chainId" json:"chainId" env:"CHAIN_ID" flag:"chain-id"`       // 0 accepts any
+	Allowlist  []string `yaml:"allowlist" json:"allowlist" env:"ALLOWLIST" flag:"allowlist"` // empty allows any address that proves ownership
+	SessionTTL string   `yaml:"sessionTtl" json:"sessionTtl" env:"SESSION_TTL" flag:"session-ttl"`
+}
+
+// ConfigFileEnv names the env var holding the config file path; the -config flag wins over it
+const ConfigFileEnv = "CONFIG_FILE"
+
+const redacted = "******"
+
+func Default() *Config {
+	return &Config{
+		Services: []string{"devserver", "logserver"},
+		Node: NodeConfig{
+			HTTPURL: "http://localhost:8565",
+			WSURL:   "ws://localhost:8546",
+		},
+		Servers: ServersConfig{
+			DevServer: ServiceConfig{Port: "8575"},
+			LogServer: ServiceConfig{Port: "9585"},
+		},
+		Accounts: AccountsConfig{
+			Mnemonic:   accounts.DefaultMnemonic,
+			BasePath:   accounts.DefaultBasePath,
+			Names:      accounts.DefaultNames,
+			Signer:     accounts.SignerMemory,
+			ExposeKeys: true,
+		},
+		Funding: FundingConfig{Amount: "1 ether"},
+		Gas: GasConfig{
+			TipCap: "1 wei",
+			FeeCap: "1 gwei",
+			Limit:  3_000_000,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: logutil.FormatConsole,
+		},
+		SIWE: SIWEConfig{SessionTTL: "1h"},
+	}
+}
+
+// Load builds the configuration from the defaults, the file named by -config or CONFIG_FILE, the
+// environment and finally the flags in args. Pass nil args to skip flag parsing.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(ConfigFileEnv), "YAML, TOML or JSON config file")
+	byFlag := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byFlag[s.flag] = s
+		fs.Var(&flagValue{isBool: s.value.Kind() == reflect.Bool}, s.flag, fmt.Sprintf("overrides %s (env %s)", s.path, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range settings {
+		if raw := os.Getenv(s.env); raw != "" {
+			if err := s.set(raw); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		if s, ok := byFlag[f.Name]; ok && flagErr == nil {
+			if err := s.set(f.Value.String()); err != nil {
+				flagErr = fmt.Errorf("invalid -%s: %w", f.Name, err)
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	cfg.fillDerived()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// flagValue holds a flag's raw value until the file and env layers are applied
+type flagValue struct {
+	raw    string
+	isBool bool
+}
+
+func (f *flagValue) String() string     { return f.raw }
+func (f *flagValue) Set(s string) error { f.raw = s; return nil }
+func (f *flagValue) IsBoolFlag() bool   { return f.isBool }
+
+func (cfg *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	case ".toml":
+		// The TOML document is re-encoded as YAML so both formats share one decoder
+		var table map[string]any
+		if err := toml.Unmarshal(data, &table); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(table); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported config file type '%s'", filepath.Ext(path))
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// fillDerived defaults the server URLs to their local ports
+func (cfg *Config) fillDerived() {
+	for _, service := range []*ServiceConfig{&cfg.Servers.DevServer, &cfg.Servers.LogServer} {
+		if service.URL == "" {
+			service.URL = "http://localhost:" + service.Port
+		}
+		service.URL = strings.TrimSuffix(service.URL, "/")
+	}
+}
+
+// Validate checks URLs, ports, amounts and the signer mode
+func (cfg *Config) Validate() error {
+	if len(cfg.Services) == 0 {
+		return fmt.Errorf("services: at least one server is required")
+	}
+	for _, name := range cfg.Services {
+		if _, ok := LookupServerName(name); !ok {
+			return fmt.Errorf("services: unknown server '%s'", name)
+		}
+	}
+	if err := checkURL("node.httpUrl", cfg.Node.HTTPURL, "http", "https"); err != nil {
+		return err
+	}
+	if err := checkURL("node.wsUrl", cfg.Node.WSURL, "ws", "wss"); err != nil {
+		return err
+	}
+	services := map[string]ServiceConfig{"devServer": cfg.Servers.DevServer, "logServer": cfg.Servers.LogServer}
+	for name, service := range services {
+		if port, err := strconv.Atoi(service.Port); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("servers.%s.port: invalid port '%s'", name, service.Port)
+		}
+		if err := checkURL("servers."+name+".url", service.URL, "http", "https"); err != nil {
+			return err
+		}
+	}
+
+	amounts := map[string]string{"funding.amount": cfg.Funding.Amount, "gas.tipCap": cfg.Gas.TipCap, "gas.feeCap": cfg.Gas.FeeCap}
+	for name, amount := range amounts {
+		if _, err := units.ParseAmount(amount); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if units.MustParseAmount(cfg.Gas.TipCap).Cmp(units.MustParseAmount(cfg.Gas.FeeCap)) > 0 {
+		return fmt.Errorf("gas.tipCap must not exceed gas.feeCap")
+	}
+	if cfg.Gas.Limit == 0 {
+		return fmt.Errorf("gas.limit must be positive")
+	}
+
+	switch cfg.Accounts.Signer {
+	case "", accounts.SignerMemory, accounts.SignerKeystore, accounts.SignerExternal:
+	default:
+		return fmt.Errorf("accounts.signer: unknown signer '%s'", cfg.Accounts.Signer)
+	}
+	if cfg.Accounts.Count < 0 {
+		return fmt.Errorf("accounts.count must not be negative")
+	}
+
+	if _, err := logutil.ParseLevel(cfg.Log.Level); err != nil {
+		return fmt.Errorf("log.level: %w", err)
+	}
+	if cfg.Log.Format != logutil.FormatConsole && cfg.Log.Format != logutil.FormatJSON {
+		return fmt.Errorf("log.format: unknown format '%s'", cfg.Log.Format)
+	}
+
+	for _, address := range cfg.SIWE.Allowlist {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("siwe.allowlist: invalid address '%s'", address)
+		}
+	}
+	if ttl, err := time.ParseDuration(cfg.SIWE.SessionTTL); err != nil || ttl <= 0 {
+		return fmt.Errorf("siwe.sessionTtl: invalid duration '%s'", cfg.SIWE.SessionTTL)
+	}
+	return nil
+}
+
+func checkURL(name, raw string, schemes ...string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("%s: invalid URL '%s'", name, raw)
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: URL scheme must be one of %s", name, strings.Join(schemes, ", "))
+}
+
+// ServerNames returns the servers listed in services, without duplicates
+func (cfg *Config) ServerNames() []ServerName {
+	var names []ServerName
+	seen := make(map[ServerName]bool)
+	for _, service := range cfg.Services {
+		name, _ := LookupServerName(service)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// AccountsConfig returns the test account settings in the form the accounts package takes
+func (cfg *Config) AccountsConfig() accounts.Config {
+	count := cfg.Accounts.Count
+	if count == 0 {
+		count = len(cfg.Accounts.Names)
+	}
+	return accounts.Config{
+		Mnemonic:         cfg.Accounts.Mnemonic,
+		Passphrase:       cfg.Accounts.Passphrase,
+		BasePath:         cfg.Accounts.BasePath,
+		Count:            count,
+		Names:            cfg.Accounts.Names,
+		SignerMode:       cfg.Accounts.Signer,
+		KeystoreDir:      cfg.Storage.KeystoreDir,
+		KeystorePassword: cfg.Accounts.KeystorePassword,
+		ExternalSigner:   cfg.Accounts.ExternalSigner,
+		ExposeKeys:       cfg.Accounts.ExposeKeys,
+	}
+}
+
+// SIWEConfig returns the Sign-In with Ethereum settings in the form the siwe package takes, or nil
+// when it is disabled
+func (cfg *Config) SIWEConfig() *siwe.Config {
+	if !cfg.SIWE.Enabled {
+		return nil
+	}
+	ttl, _ := time.ParseDuration(cfg.SIWE.SessionTTL) // checked by Validate
+	authConfig := &siwe.Config{Domain: cfg.SIWE.Domain, ChainID: cfg.SIWE.ChainID, SessionTTL: ttl}
+	for _, address := range cfg.SIWE.Allowlist {
+		authConfig.Allowlist = append(authConfig.Allowlist, common.HexToAddress(address))
+	}
+	return authConfig
+}
+
+// Redacted returns a copy with every secret setting masked, safe to log or serve
+func (cfg *Config) Redacted() *Config {
+	clone := *cfg
+	for _, s := range clone.settings() {
+		if s.value.IsZero() {
+			continue
+		}
+		switch s.secret {
+		case "true":
+			s.value.SetString(redacted)
+		case "url":
+			s.value.SetString(redactURL(s.value.String()))
+		}
+	}
+	return &clone
+}
+
+// redactURL keeps the scheme and host of a URL, masking its credentials, path and query
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	safe := parsed.Scheme + "://"
+	if parsed.User != nil {
+		safe += redacted + "@"
+	}
+	safe += parsed.Host
+	if strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "" {
+		safe += "/" + redacted
+	}
+	return safe
+}
+
+// setting is one leaf of Config with its env var, flag and dotted file path
+type setting struct {
+	path   string
+	env    string
+	flag   string
+	secret string // "true" masks the value, "url" only the secret parts of a URL
+	value  reflect.Value
+}
+
+func (cfg *Config) settings() []setting {
+	return collectSettings(reflect.ValueOf(cfg).Elem(), "", "", "")
+}
+
+// collectSettings walks nested structs, joining env names with "_" and flags and paths with "."
+func collectSettings(v reflect.Value, path, env, flagName string) []setting {
+	var out []setting
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		fieldPath := join(path, name, ".")
+		fieldEnv := join(env, field.Tag.Get("env"), "_")
+		fieldFlag := join(flagName, field.Tag.Get("flag"), ".")
+		if field.Type.Kind() == reflect.Struct {
+			out = append(out, collectSettings(v.Field(i), fieldPath, fieldEnv, fieldFlag)...)
+			continue
+		}
+		out = append(out, setting{
+			path:   fieldPath,
+			env:    fieldEnv,
+			flag:   fieldFlag,
+			secret: field.Tag.Get("secret"),
+			value:  v.Field(i),
+		})
+	}
+	return out
+}
+
+func join(prefix, name, sep string) string {
+	if prefix == "" || name == "" {
+		return prefix + name
+	}
+	return prefix + sep + name
+}
+
+// set parses raw into the setting; lists are comma separated
+func (s setting) set(raw string) error {
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("'%s' is not an integer", raw)
+		}
+		s.value.SetInt(int64(n))
+	case reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("'%s' is not an unsigned integer", raw)
+		}
+		s.value.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a boolean", raw)
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		var list []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(list))
+	default:
+		panic(fmt.Sprintf("config: unsupported setting type %s", s.value.Type()))
+	}
+	return nil
+}
+
+var (
+	currentMu sync.Mutex
+	current   *Config
+)
+
+// Init loads the configuration from the command line and environment, makes it current and sets
+// up logging
+func Init(args []string) (*Config, error) {
+	cfg, err := Load(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := logutil.Setup(os.Stdout, cfg.Log.Format, cfg.Log.Level); err != nil {
+		return nil, err
+	}
+	SetCurrent(cfg)
+	return cfg, nil
+}
+
+// SetCurrent replaces the configuration Current returns
+func SetCurrent(cfg *Config) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = cfg
+}
+
+// Current returns the configuration set by Init. Processes that never call Init, such as clients
+// and tests, get the defaults overlaid with the config file and env vars.
+func Current() *Config {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	if current == nil {
+		cfg, err := Load(nil)
+		if err != nil {
+			slog.Warn("⚠️ Invalid configuration, using defaults", "error", err)
+			cfg = Default()
+			cfg.fillDerived()
+		}
+		current = cfg
+	}
+	return current
+}
@@ -1,19 +1,18 @@
 package config
 
 import (
-	"flag"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"net/http"
 	"strings"
 )
 
 type DevNodeConfig struct {
-	Port          string
-	WebSocketPort string
+	HTTPURL string
+	WSURL   string
 }
 
 func (config DevNodeConfig) GetDevNodeUrl() string {
-	return "http://localhost:" + config.Port
+	return config.HTTPURL
 }
 
 func (config DevNodeConfig) GetEthClient() (*ethclient.Client, error) {
@@ -24,51 +23,38 @@ func (config DevNodeConfig) GetEthClient() (*ethclient.Client, error) {
 type ServerConfig struct {
 	Name          ServerName
 	Port          string
+	URL           string
 	DevNodeConfig DevNodeConfig
 }
 
-func GetServerConfigFromFlag(name ServerName) ServerConfig {
-	var port string
-	var serverPort string
-	flag.StringVar(&port, "port", "8545", "HTTP RPC port for the dev node")
-	flag.StringVar(&serverPort, "serverPort", "8888", "HTTP RPC port for the supporting server")
-	flag.Parse()
-
-	return ServerConfig{
-		Name: name,
-		Port: serverPort,
-		DevNodeConfig: DevNodeConfig{
-			Port: port,
-		},
-	}
-}
-
+// GetServerConfig derives the server's settings from the current configuration
 func (serverName ServerName) GetServerConfig() ServerConfig {
+	cfg := Current()
 	devNodeConfig := DevNodeConfig{
-		Port:          "8565",
-		WebSocketPort: "8546",
-	}
-	registry := make(map[ServerName]ServerConfig)
-	registry[Servers.DevServer] = ServerConfig{
-		Name:          Servers.DevServer,
-		Port:          "8575",
-		DevNodeConfig: devNodeConfig,
+		HTTPURL: cfg.Node.HTTPURL,
+		WSURL:   cfg.Node.WSURL,
 	}
+	registry := make(map[ServerName]ServiceConfig)
+	registry[Servers.DevServer] = cfg.Servers.DevServer
+	registry[Servers.LogServer] = cfg.Servers.LogServer
 
-	registry[Servers.LogServer] = ServerConfig{
-		Name:          Servers.LogServer,
-		Port:          "9585",
+	service, ok := registry[serverName]
+	if !ok {
+		return ServerConfig{}
+	}
+	return ServerConfig{
+		Name:          serverName,
+		Port:          service.Port,
+		URL:           service.URL,
 		DevNodeConfig: devNodeConfig,
 	}
-
-	return registry[serverName]
 }
 
 func (config ServerConfig) GetServerUrl(pathSegment string) string {
 	if strings.HasPrefix(pathSegment, "/") {
-		return "http://localhost:" + config.Port + pathSegment
+		return config.URL + pathSegment
 	}
-	return "http://localhost:" + config.Port + "/" + pathSegment
+	return config.URL + "/" + pathSegment
 }
 
 type ServerName string
@@ -89,3 +75,13 @@ var Servers = ServersName{
 	DevServer: "DevServer",
 	LogServer: "LogServer",
 }
+
+// LookupServerName finds a server by name, case-insensitively, e.g. "devserver"
+func LookupServerName(name string) (ServerName, bool) {
+	for _, serverName := range []ServerName{Servers.DevServer, Servers.LogServer} {
+		if strings.EqualFold(name, string(serverName)) {
+			return serverName, true
+		}
+	}
+	return "", false
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"eth-toy-client/core/httpapi"
 	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/revert"
 	toytypes "eth-toy-client/core/types"
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"strings"
 	"sync"
 	"time"
 )
 
+var logger = logutil.For("contracts")
+
 func DeployContract(
 	ctx context.Context,
 	client *ethclient.Client,
@@ -43,7 +47,7 @@ func DeployContract(
 	}
 
 	txHash := apiResp.TxHash
-	fmt.Printf("🚀 Deployment tx sent: %s\n", txHash)
+	logger.InfoContext(ctx, "🚀 Deployment tx sent", "tx", txHash)
 
 	// ⏳ Wait for receipt
 	var receipt *types.Receipt
@@ -58,34 +62,32 @@ func DeployContract(
 		return common.Address{}, txHash, fmt.Errorf("⏱️ timeout waiting for tx %s", txHash)
 	}
 
-	// Print receipt details for debugging
-	fmt.Println("Transaction Receipt Details:")
-	fmt.Printf("  Status: %d\n", receipt.Status) // Status: 1 (success) or 0 (failure)
-	fmt.Printf("  Transaction Hash: %s\n", receipt.TxHash.Hex())
-	fmt.Printf("  Contract Address: %s\n", receipt.ContractAddress.Hex())
-	fmt.Printf("  Block Number: %d\n", receipt.BlockNumber.Uint64())
-	fmt.Printf("  Gas Used: %d\n", receipt.GasUsed)
-	fmt.Println("  Logs:")
-	for i, log := range receipt.Logs {
-		fmt.Printf("    Log #%d: %+v\n", i, log)
-	}
+	logger.DebugContext(ctx, "Deployment receipt",
+		"tx", receipt.TxHash.Hex(),
+		"status", receipt.Status, // 1 (success) or 0 (failure)
+		"contract", receipt.ContractAddress.Hex(),
+		"block", receipt.BlockNumber.Uint64(),
+		"gasUsed", receipt.GasUsed,
+		"logs", len(receipt.Logs))
 
 	if receipt.Status != 1 {
-		return common.Address{}, txHash, fmt.Errorf("transaction failed, status: %d", receipt.Status)
+		reason, explainErr := revert.Explain(ctx, client, receipt)
+		if explainErr != nil {
+			logger.WarnContext(ctx, "⚠️ Could not explain failed tx", "tx", txHash, "error", explainErr)
+			return common.Address{}, txHash, fmt.Errorf("transaction failed, status: %d", receipt.Status)
+		}
+		return common.Address{}, txHash, fmt.Errorf("transaction failed, status: %d, reason: %s", receipt.Status, revert.String(reason))
 	}
 
-	logutil.Infof("contract address: %s", receipt.ContractAddress.Hex())
-
 	code, err := client.CodeAt(ctx, receipt.ContractAddress, nil)
 	if err != nil {
 		return common.Address{}, txHash, fmt.Errorf("failed to fetch contract code: %w", err)
 	}
-	logutil.Infof("contract code: %x", string(code))
-	logutil.Infof("contract Tx Hash: %s", receipt.TxHash)
 	if len(code) == 0 {
 		return common.Address{}, txHash, fmt.Errorf("contract code is empty — deployment likely failed")
 	}
 
+	logger.InfoContext(ctx, "✅ Contract deployed", "contract", receipt.ContractAddress.Hex(), "tx", txHash, "codeBytes", len(code))
 	return receipt.ContractAddress, txHash, nil
 }
 
@@ -105,12 +107,14 @@ type ContractMeta struct {
 }
 
 type DeployedContractInfo struct {
-	Pending   bool
-	Alias     string
-	Address   toytypes.ContractAddress
-	TxHash    string
-	ABI       string
-	ParsedABI *abi.ABI
+	Pending             bool
+	Alias               string
+	Address             toytypes.ContractAddress
+	TxHash              string
+	ABI                 string
+	ParsedABI           *abi.ABI `json:"-"`
+	StorageLayout       string
+	ParsedStorageLayout *StorageLayout `json:"-"`
 }
 
 type Registry struct {
@@ -124,17 +128,36 @@ func NewRegistry() *Registry {
 	}
 }
 
+// Add stores a contract. Registering an address again under the same alias merges the two entries,
+// so services sharing one registry can each register the contracts they are told about.
 func (r *Registry) Add(meta DeployedContractInfo) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, exists := r.entries[meta.Address]; exists {
-		return fmt.Errorf("ContractAddress already exists: %s", meta.Address)
+	if existing, exists := r.entries[meta.Address]; exists {
+		if existing.Alias != meta.Alias {
+			return fmt.Errorf("ContractAddress already exists: %s", meta.Address)
+		}
+		meta = mergeInfo(existing, meta)
 	}
 
 	r.entries[meta.Address] = meta
 	return nil
 }
 
+// mergeInfo keeps the newer entry and fills its empty fields from the older one
+func mergeInfo(older, newer DeployedContractInfo) DeployedContractInfo {
+	if newer.TxHash == "" {
+		newer.TxHash = older.TxHash
+	}
+	if newer.ABI == "" {
+		newer.ABI, newer.ParsedABI = older.ABI, older.ParsedABI
+	}
+	if newer.StorageLayout == "" {
+		newer.StorageLayout, newer.ParsedStorageLayout = older.StorageLayout, older.ParsedStorageLayout
+	}
+	return newer
+}
+
 func (r *Registry) All() []DeployedContractInfo {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -153,6 +176,50 @@ func (r *Registry) Get(contractAddress toytypes.ContractAddress) (DeployedContra
 	return meta, ok
 }
 
+// Lookup finds a registered contract by address, ignoring hex casing
+func (r *Registry) Lookup(address common.Address) (DeployedContractInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, meta := range r.entries {
+		if common.HexToAddress(meta.Address.Address) == address {
+			return meta, true
+		}
+	}
+	return DeployedContractInfo{}, false
+}
+
+// GetByAlias finds a registered contract by its alias
+func (r *Registry) GetByAlias(alias string) (DeployedContractInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, meta := range r.entries {
+		if meta.Alias == alias {
+			return meta, true
+		}
+	}
+	return DeployedContractInfo{}, false
+}
+
+// ABIsFor returns the parsed ABIs of all registered contracts, with the ABI
+// registered for address first so its custom errors win over look-alikes.
+func (r *Registry) ABIsFor(address common.Address) []*abi.ABI {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	abis := make([]*abi.ABI, 0, len(r.entries))
+	for _, meta := range r.entries {
+		if meta.ParsedABI == nil {
+			continue
+		}
+		if common.HexToAddress(meta.Address.Address) == address {
+			abis = append([]*abi.ABI{meta.ParsedABI}, abis...)
+		} else {
+			abis = append(abis, meta.ParsedABI)
+		}
+	}
+	return abis
+}
+
 type AliasDeployResponse struct {
 	Alias   string `json:"alias"`
 	Address string `json:"address"`
@@ -167,15 +234,27 @@ type DeployedContractMetaJSON struct {
 	Bytecode  string `json:"bytecode"`
 	Timestamp int64  `json:"timestamp"`
 	Owner     string `json:"owner"`
+
+	StorageLayout string `json:"storageLayout,omitempty"` // optional `solc --storage-layout` output
+}
+
+// ParseABI parses a JSON ABI string as stored in DeployedContractMetaJSON
+func ParseABI(rawABI string) (*abi.ABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
 }
 
 func (meta *DeployedContractMetaJSON) ToDeployedContractInfo(pending bool) *DeployedContractInfo {
 	return &DeployedContractInfo{
-		Address:   toytypes.ContractAddress{Address: meta.Address},
-		Alias:     meta.Alias,
-		Pending:   pending,
-		TxHash:    meta.TxHash,
-		ABI:       meta.ABI,
-		ParsedABI: nil,
+		Address:       toytypes.ContractAddress{Address: meta.Address},
+		Alias:         meta.Alias,
+		Pending:       pending,
+		TxHash:        meta.TxHash,
+		ABI:           meta.ABI,
+		ParsedABI:     nil,
+		StorageLayout: meta.StorageLayout,
 	}
 }
@@ -0,0 +1,24 @@
+package contract
+
+import (
+	toytypes "eth-toy-client/core/types"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryAddMergesSameAlias(t *testing.T) {
+	reg := NewRegistry()
+	address := toytypes.ContractAddress{Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"}
+
+	require.NoError(t, reg.Add(DeployedContractInfo{Alias: "counter", Address: address, TxHash: "0xabc"}))
+	require.NoError(t, reg.Add(DeployedContractInfo{Alias: "counter", Address: address, ABI: "[]"}), "a second service registers the same contract")
+
+	info, ok := reg.Get(address)
+	require.True(t, ok)
+	require.Equal(t, "0xabc", info.TxHash)
+	require.Equal(t, "[]", info.ABI)
+	require.Len(t, reg.All(), 1)
+
+	require.Error(t, reg.Add(DeployedContractInfo{Alias: "other", Address: address}))
+}
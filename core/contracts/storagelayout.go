@@ -0,0 +1,175 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxLabeledArrayIndex bounds how far past the data slot of a dynamic array we still label elements
+const maxLabeledArrayIndex = 1 << 16
+
+// StorageLayout is the JSON produced by `solc --storage-layout`
+type StorageLayout struct {
+	Storage []StorageEntry         `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+type StorageEntry struct {
+	Label  string `json:"label"`
+	Offset int    `json:"offset"`
+	Slot   string `json:"slot"`
+	Type   string `json:"type"`
+}
+
+type StorageType struct {
+	Encoding      string         `json:"encoding"` // inplace, mapping, dynamic_array, bytes
+	Label         string         `json:"label"`
+	NumberOfBytes string         `json:"numberOfBytes"`
+	Key           string         `json:"key,omitempty"`
+	Value         string         `json:"value,omitempty"`
+	Base          string         `json:"base,omitempty"`
+	Members       []StorageEntry `json:"members,omitempty"`
+}
+
+// ParseStorageLayout parses a solc storage layout JSON string
+func ParseStorageLayout(rawLayout string) (*StorageLayout, error) {
+	var layout StorageLayout
+	if err := json.Unmarshal([]byte(rawLayout), &layout); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// LabelSlot names a storage slot after the state variable(s) it holds. Mapping entries can only
+// be recognised when their key is among mappingKeys, as Solidity hashes keys into the slot.
+// An empty string means the slot could not be attributed.
+func (layout *StorageLayout) LabelSlot(slot common.Hash, mappingKeys []common.Address) string {
+	if layout == nil {
+		return ""
+	}
+	target := slot.Big()
+
+	var labels []string
+	for _, entry := range layout.Storage {
+		base, ok := new(big.Int).SetString(entry.Slot, 10)
+		if !ok {
+			continue
+		}
+		if label := layout.labelIn(entry.Label, entry.Type, base, target, mappingKeys); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ", ")
+}
+
+func (layout *StorageLayout) labelIn(name, typeID string, base, target *big.Int, mappingKeys []common.Address) string {
+	typ, ok := layout.Types[typeID]
+	if !ok {
+		if base.Cmp(target) == 0 {
+			return name
+		}
+		return ""
+	}
+
+	switch typ.Encoding {
+	case "mapping":
+		for _, key := range mappingKeys {
+			entrySlot := mappingSlot(key, base)
+			if label := layout.labelIn(fmt.Sprintf("%s[%s]", name, key.Hex()), typ.Value, entrySlot, target, mappingKeys); label != "" {
+				return label
+			}
+		}
+		if base.Cmp(target) == 0 {
+			return name
+		}
+		return ""
+
+	case "dynamic_array":
+		if base.Cmp(target) == 0 {
+			return name + ".length"
+		}
+		data := crypto.Keccak256Hash(common.BigToHash(base).Bytes()).Big()
+		index := new(big.Int).Sub(target, data)
+		if index.Sign() < 0 || index.Cmp(big.NewInt(maxLabeledArrayIndex)) >= 0 {
+			return ""
+		}
+		elementSize := layout.typeSize(typ.Base)
+		if elementSize >= 32 {
+			slotsPerElement := int64((elementSize + 31) / 32)
+			i := index.Int64()
+			if i%slotsPerElement == 0 {
+				return fmt.Sprintf("%s[%d]", name, i/slotsPerElement)
+			}
+			return fmt.Sprintf("%s[%d] (+%d)", name, i/slotsPerElement, i%slotsPerElement)
+		}
+		return fmt.Sprintf("%s[packed slot %d]", name, index.Int64())
+
+	case "bytes":
+		if base.Cmp(target) == 0 {
+			return name
+		}
+		data := crypto.Keccak256Hash(common.BigToHash(base).Bytes()).Big()
+		index := new(big.Int).Sub(target, data)
+		if index.Sign() >= 0 && index.Cmp(big.NewInt(maxLabeledArrayIndex)) < 0 {
+			return fmt.Sprintf("%s (data +%d)", name, index.Int64())
+		}
+		return ""
+
+	default:
+		offset := new(big.Int).Sub(target, base)
+		slots := int64((layout.typeSize(typeID) + 31) / 32)
+		if slots < 1 {
+			slots = 1
+		}
+		if offset.Sign() < 0 || offset.Cmp(big.NewInt(slots)) >= 0 {
+			return ""
+		}
+		if len(typ.Members) > 0 {
+			var members []string
+			for _, member := range typ.Members {
+				memberSlot, ok := new(big.Int).SetString(member.Slot, 10)
+				if !ok {
+					continue
+				}
+				memberBase := new(big.Int).Add(base, memberSlot)
+				if label := layout.labelIn(name+"."+member.Label, member.Type, memberBase, target, mappingKeys); label != "" {
+					members = append(members, label)
+				}
+			}
+			if len(members) > 0 {
+				return strings.Join(members, ", ")
+			}
+		}
+		if offset.Sign() == 0 {
+			return name
+		}
+		return fmt.Sprintf("%s (+%d)", name, offset.Int64())
+	}
+}
+
+func (layout *StorageLayout) typeSize(typeID string) int {
+	typ, ok := layout.Types[typeID]
+	if !ok {
+		return 32
+	}
+	size, err := strconv.Atoi(typ.NumberOfBytes)
+	if err != nil {
+		return 32
+	}
+	return size
+}
+
+// mappingSlot computes keccak256(pad32(key) . pad32(base)), where Solidity stores mapping[key]
+func mappingSlot(key common.Address, base *big.Int) *big.Int {
+	return crypto.Keccak256Hash(
+		common.LeftPadBytes(key.Bytes(), 32),
+		common.BigToHash(base).Bytes(),
+	).Big()
+}
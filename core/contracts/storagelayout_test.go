@@ -0,0 +1,85 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// Trimmed `solc --storage-layout` output of a MockUSDC-like token
+const tokenStorageLayout = `{
+	"storage": [
+		{"label": "_balances", "offset": 0, "slot": "0", "type": "t_mapping(t_address,t_uint256)"},
+		{"label": "_allowances", "offset": 0, "slot": "1", "type": "t_mapping(t_address,t_mapping(t_address,t_uint256))"},
+		{"label": "_totalSupply", "offset": 0, "slot": "2", "type": "t_uint256"},
+		{"label": "_decimals", "offset": 0, "slot": "3", "type": "t_uint8"},
+		{"label": "_paused", "offset": 1, "slot": "3", "type": "t_bool"},
+		{"label": "holders", "offset": 0, "slot": "4", "type": "t_array(t_address)dyn_storage"}
+	],
+	"types": {
+		"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+		"t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+		"t_uint8": {"encoding": "inplace", "label": "uint8", "numberOfBytes": "1"},
+		"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		"t_array(t_address)dyn_storage": {"encoding": "dynamic_array", "label": "address[]", "numberOfBytes": "32", "base": "t_address"},
+		"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "label": "mapping(address => uint256)", "numberOfBytes": "32", "key": "t_address", "value": "t_uint256"},
+		"t_mapping(t_address,t_mapping(t_address,t_uint256))": {"encoding": "mapping", "label": "mapping(address => mapping(address => uint256))", "numberOfBytes": "32", "key": "t_address", "value": "t_mapping(t_address,t_uint256)"}
+	}
+}`
+
+var (
+	alice = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob   = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+func parseTestLayout(t *testing.T) *StorageLayout {
+	layout, err := ParseStorageLayout(tokenStorageLayout)
+	require.NoError(t, err)
+	return layout
+}
+
+func TestLabelSlotPlainVariable(t *testing.T) {
+	layout := parseTestLayout(t)
+	require.Equal(t, "_totalSupply", layout.LabelSlot(common.BigToHash(big.NewInt(2)), nil))
+}
+
+func TestLabelSlotPackedVariables(t *testing.T) {
+	layout := parseTestLayout(t)
+	require.Equal(t, "_decimals, _paused", layout.LabelSlot(common.BigToHash(big.NewInt(3)), nil))
+}
+
+func TestLabelSlotMappingWithKnownKey(t *testing.T) {
+	layout := parseTestLayout(t)
+	slot := common.BigToHash(mappingSlot(bob, big.NewInt(0)))
+
+	require.Equal(t, "_balances["+bob.Hex()+"]", layout.LabelSlot(slot, []common.Address{alice, bob}))
+	require.Empty(t, layout.LabelSlot(slot, []common.Address{alice}), "unknown keys cannot be attributed")
+}
+
+func TestLabelSlotNestedMapping(t *testing.T) {
+	layout := parseTestLayout(t)
+	inner := mappingSlot(alice, big.NewInt(1))
+	slot := common.BigToHash(mappingSlot(bob, inner))
+
+	require.Equal(t, "_allowances["+alice.Hex()+"]["+bob.Hex()+"]", layout.LabelSlot(slot, []common.Address{alice, bob}))
+}
+
+func TestLabelSlotDynamicArray(t *testing.T) {
+	layout := parseTestLayout(t)
+	require.Equal(t, "holders.length", layout.LabelSlot(common.BigToHash(big.NewInt(4)), nil))
+
+	data := crypto.Keccak256Hash(common.BigToHash(big.NewInt(4)).Bytes()).Big()
+	third := common.BigToHash(new(big.Int).Add(data, big.NewInt(2)))
+	require.Equal(t, "holders[packed slot 2]", layout.LabelSlot(third, nil))
+}
+
+func TestLabelSlotUnknown(t *testing.T) {
+	layout := parseTestLayout(t)
+	require.Empty(t, layout.LabelSlot(common.BigToHash(big.NewInt(42)), nil))
+
+	var nilLayout *StorageLayout
+	require.Empty(t, nilLayout.LabelSlot(common.Hash{}, nil))
+}
@@ -0,0 +1,25 @@
+// Package devchain controls a development chain: snapshots, reverts, mining and time travel.
+// The simulated backend supports everything natively; a node reached over RPC supports whatever
+// its dev methods allow, as reported by Capabilities.
+package devchain
+
+import (
+	"context"
+	"errors"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrUnsupported is returned for controls the backend cannot perform
+var ErrUnsupported = errors.New("not supported by this chain backend")
+
+type Controller interface {
+	Capabilities() toytypes.ChainCapabilities
+	Head(ctx context.Context) (*types.Header, error)
+	// Snapshot returns an ID that Revert rolls the chain back to
+	Snapshot(ctx context.Context) (string, error)
+	Revert(ctx context.Context, id string) error
+	Mine(ctx context.Context, blocks uint64) error
+	SetNextBlockTimestamp(ctx context.Context, timestamp uint64) error
+	IncreaseTime(ctx context.Context, seconds uint64) error
+}
@@ -0,0 +1,82 @@
+package devchain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatedSnapshotMineRevert(t *testing.T) {
+	backend := simulated.NewBackend(types.GenesisAlloc{})
+	defer backend.Close()
+	chain := NewSimulatedController(backend)
+	ctx := context.Background()
+
+	id, err := chain.Snapshot(ctx)
+	require.NoError(t, err)
+	start, err := chain.Head(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, chain.Mine(ctx, 3))
+	head, err := chain.Head(ctx)
+	require.NoError(t, err)
+	require.Equal(t, start.Number.Uint64()+3, head.Number.Uint64())
+
+	require.NoError(t, chain.Revert(ctx, id))
+	head, err = chain.Head(ctx)
+	require.NoError(t, err)
+	require.Equal(t, start.Hash(), head.Hash())
+
+	require.Error(t, chain.Revert(ctx, "0x1234"))
+}
+
+func TestSimulatedTimeTravel(t *testing.T) {
+	backend := simulated.NewBackend(types.GenesisAlloc{})
+	defer backend.Close()
+	chain := NewSimulatedController(backend)
+	ctx := context.Background()
+
+	start, err := chain.Head(ctx)
+	require.NoError(t, err)
+	require.NoError(t, chain.IncreaseTime(ctx, 3600))
+	head, err := chain.Head(ctx)
+	require.NoError(t, err)
+	require.Equal(t, start.Time+3600, head.Time)
+
+	require.NoError(t, chain.SetNextBlockTimestamp(ctx, head.Time+100))
+	next, err := chain.Head(ctx)
+	require.NoError(t, err)
+	require.Equal(t, head.Time+100, next.Time)
+
+	require.Error(t, chain.SetNextBlockTimestamp(ctx, next.Time))
+}
+
+type fakeWeb3 struct{ version string }
+
+func (f *fakeWeb3) ClientVersion() string { return f.version }
+
+func TestRPCControllerCapabilities(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("web3", &fakeWeb3{version: "Geth/v1.15.6-stable/linux-amd64/go1.24"}))
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	chain, err := NewRPCController(context.Background(), client)
+	require.NoError(t, err)
+	caps := chain.Capabilities()
+	require.Equal(t, "geth", caps.Backend)
+	require.False(t, caps.Revert, "no debug namespace")
+	require.False(t, caps.Mine, "no dev namespace")
+	require.False(t, caps.IncreaseTime)
+
+	_, err = chain.Snapshot(context.Background())
+	require.ErrorIs(t, err, ErrUnsupported)
+	require.ErrorIs(t, chain.IncreaseTime(context.Background(), 10), ErrUnsupported)
+
+	require.Equal(t, "anvil", detectFlavor("anvil/v0.2.0"))
+	require.Equal(t, "hardhat", detectFlavor("HardhatNetwork/2.22.0/@ethereumjs/vm/7.0.0"))
+}
@@ -0,0 +1,197 @@
+package devchain
+
+import (
+	"context"
+	"errors"
+	toytypes "eth-toy-client/core/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"strings"
+	"time"
+)
+
+const (
+	flavorGeth    = "geth"
+	flavorAnvil   = "anvil"
+	flavorHardhat = "hardhat"
+)
+
+// RPCController drives a node over JSON-RPC. Anvil and Hardhat expose evm_* methods for every
+// control. A geth --dev node can rewind with debug_setHead and seal blocks on demand through the
+// simulated beacon's dev namespace, but cannot move time.
+type RPCController struct {
+	rpcClient    *rpc.Client
+	client       *ethclient.Client
+	capabilities toytypes.ChainCapabilities
+}
+
+// NewRPCController probes the node to find out which controls it supports
+func NewRPCController(ctx context.Context, rpcClient *rpc.Client) (*RPCController, error) {
+	var version string
+	if err := rpcClient.CallContext(ctx, &version, "web3_clientVersion"); err != nil {
+		return nil, fmt.Errorf("web3_clientVersion failed: %w", err)
+	}
+	c := &RPCController{
+		rpcClient: rpcClient,
+		client:    ethclient.NewClient(rpcClient),
+		capabilities: toytypes.ChainCapabilities{
+			Backend:       detectFlavor(version),
+			ClientVersion: version,
+		},
+	}
+
+	caps := &c.capabilities
+	if caps.Backend != flavorGeth {
+		caps.Snapshot, caps.Revert, caps.Mine = true, true, true
+		caps.SetNextBlockTimestamp, caps.IncreaseTime = true, true
+		return c, nil
+	}
+
+	// A node without rpc_modules simply reports no optional namespaces
+	var modules map[string]string
+	_ = rpcClient.CallContext(ctx, &modules, "rpc_modules")
+	if _, ok := modules["debug"]; ok {
+		caps.Snapshot, caps.Revert = true, true
+		caps.Notes = append(caps.Notes, "snapshot IDs are block numbers; revert rewinds with debug_setHead")
+	} else {
+		caps.Notes = append(caps.Notes, "enable the debug API (--http.api debug) for snapshot and revert")
+	}
+	if _, ok := modules["dev"]; ok {
+		caps.Mine = true
+	} else {
+		caps.Notes = append(caps.Notes, "enable the dev API (--http.api dev) to mine empty blocks")
+	}
+	caps.Notes = append(caps.Notes, "geth stamps blocks with the wall clock, time travel is unavailable")
+	return c, nil
+}
+
+func detectFlavor(clientVersion string) string {
+	version := strings.ToLower(clientVersion)
+	switch {
+	case strings.HasPrefix(version, "anvil"):
+		return flavorAnvil
+	case strings.Contains(version, "hardhat"):
+		return flavorHardhat
+	}
+	return flavorGeth
+}
+
+func (c *RPCController) Capabilities() toytypes.ChainCapabilities {
+	return c.capabilities
+}
+
+func (c *RPCController) Head(ctx context.Context) (*types.Header, error) {
+	return c.client.HeaderByNumber(ctx, nil)
+}
+
+func (c *RPCController) Snapshot(ctx context.Context) (string, error) {
+	if !c.capabilities.Snapshot {
+		return "", ErrUnsupported
+	}
+	if c.capabilities.Backend != flavorGeth {
+		var id hexutil.Big
+		if err := c.rpcClient.CallContext(ctx, &id, "evm_snapshot"); err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.EncodeUint64(head), nil
+}
+
+func (c *RPCController) Revert(ctx context.Context, id string) error {
+	if !c.capabilities.Revert {
+		return ErrUnsupported
+	}
+	if c.capabilities.Backend != flavorGeth {
+		var reverted bool
+		if err := c.rpcClient.CallContext(ctx, &reverted, "evm_revert", id); err != nil {
+			return err
+		}
+		if !reverted {
+			return fmt.Errorf("unknown snapshot ID '%s'", id)
+		}
+		return nil
+	}
+
+	block, err := hexutil.DecodeUint64(id)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot ID '%s'", id)
+	}
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if block > head {
+		return fmt.Errorf("snapshot block %d is ahead of the head %d", block, head)
+	}
+	return c.rpcClient.CallContext(ctx, nil, "debug_setHead", hexutil.Uint64(block))
+}
+
+func (c *RPCController) Mine(ctx context.Context, blocks uint64) error {
+	if !c.capabilities.Mine {
+		return ErrUnsupported
+	}
+	for i := uint64(0); i < blocks; i++ {
+		if c.capabilities.Backend != flavorGeth {
+			if err := c.rpcClient.CallContext(ctx, nil, "evm_mine"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.sealGethBlock(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sealGethBlock queues an empty withdrawal, which makes the dev-mode beacon seal a block, and waits
+// for the head to move
+func (c *RPCController) sealGethBlock(ctx context.Context) error {
+	head, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	withdrawal := &types.Withdrawal{Index: head}
+	if err := c.rpcClient.CallContext(ctx, nil, "dev_addWithdrawal", withdrawal); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	for {
+		current, err := c.client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if current > head {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("timed out waiting for the node to seal a block")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (c *RPCController) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) error {
+	if !c.capabilities.SetNextBlockTimestamp {
+		return ErrUnsupported
+	}
+	return c.rpcClient.CallContext(ctx, nil, "evm_setNextBlockTimestamp", timestamp)
+}
+
+func (c *RPCController) IncreaseTime(ctx context.Context, seconds uint64) error {
+	if !c.capabilities.IncreaseTime {
+		return ErrUnsupported
+	}
+	return c.rpcClient.CallContext(ctx, nil, "evm_increaseTime", seconds)
+}
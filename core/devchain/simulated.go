@@ -0,0 +1,80 @@
+package devchain
+
+import (
+	"context"
+	"errors"
+	toytypes "eth-toy-client/core/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"time"
+)
+
+// SimulatedController drives an in-process simulated backend
+type SimulatedController struct {
+	backend *simulated.Backend
+}
+
+func NewSimulatedController(backend *simulated.Backend) *SimulatedController {
+	return &SimulatedController{backend: backend}
+}
+
+func (c *SimulatedController) Capabilities() toytypes.ChainCapabilities {
+	return toytypes.ChainCapabilities{
+		Backend:               "simulated",
+		Snapshot:              true,
+		Revert:                true,
+		Mine:                  true,
+		SetNextBlockTimestamp: true,
+		IncreaseTime:          true,
+		Notes: []string{
+			"snapshot IDs are block hashes; revert drops pending transactions and resets the head",
+			"time controls seal an empty block with the adjusted timestamp right away",
+		},
+	}
+}
+
+func (c *SimulatedController) Head(ctx context.Context) (*types.Header, error) {
+	return c.backend.Client().HeaderByNumber(ctx, nil)
+}
+
+func (c *SimulatedController) Snapshot(ctx context.Context) (string, error) {
+	head, err := c.Head(ctx)
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().Hex(), nil
+}
+
+func (c *SimulatedController) Revert(_ context.Context, id string) error {
+	raw, err := hexutil.Decode(id)
+	if err != nil || len(raw) != common.HashLength {
+		return fmt.Errorf("invalid snapshot ID '%s'", id)
+	}
+	c.backend.Rollback()
+	return c.backend.Fork(common.BytesToHash(raw))
+}
+
+func (c *SimulatedController) Mine(_ context.Context, blocks uint64) error {
+	for i := uint64(0); i < blocks; i++ {
+		c.backend.Commit()
+	}
+	return nil
+}
+
+func (c *SimulatedController) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) error {
+	head, err := c.Head(ctx)
+	if err != nil {
+		return err
+	}
+	if timestamp <= head.Time {
+		return errors.New("timestamp must be after the head block")
+	}
+	return c.backend.AdjustTime(time.Duration(timestamp-head.Time) * time.Second)
+}
+
+func (c *SimulatedController) IncreaseTime(_ context.Context, seconds uint64) error {
+	return c.backend.AdjustTime(time.Duration(seconds) * time.Second)
+}
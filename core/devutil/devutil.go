@@ -1,10 +1,11 @@
 package devutil
 
 import (
-	"encoding/json"
+	"context"
+	"eth-toy-client/client"
+	"eth-toy-client/config"
+	toytypes "eth-toy-client/core/types"
 	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -24,10 +25,7 @@ type ClientTestAccount struct {
 	CommonAddress common.Address `json:"-"`
 }
 
-type InfoResponse struct {
-	RPCURL        string `json:"rpcUrl"`
-	AccountsCount int    `json:"accountsCount"`
-}
+type InfoResponse = toytypes.DevInfoResponse
 
 type Urls struct {
 	ServerURL   string
@@ -36,7 +34,7 @@ type Urls struct {
 }
 
 func GetUrls() Urls {
-	base := "http://localhost:8575" // or env override in future
+	base := config.Current().Servers.DevServer.URL
 	return Urls{
 		ServerURL:   base,
 		InfoURL:     base + "/info",
@@ -44,23 +42,15 @@ func GetUrls() Urls {
 	}
 }
 
-func GetInfoResponse(urls Urls) (*InfoResponse, error) {
-	resp, err := http.Get(urls.InfoURL)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// DevServerClient returns an SDK client for the DevServer behind urls
+func (urls Urls) DevServerClient() *client.DevServerClient {
+	cfg := client.DefaultConfig(config.Servers.DevServer)
+	cfg.BaseURL = urls.ServerURL
+	return client.NewDevServerClient(cfg)
+}
 
-	var info InfoResponse
-	if err := json.Unmarshal(body, &info); err != nil {
-		return nil, err
-	}
-	return &info, nil
+func GetInfoResponse(urls Urls) (*InfoResponse, error) {
+	return urls.DevServerClient().Info(context.Background())
 }
 
 func GetDevContext(fromAlias string) (*DevContext, error) {
@@ -0,0 +1,305 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"eth-toy-client/core/logutil"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ClientConfig struct {
+	BaseURL      string            // prepended to every path, may be empty when paths are full URLs
+	Timeout      time.Duration     // default per-call timeout covering all attempts, 0 means none
+	Retries      int               // extra attempts for idempotent calls that fail or hit 429/502/503/504
+	RetryBackoff time.Duration     // first retry delay, doubled per attempt with jitter
+	Transport    http.RoundTripper // defaults to http.DefaultTransport
+	Header       http.Header       // sent with every request
+	Token        string            // bearer token for the Authorization header
+}
+
+// ResponseError is a non-2xx answer from a server
+type ResponseError struct {
+	Status    int
+	RequestID string
+	APIError
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// Client calls JSON APIs that answer with the APIResponse envelope
+type Client struct {
+	config ClientConfig
+	http   *http.Client
+
+	mu    sync.RWMutex
+	token string
+}
+
+func NewClient(config ClientConfig) *Client {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	return &Client{
+		config: config,
+		http:   &http.Client{Transport: config.Transport},
+		token:  config.Token,
+	}
+}
+
+func (c *Client) BaseURL() string {
+	return c.config.BaseURL
+}
+
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+type callOptions struct {
+	timeout   time.Duration
+	retries   int
+	header    http.Header
+	requestID string
+	raw       bool
+}
+
+// CallOption adjusts a single call
+type CallOption func(*callOptions)
+
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) { o.timeout = timeout }
+}
+
+// WithRetries overrides the retry count; it also makes non-idempotent calls retryable, so only use
+// it for POSTs that are safe to repeat
+func WithRetries(retries int) CallOption {
+	return func(o *callOptions) { o.retries = retries }
+}
+
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) { o.header.Set(key, value) }
+}
+
+func WithRequestID(id string) CallOption {
+	return func(o *callOptions) { o.requestID = id }
+}
+
+// WithoutEnvelope decodes the body directly into T for endpoints that answer with bare JSON
+func WithoutEnvelope() CallOption {
+	return func(o *callOptions) { o.raw = true }
+}
+
+func Get[T any](ctx context.Context, c *Client, path string, opts ...CallOption) (*T, error) {
+	return Do[T](ctx, c, http.MethodGet, path, nil, opts...)
+}
+
+func Post[T any](ctx context.Context, c *Client, path string, payload any, opts ...CallOption) (*T, error) {
+	return Do[T](ctx, c, http.MethodPost, path, payload, opts...)
+}
+
+func Put[T any](ctx context.Context, c *Client, path string, payload any, opts ...CallOption) (*T, error) {
+	return Do[T](ctx, c, http.MethodPut, path, payload, opts...)
+}
+
+func Delete[T any](ctx context.Context, c *Client, path string, opts ...CallOption) (*T, error) {
+	return Do[T](ctx, c, http.MethodDelete, path, nil, opts...)
+}
+
+// Do sends payload as JSON and decodes the APIResponse envelope. Error envelopes and other non-2xx
+// answers come back as *ResponseError.
+func Do[T any](ctx context.Context, c *Client, method, path string, payload any, opts ...CallOption) (*T, error) {
+	options := c.callOptions(opts)
+	body, status, err := c.send(ctx, method, path, payload, &options)
+	if err != nil {
+		return nil, err
+	}
+	if options.raw {
+		var parsed T
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+		}
+		return &parsed, nil
+	}
+
+	var parsed APIResponse[T]
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	if parsed.Error != nil {
+		return nil, &ResponseError{Status: status, RequestID: options.requestID, APIError: *parsed.Error}
+	}
+	if parsed.Data == nil {
+		parsed.Data = new(T)
+	}
+	return parsed.Data, nil
+}
+
+// GetText returns the body of a plain-text endpoint such as /ping
+func GetText(ctx context.Context, c *Client, path string, opts ...CallOption) (string, error) {
+	options := c.callOptions(opts)
+	body, _, err := c.send(ctx, http.MethodGet, path, nil, &options)
+	return string(body), err
+}
+
+func (c *Client) callOptions(opts []CallOption) callOptions {
+	options := callOptions{timeout: c.config.Timeout, retries: -1, header: http.Header{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.requestID == "" {
+		options.requestID = logutil.NewRequestID()
+	}
+	return options
+}
+
+// send runs the call with retries and returns the final body. Statuses of 400 and above become
+// *ResponseError unless the body is an error envelope for Do to decode.
+func (c *Client) send(ctx context.Context, method, path string, payload any, options *callOptions) ([]byte, int, error) {
+	attempts := 1
+	switch {
+	case options.retries >= 0:
+		attempts += options.retries
+	case idempotent(method):
+		attempts += c.config.Retries
+	}
+
+	var data []byte
+	if payload != nil {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal request payload: %w", err)
+		}
+	}
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		body, status, retryAfter, err := c.attempt(ctx, method, path, data, options)
+		if attempt >= attempts || !retryable(status, body, err) || ctx.Err() != nil {
+			if err != nil {
+				return nil, status, err
+			}
+			if status >= 400 && (options.raw || envelopeError(body) == nil) {
+				kind := ErrorKindForStatus(status)
+				return nil, status, &ResponseError{
+					Status:    status,
+					RequestID: options.requestID,
+					APIError:  APIError{Code: kind.Code, Message: strings.TrimSpace(string(body)), Retryable: kind.Retryable},
+				}
+			}
+			return body, status, nil
+		}
+
+		delay := backoff(c.config.RetryBackoff, attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, data []byte, options *callOptions) ([]byte, int, time.Duration, error) {
+	var reqBody io.Reader
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, reqBody)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for key, values := range c.config.Header {
+		req.Header[key] = values
+	}
+	for key, values := range options.header {
+		req.Header[key] = values
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.Token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set(logutil.RequestIDHeader, options.requestID)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("HTTP %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, 0, fmt.Errorf("failed to read %s %s response: %w", method, path, err)
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return body, resp.StatusCode, retryAfter, nil
+}
+
+// backoff doubles base per attempt and keeps a random half of it so concurrent clients spread out
+func backoff(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + mathrand.N(delay/2+1)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports transport failures, overload statuses and errors the server flagged as retryable
+func retryable(status int, body []byte, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	if status >= 400 {
+		if apiErr := envelopeError(body); apiErr != nil {
+			return apiErr.Retryable
+		}
+	}
+	return false
+}
+
+func envelopeError(body []byte) *APIError {
+	var probe struct {
+		Error *APIError `json:"error"`
+	}
+	if json.Unmarshal(body, &probe) != nil {
+		return nil
+	}
+	return probe.Error
+}
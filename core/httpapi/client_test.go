@@ -0,0 +1,138 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"eth-toy-client/core/logutil"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport remembers every request before handing it to the default transport
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, req)
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func flakyServer(failures int, status int) *httptest.Server {
+	var mu sync.Mutex
+	calls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		WriteOK(w, &DummyResponse{Echo: r.Header.Get(logutil.RequestIDHeader)})
+	}))
+}
+
+func TestClientRetriesIdempotentCalls(t *testing.T) {
+	server := flakyServer(2, http.StatusServiceUnavailable)
+	defer server.Close()
+	transport := &recordingTransport{}
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retries: 2, RetryBackoff: time.Millisecond, Transport: transport})
+
+	data, err := Get[DummyResponse](context.Background(), client, "/", WithRequestID("req-1"))
+	require.NoError(t, err)
+	require.Equal(t, "req-1", data.Echo)
+	require.Len(t, transport.requests, 3)
+	for _, req := range transport.requests {
+		require.Equal(t, "req-1", req.Header.Get(logutil.RequestIDHeader), "retries share the request ID")
+	}
+}
+
+func TestClientDoesNotRetryPostByDefault(t *testing.T) {
+	server := flakyServer(1, http.StatusBadGateway)
+	defer server.Close()
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retries: 3, RetryBackoff: time.Millisecond})
+
+	_, err := Post[DummyResponse](context.Background(), client, "/", DummyPayload{Message: "once"})
+	var respErr *ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusBadGateway, respErr.Status)
+	require.NotEmpty(t, respErr.RequestID)
+
+	// Opting in makes a POST that is safe to repeat retryable
+	_, err = Post[DummyResponse](context.Background(), client, "/", DummyPayload{Message: "again"}, WithRetries(1))
+	require.NoError(t, err)
+}
+
+func TestClientDecodesErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, http.StatusBadRequest, "Oopsie", "Something went wrong")
+	}))
+	defer server.Close()
+	client := NewClient(ClientConfig{BaseURL: server.URL})
+
+	_, err := Put[DummyResponse](context.Background(), client, "/", DummyPayload{})
+	var respErr *ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, "Oopsie", respErr.Code)
+	require.Equal(t, "Something went wrong", respErr.Message)
+}
+
+func TestClientSendsHeadersAndToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteOK(w, &map[string]string{
+			"auth":   r.Header.Get("Authorization"),
+			"static": r.Header.Get("X-Static"),
+			"call":   r.Header.Get("X-Call"),
+		})
+	}))
+	defer server.Close()
+	client := NewClient(ClientConfig{BaseURL: server.URL, Header: http.Header{"X-Static": {"yes"}}})
+	client.SetToken("secret")
+
+	got, err := Delete[map[string]string](context.Background(), client, "/", WithHeader("X-Call", "1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"auth": "Bearer secret", "static": "yes", "call": "1"}, *got)
+}
+
+func TestClientPerCallTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		WriteOK(w, &DummyResponse{})
+	}))
+	defer server.Close()
+	client := NewClient(ClientConfig{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	_, err := Get[DummyResponse](context.Background(), client, "/", WithTimeout(20*time.Millisecond))
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "got %v", err)
+}
+
+func TestClientWithoutEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"echo":"bare"}`))
+	}))
+	defer server.Close()
+
+	data, err := Get[DummyResponse](context.Background(), NewClient(ClientConfig{BaseURL: server.URL}), "/", WithoutEnvelope())
+	require.NoError(t, err)
+	require.Equal(t, "bare", data.Echo)
+}
+
+func TestBackoffIsJittered(t *testing.T) {
+	for attempt := 1; attempt <= 4; attempt++ {
+		full := 100 * time.Millisecond << (attempt - 1)
+		delay := backoff(100*time.Millisecond, attempt)
+		require.GreaterOrEqual(t, delay, full/2)
+		require.LessOrEqual(t, delay, full)
+	}
+	require.Zero(t, backoff(0, 1))
+}
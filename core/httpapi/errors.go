@@ -0,0 +1,104 @@
+package httpapi
+
+import (
+	"net/http"
+)
+
+// ErrorKind is one entry of the error catalog: a stable code with its HTTP status and whether
+// repeating the same request may succeed
+type ErrorKind struct {
+	Code      string
+	Status    int
+	Retryable bool
+}
+
+func (k *ErrorKind) Error() string {
+	return k.Code
+}
+
+// The error catalog. Handlers answer with Fail, clients match with errors.Is(err, httpapi.ErrNotFound).
+var (
+	ErrInvalidRequest   = &ErrorKind{Code: "InvalidRequest", Status: http.StatusBadRequest}   // malformed JSON or a request that is unusable as a whole
+	ErrInvalidParameter = &ErrorKind{Code: "InvalidParameter", Status: http.StatusBadRequest} // details.fields lists each missing or malformed field
+	ErrInvalidAccount   = &ErrorKind{Code: "InvalidAccount", Status: http.StatusBadRequest}   // an alias that names no test account
+	ErrInvalidSignature = &ErrorKind{Code: "InvalidSignature", Status: http.StatusBadRequest}
+	ErrUnauthorized     = &ErrorKind{Code: "Unauthorized", Status: http.StatusUnauthorized}
+	ErrNotFound         = &ErrorKind{Code: "NotFound", Status: http.StatusNotFound}
+	ErrMethodNotAllowed = &ErrorKind{Code: "MethodNotAllowed", Status: http.StatusMethodNotAllowed}
+	ErrConflict         = &ErrorKind{Code: "Conflict", Status: http.StatusConflict} // duplicate names, transactions that already mined
+	ErrTxRejected       = &ErrorKind{Code: "TxRejected", Status: http.StatusUnprocessableEntity}
+	ErrSigningFailed    = &ErrorKind{Code: "SigningFailed", Status: http.StatusInternalServerError}
+	ErrInternal         = &ErrorKind{Code: "Internal", Status: http.StatusInternalServerError}
+	ErrNotImplemented   = &ErrorKind{Code: "NotImplemented", Status: http.StatusNotImplemented}
+	ErrNodeError        = &ErrorKind{Code: "NodeError", Status: http.StatusBadGateway, Retryable: true} // the Ethereum node failed or is unreachable
+	ErrUnavailable      = &ErrorKind{Code: "Unavailable", Status: http.StatusServiceUnavailable, Retryable: true}
+	ErrTimeout          = &ErrorKind{Code: "Timeout", Status: http.StatusGatewayTimeout, Retryable: true}
+)
+
+var catalog = []*ErrorKind{
+	ErrInvalidRequest, ErrInvalidParameter, ErrInvalidAccount, ErrInvalidSignature, ErrUnauthorized,
+	ErrNotFound, ErrMethodNotAllowed, ErrConflict, ErrTxRejected, ErrSigningFailed, ErrInternal,
+	ErrNotImplemented, ErrNodeError, ErrUnavailable, ErrTimeout,
+}
+
+// LookupErrorKind finds a catalog entry by code
+func LookupErrorKind(code string) (*ErrorKind, bool) {
+	for _, kind := range catalog {
+		if kind.Code == code {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// ErrorKindForStatus picks the catalog entry for answers that carry no error envelope
+func ErrorKindForStatus(status int) *ErrorKind {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrInvalidRequest
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrMethodNotAllowed
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnprocessableEntity:
+		return ErrTxRejected
+	case http.StatusNotImplemented:
+		return ErrNotImplemented
+	case http.StatusBadGateway:
+		return ErrNodeError
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return ErrUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrTimeout
+	}
+	return ErrInternal
+}
+
+func (e *APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Is lets errors.Is match a decoded error against a catalog entry
+func (e *APIError) Is(target error) bool {
+	kind, ok := target.(*ErrorKind)
+	return ok && kind.Code == e.Code
+}
+
+// Fail writes an error envelope for a catalog entry
+func Fail(w http.ResponseWriter, kind *ErrorKind, message string) {
+	FailWithDetails(w, kind, message, nil)
+}
+
+// FailWithDetails writes an error envelope with a details object for machine consumers
+func FailWithDetails(w http.ResponseWriter, kind *ErrorKind, message string, details map[string]any) {
+	WriteJSON[any](w, kind.Status, nil, &APIError{
+		Code:      kind.Code,
+		Message:   message,
+		Retryable: kind.Retryable,
+		Details:   details,
+	})
+}
@@ -0,0 +1,63 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailWritesCatalogEntry(t *testing.T) {
+	rec := httptest.NewRecorder()
+	FailWithDetails(rec, ErrNodeError, "node is down", map[string]any{"method": "eth_chainId"})
+
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+	var decoded APIResponse[DummyResponse]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded))
+	require.Nil(t, decoded.Data)
+	require.Equal(t, "NodeError", decoded.Error.Code)
+	require.True(t, decoded.Error.Retryable)
+	require.Equal(t, map[string]any{"method": "eth_chainId"}, decoded.Error.Details)
+	require.ErrorIs(t, decoded.Error, ErrNodeError)
+	require.NotErrorIs(t, decoded.Error, ErrTimeout)
+}
+
+func TestCatalogCodesAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, kind := range catalog {
+		require.False(t, seen[kind.Code], "duplicate code %s", kind.Code)
+		seen[kind.Code] = true
+		found, ok := LookupErrorKind(kind.Code)
+		require.True(t, ok)
+		require.Same(t, kind, found)
+	}
+	_, ok := LookupErrorKind("Oopsie")
+	require.False(t, ok)
+}
+
+func TestClientMatchesCatalogErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/envelope":
+			Fail(w, ErrNotFound, "Account 'mallory' not found")
+		default:
+			http.Error(w, "gone", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(ClientConfig{BaseURL: server.URL, Retries: 2})
+
+	_, err := Get[DummyResponse](context.Background(), client, "/envelope")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, int32(1), calls.Load(), "non-retryable errors are not repeated")
+
+	// Answers without an envelope are mapped onto the catalog by status
+	_, err = Get[DummyResponse](context.Background(), client, "/plain")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -9,8 +9,10 @@ import (
 
 // APIError represents a structured error in API responses
 type APIError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	Retryable bool           `json:"retryable,omitempty"` // repeating the same request may succeed
+	Details   map[string]any `json:"details,omitempty"`
 }
 
 // APIResponse is a generic wrapper for any API response payload
@@ -39,7 +41,7 @@ func WriteOK[T any](w http.ResponseWriter, data *T) {
 	WriteJSON(w, http.StatusOK, data, nil)
 }
 
-// Helper for sending errors
+// Helper for sending errors with a free-form code, prefer Fail with a catalog entry
 func WriteError(w http.ResponseWriter, status int, code, message string) {
 	err := &APIError{Code: code, Message: message}
 	WriteJSON[any](w, status, nil, err)
@@ -0,0 +1,105 @@
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// consoleHandler writes records as "15:04:05.000 INFO  [component] message key=value ..."
+type consoleHandler struct {
+	w         io.Writer
+	mu        *sync.Mutex
+	level     slog.Leveler
+	component string
+	attrs     []byte // preformatted attrs from WithAttrs
+	group     string // prefix of the keys, e.g. "req."
+}
+
+func newConsoleHandler(w io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{w: w, mu: &sync.Mutex{}, level: level}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	if !r.Time.IsZero() {
+		buf.WriteString(r.Time.Format("15:04:05.000 "))
+	}
+	fmt.Fprintf(&buf, "%-5s ", r.Level.String())
+	if h.component != "" {
+		buf.WriteString("[" + h.component + "] ")
+	}
+	buf.WriteString(r.Message)
+	buf.Write(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&buf, h.group, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	buf := bytes.NewBuffer(append([]byte(nil), h.attrs...))
+	for _, a := range attrs {
+		if a.Key == "component" && h.group == "" {
+			next.component = a.Value.String()
+			continue
+		}
+		appendAttr(buf, h.group, a)
+	}
+	next.attrs = buf.Bytes()
+	return &next
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := *h
+	next.group = h.group + name + "."
+	return &next
+}
+
+func appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, member := range a.Value.Group() {
+			appendAttr(buf, prefix, member)
+		}
+		return
+	}
+	buf.WriteString(" " + prefix + a.Key + "=")
+	var value string
+	switch a.Value.Kind() {
+	case slog.KindDuration:
+		value = a.Value.Duration().Round(time.Microsecond).String()
+	default:
+		value = a.Value.String()
+	}
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	buf.WriteString(value)
+}
@@ -0,0 +1,94 @@
+package logutil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"eth-toy-client/core/statuswriter"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestIDHeader carries the request ID in and out; a missing one is generated. Clients send it
+// so retries of one call share an ID in the server logs.
+const RequestIDHeader = "X-Request-ID"
+
+type fieldsKey struct{}
+
+// fields is the request-scoped set of attrs. It is shared by everything running under the same
+// context, so a handler can Annotate what it learns later, such as the tx hash after sending.
+type fields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// WithFields returns a context whose records carry the parent's fields plus args, given as
+// alternating keys and values like slog.Logger.Info
+func WithFields(ctx context.Context, args ...any) context.Context {
+	attrs := append(Fields(ctx), argsToAttrs(args)...)
+	return context.WithValue(ctx, fieldsKey{}, &fields{attrs: attrs})
+}
+
+// Annotate adds fields to the context's existing set; without one it does nothing
+func Annotate(ctx context.Context, args ...any) {
+	f, ok := ctx.Value(fieldsKey{}).(*fields)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attrs = append(f.attrs, argsToAttrs(args)...)
+}
+
+// Fields returns a copy of the request-scoped fields of ctx
+func Fields(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	f, ok := ctx.Value(fieldsKey{}).(*fields)
+	if !ok {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slog.Attr(nil), f.attrs...)
+}
+
+func argsToAttrs(args []any) []slog.Attr {
+	var r slog.Record
+	r.Add(args...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// Middleware gives every request a request ID and logs it at debug level once served, with the
+// fields the handlers annotated
+func Middleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := WithFields(r.Context(), "requestId", requestID)
+
+		recorder := statuswriter.Wrap(w)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		logger.DebugContext(ctx, "request served",
+			"method", r.Method, "path", r.URL.Path, "status", recorder.Status, "duration", time.Since(start))
+	})
+}
+
+// NewRequestID returns a random 16 hex digit request ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
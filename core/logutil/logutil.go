@@ -1,29 +1,118 @@
+// Package logutil is the process-wide leveled logger on top of log/slog. Components log through
+// For("name"); output format and level are set once by Setup and the level can change at runtime.
 package logutil
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync/atomic"
 )
 
-// ℹ️ Informational log
-func Infof(format string, args ...any) {
-	fmt.Printf("ℹ️  "+format+"\n", args...)
+const (
+	FormatConsole = "console" // one human-readable line per record
+	FormatJSON    = "json"    // one JSON object per record
+)
+
+var (
+	level = new(slog.LevelVar)
+	// output is the handler every logger writes to, swapped by Setup
+	output atomic.Pointer[slog.Handler]
+)
+
+func init() {
+	_ = Setup(os.Stdout, FormatConsole, "info")
+	slog.SetDefault(slog.New(&dynamicHandler{}))
+}
+
+// Setup writes all logs to w in the given format from the given level on. Loggers created
+// earlier with For pick up the new output.
+func Setup(w io.Writer, format, lvl string) error {
+	if err := SetLevel(lvl); err != nil {
+		return err
+	}
+	var handler slog.Handler
+	switch format {
+	case FormatConsole, "":
+		handler = newConsoleHandler(w, level)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	default:
+		return fmt.Errorf("unknown log format %q, want %s or %s", format, FormatConsole, FormatJSON)
+	}
+	output.Store(&handler)
+	return nil
+}
+
+// ParseLevel accepts debug, info, warn and error in any case
+func ParseLevel(lvl string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(lvl)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, want debug, info, warn or error", lvl)
+	}
+	return l, nil
+}
+
+// SetLevel changes the minimum level of every logger
+func SetLevel(lvl string) error {
+	l, err := ParseLevel(lvl)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level returns the current minimum level, e.g. "info"
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
+// For returns the logger of a component, e.g. For("devserver")
+func For(component string) *slog.Logger {
+	return slog.New(&dynamicHandler{}).With("component", component)
+}
+
+// dynamicHandler forwards to the current output, replaying the attrs and groups it was derived
+// with, and adds the request-scoped fields of the record's context
+type dynamicHandler struct {
+	derive []func(slog.Handler) slog.Handler
+}
+
+func (h *dynamicHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+func (h *dynamicHandler) Handle(ctx context.Context, r slog.Record) error {
+	handler := *output.Load()
+	for _, derive := range h.derive {
+		handler = derive(handler)
+	}
+	if fields := Fields(ctx); len(fields) > 0 {
+		r = r.Clone()
+		r.AddAttrs(fields...)
+	}
+	return handler.Handle(ctx, r)
+}
+
+func (h *dynamicHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
 }
 
-// ⚠️ Warning log
-func Warnf(format string, args ...any) {
-	fmt.Printf("⚠️  "+format+"\n", args...)
+func (h *dynamicHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
 }
 
-// ❌ Error log
-func Errorf(format string, args ...any) {
-	fmt.Printf("❌ "+format+"\n", args...)
+func (h *dynamicHandler) with(derive func(slog.Handler) slog.Handler) *dynamicHandler {
+	return &dynamicHandler{derive: append(h.derive[:len(h.derive):len(h.derive)], derive)}
 }
 
-// 💀 Fatal error with exit
-func Exitf(format string, args ...any) {
-	msg := fmt.Sprintf("💀 "+format, args...)
-	fmt.Fprintln(os.Stderr, msg)
+// Fatal logs msg at error level and exits, for failures a process cannot start without
+func Fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Error(msg, args...)
 	os.Exit(1)
 }
 
@@ -0,0 +1,73 @@
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func captureLogs(t *testing.T, format, lvl string) *bytes.Buffer {
+	var buf bytes.Buffer
+	require.NoError(t, Setup(&buf, format, lvl))
+	t.Cleanup(func() { _ = Setup(os.Stdout, FormatConsole, "info") })
+	return &buf
+}
+
+func TestConsoleLineCarriesComponentAndFields(t *testing.T) {
+	logger := For("devserver") // created before Setup, still follows the new output
+	buf := captureLogs(t, FormatConsole, "info")
+
+	ctx := WithFields(context.Background(), "requestId", "abc")
+	Annotate(ctx, "tx", "0x1")
+	logger.InfoContext(ctx, "✅ Sent tx", "from", "alice")
+
+	line := buf.String()
+	require.Contains(t, line, "INFO  [devserver] ✅ Sent tx from=alice requestId=abc tx=0x1")
+}
+
+func TestLevelChangesAtRuntime(t *testing.T) {
+	logger := For("test")
+	buf := captureLogs(t, FormatJSON, "warn")
+
+	logger.Info("hidden")
+	require.Empty(t, buf.String())
+
+	require.NoError(t, SetLevel("DEBUG"))
+	require.Equal(t, "debug", Level())
+	logger.Debug("shown", "n", 1)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "shown", record["msg"])
+	require.Equal(t, "test", record["component"])
+
+	require.Error(t, SetLevel("verbose"))
+	require.Equal(t, "debug", Level(), "a bad level keeps the current one")
+}
+
+func TestMiddlewareAssignsRequestID(t *testing.T) {
+	buf := captureLogs(t, FormatConsole, "debug")
+	handler := Middleware(For("http"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Annotate(r.Context(), "alias", "bob")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/x", nil)
+	req.Header.Set(RequestIDHeader, "given")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, "given", rec.Header().Get(RequestIDHeader))
+	require.True(t, strings.Contains(buf.String(), "status=418 duration="))
+	require.Contains(t, buf.String(), "requestId=given alias=bob")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/x", nil))
+	require.Len(t, rec.Header().Get(RequestIDHeader), 16)
+}
@@ -0,0 +1,149 @@
+// Package metrics holds the process-wide Prometheus metrics. Every server serves the same registry
+// on /metrics, so when inso hosts several servers in one process each of them shows all metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"eth-toy-client/core/statuswriter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "inso"
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by server, route pattern, method and status code.",
+	}, []string{"server", "route", "method", "code"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by server, route pattern and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "route", "method"})
+
+	TxsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "txs_sent_total",
+		Help:      "Transactions the node accepted, by kind (send, batch, deploy, rpc, speedup, cancel).",
+	}, []string{"kind"})
+
+	TxsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "txs_failed_total",
+		Help:      "Transactions the node rejected, by kind and error code.",
+	}, []string{"kind", "code"})
+
+	NonceConflicts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nonce_conflicts_total",
+		Help:      "Transactions rejected because their nonce was already used or is held by a pending tx.",
+	})
+
+	RPCCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_proxy_calls_total",
+		Help:      "JSON-RPC calls through DevServer's /rpc proxy, by method (\"other\" when unserved), transport (http or ws) and result.",
+	}, []string{"method", "transport", "result"})
+
+	LogEventsReceived = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "log_events_received_total",
+		Help:      "Contract logs received from the node subscription.",
+	})
+
+	LogEventsDecoded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "log_events_decoded_total",
+		Help:      "Contract logs by decoding result (ok or error).",
+	}, []string{"result"})
+
+	LogEventsDelivered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "log_events_delivered_total",
+		Help:      "Log events handed to a broadcaster subscriber.",
+	}, []string{"subscriber"})
+
+	LogEventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "log_events_dropped_total",
+		Help:      "Log events dropped because a broadcaster subscriber's channel was full.",
+	}, []string{"subscriber"})
+
+	SubscriptionReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "subscription_reconnects_total",
+		Help:      "Node subscriptions re-established after an error.",
+	}, []string{"subscription"})
+
+	LastProcessedBlock = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_processed_block",
+		Help:      "Number of the last block the block watcher processed.",
+	})
+
+	ChainHeadBlock = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_head_block",
+		Help:      "Chain head seen after processing the last block.",
+	})
+
+	BlockLag = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "block_lag",
+		Help:      "Blocks between the chain head and the last processed block.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration,
+		TxsSent, TxsFailed, NonceConflicts, RPCCalls,
+		LogEventsReceived, LogEventsDecoded, LogEventsDelivered, LogEventsDropped,
+		SubscriptionReconnects, LastProcessedBlock, ChainHeadBlock, BlockLag,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveBlock records the last processed block and how far it trails the chain head
+func ObserveBlock(processed, head uint64) {
+	LastProcessedBlock.Set(float64(processed))
+	ChainHeadBlock.Set(float64(head))
+	if head > processed {
+		BlockLag.Set(float64(head - processed))
+	} else {
+		BlockLag.Set(0)
+	}
+}
+
+// Instrument counts and times the requests of one server. Routes are labeled by the ServeMux
+// pattern that matched, so path values such as tx hashes do not explode the label set.
+func Instrument(server string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := statuswriter.Wrap(w)
+		next.ServeHTTP(recorder, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequests.WithLabelValues(server, route, r.Method, strconv.Itoa(recorder.Status)).Inc()
+		HTTPDuration.WithLabelValues(server, route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentLabelsByRoutePattern(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := Instrument("TestServer", mux)
+
+	for _, path := range []string{"/tx/0x1", "/tx/0x2", "/nope"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, 2.0, testutil.ToFloat64(HTTPRequests.WithLabelValues("TestServer", "GET /tx/{hash}", "GET", "404")))
+	require.Equal(t, 1.0, testutil.ToFloat64(HTTPRequests.WithLabelValues("TestServer", "unmatched", "GET", "404")))
+}
+
+func TestObserveBlockNeverReportsNegativeLag(t *testing.T) {
+	ObserveBlock(10, 12)
+	require.Equal(t, 2.0, testutil.ToFloat64(BlockLag))
+
+	ObserveBlock(12, 11)
+	require.Equal(t, 12.0, testutil.ToFloat64(LastProcessedBlock))
+	require.Equal(t, 0.0, testutil.ToFloat64(BlockLag))
+}
+
+func TestHandlerServesTextFormat(t *testing.T) {
+	NonceConflicts.Inc()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, strings.Contains(rec.Body.String(), "inso_nonce_conflicts_total"))
+}
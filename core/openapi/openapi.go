@@ -0,0 +1,270 @@
+// Package openapi builds OpenAPI 3 documents from the servers' route tables and the Go types in
+// core/types, so the spec follows the code instead of being written by hand.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"eth-toy-client/core/httpapi"
+)
+
+const Version = "3.0.3"
+
+// Operation documents one route. Request and Response are zero values of the body types, e.g.
+// toytypes.SignTxRequest{}; Response is wrapped in the httpapi envelope unless Produces is set.
+type Operation struct {
+	Method   string // GET, POST, PUT or DELETE
+	Path     string // ServeMux style, e.g. /api/tx/{hash}/receipt
+	Summary  string
+	Tag      string
+	Query    []Param
+	Request  any
+	Response any
+	Produces string // media type of a response outside the envelope, e.g. text/plain
+}
+
+// Param is a query parameter; path parameters are taken from the {name} segments of the path
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	// Security lists the alternative requirements of every operation; an empty one makes auth optional
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps lower-case methods to their operations
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SessionAuth is the Sign-In with Ethereum session token, required only when the server enables SIWE
+const SessionAuth = "siweSession"
+
+// Build documents the operations. Every JSON operation answers with the httpapi envelope, and
+// every operation may fail with the error envelope. Every operation accepts the optional SessionAuth.
+func Build(info Info, operations []Operation) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{SessionAuth: {
+				Type:        "http",
+				Scheme:      "bearer",
+				Description: "Session token from /api/auth/verify, needed when the server requires Sign-In with Ethereum",
+			}},
+		},
+		Security: []map[string][]string{{}, {SessionAuth: {}}},
+	}
+	gen := &generator{schemas: doc.Components.Schemas}
+	errorSchema := envelope("error", gen.schema(reflect.TypeOf(httpapi.APIError{})))
+
+	for _, op := range operations {
+		item, ok := doc.Paths[op.Path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[op.Path] = item
+		}
+		obj := &OperationObject{
+			OperationID: operationID(op.Method, op.Path),
+			Summary:     op.Summary,
+			Responses:   make(map[string]Response),
+		}
+		if op.Tag != "" {
+			obj.Tags = []string{op.Tag}
+		}
+		for _, name := range pathParams(op.Path) {
+			obj.Parameters = append(obj.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+		for _, q := range op.Query {
+			obj.Parameters = append(obj.Parameters, Parameter{Name: q.Name, In: "query", Description: q.Description, Required: q.Required, Schema: &Schema{Type: "string"}})
+		}
+		if op.Request != nil {
+			obj.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: gen.schema(reflect.TypeOf(op.Request))}},
+			}
+		}
+
+		switch {
+		case op.Produces == "application/json":
+			obj.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{op.Produces: {Schema: &Schema{Type: "object"}}}}
+		case op.Produces != "":
+			obj.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{op.Produces: {Schema: &Schema{Type: "string"}}}}
+		default:
+			data := &Schema{}
+			if op.Response != nil {
+				data = gen.schema(reflect.TypeOf(op.Response))
+			}
+			obj.Responses["200"] = Response{Description: "OK", Content: map[string]MediaType{"application/json": {Schema: envelope("data", data)}}}
+		}
+		obj.Responses["default"] = Response{Description: "Error", Content: map[string]MediaType{"application/json": {Schema: errorSchema}}}
+		item[strings.ToLower(op.Method)] = obj
+	}
+	return doc
+}
+
+// Marshal encodes the document the way it is checked in: indented, with a trailing newline
+func Marshal(doc *Document) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// envelope is the httpapi.APIResponse wrapper around data or error
+func envelope(field string, schema *Schema) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "integer"},
+			field:    schema,
+		},
+		Required: []string{"status", field},
+	}
+}
+
+// Missing returns the route patterns, as registered on a ServeMux, that no operation documents.
+// A pattern without a method needs any operation on its path; a subtree pattern ending in "/"
+// needs an operation below it.
+func (doc *Document) Missing(patterns []string) []string {
+	var missing []string
+	for _, pattern := range patterns {
+		method, path := splitPattern(pattern)
+		if !doc.covers(method, path) {
+			missing = append(missing, pattern)
+		}
+	}
+	return missing
+}
+
+func (doc *Document) covers(method, path string) bool {
+	for specPath, item := range doc.Paths {
+		if specPath != path && !(strings.HasSuffix(path, "/") && strings.HasPrefix(specPath, path)) {
+			continue
+		}
+		if method == "" {
+			return true
+		}
+		if _, ok := item[strings.ToLower(method)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Unrouted returns the operations that none of the patterns serve, e.g. routes that were removed
+func (doc *Document) Unrouted(patterns []string) []string {
+	mux := http.NewServeMux()
+	for _, pattern := range patterns {
+		mux.HandleFunc(pattern, func(http.ResponseWriter, *http.Request) {})
+	}
+	var unrouted []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			concrete := strings.NewReplacer("{", "", "}", "").Replace(path)
+			req, _ := http.NewRequest(strings.ToUpper(method), concrete, nil)
+			if _, matched := mux.Handler(req); matched == "" {
+				unrouted = append(unrouted, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(unrouted)
+	return unrouted
+}
+
+func splitPattern(pattern string) (method, path string) {
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		return pattern[:i], strings.TrimSpace(pattern[i+1:])
+	}
+	return "", pattern
+}
+
+func pathParams(path string) []string {
+	var params []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		}
+	}
+	return params
+}
+
+// operationID turns "GET /api/tx/{hash}/receipt" into "getApiTxHashReceipt"
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	upper := true
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
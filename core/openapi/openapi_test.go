@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pingRequest struct {
+	Mode  string `json:"mode" validate:"required,oneof=fast|slow"`
+	Note  string `json:"note,omitempty"`
+	Inner *pingRequest
+	skip  string
+}
+
+func TestBuildDescribesTypesAndEnvelope(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1"}, []Operation{
+		{Method: "POST", Path: "/api/ping/{id}", Request: pingRequest{}, Response: []string{}},
+	})
+
+	op := doc.Paths["/api/ping/{id}"]["post"]
+	require.Equal(t, "postApiPingId", op.OperationID)
+	require.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, op.Parameters)
+	require.Equal(t, "#/components/schemas/pingRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+
+	request := doc.Components.Schemas["pingRequest"]
+	require.Equal(t, []string{"mode"}, request.Required)
+	require.Equal(t, []string{"fast", "slow"}, request.Properties["mode"].Enum)
+	require.Equal(t, "#/components/schemas/pingRequest", request.Properties["Inner"].Ref)
+	require.NotContains(t, request.Properties, "skip")
+
+	data := op.Responses["200"].Content["application/json"].Schema.Properties["data"]
+	require.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, data)
+	require.Equal(t, "#/components/schemas/APIError", op.Responses["default"].Content["application/json"].Schema.Properties["error"].Ref)
+
+	require.Equal(t, "bearer", doc.Components.SecuritySchemes[SessionAuth].Scheme)
+	require.Equal(t, []map[string][]string{{}, {SessionAuth: {}}}, doc.Security, "the session token is optional")
+}
+
+func TestMissingAndUnrouted(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1"}, []Operation{
+		{Method: "GET", Path: "/api/contracts/{address}"},
+		{Method: "POST", Path: "/legacy"},
+		{Method: "DELETE", Path: "/gone"},
+	})
+	patterns := []string{"/api/contracts/", "/legacy", "GET /api/new"}
+
+	require.Equal(t, []string{"GET /api/new"}, doc.Missing(patterns))
+	require.Equal(t, []string{"DELETE /gone"}, doc.Unrouted(patterns))
+}
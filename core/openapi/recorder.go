@@ -0,0 +1,21 @@
+package openapi
+
+import "net/http"
+
+// Recorder stands in for a ServeMux to collect the patterns a route setup registers
+type Recorder struct {
+	patterns []string
+}
+
+func (r *Recorder) Handle(pattern string, _ http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func (r *Recorder) HandleFunc(pattern string, _ func(http.ResponseWriter, *http.Request)) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+// Patterns returns the registered patterns in registration order
+func (r *Recorder) Patterns() []string {
+	return r.patterns
+}
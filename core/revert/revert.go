@@ -0,0 +1,238 @@
+package revert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	toytypes "eth-toy-client/core/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"strings"
+)
+
+const (
+	KindError   = "Error"
+	KindPanic   = "Panic"
+	KindCustom  = "CustomError"
+	KindUnknown = "Unknown"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// executionErrors are the messages of EVM failures that eth_call reports without revert data
+var executionErrors = []string{
+	"out of gas",
+	"invalid opcode",
+	"invalid jump destination",
+	"stack underflow",
+	"stack limit reached",
+	"max call depth exceeded",
+	"write protection",
+	"return data out of bounds",
+	"gas uint64 overflow",
+	"insufficient balance for transfer",
+	"insufficient funds",
+	"intrinsic gas too low",
+	"contract address collision",
+	"max code size exceeded",
+	"max initcode size exceeded",
+	"invalid code",
+	"nonce uint64 overflow",
+	"execution reverted",
+}
+
+// PanicReasons maps Solidity Panic(uint256) codes to readable messages
+var PanicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "pop on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// Decode turns raw revert data into a RevertReason. Error(string) and Panic(uint256)
+// are decoded without an ABI; custom errors are matched against the given ABIs in order.
+func Decode(data []byte, abis ...*abi.ABI) *toytypes.RevertReason {
+	reason := &toytypes.RevertReason{
+		Kind: KindUnknown,
+		Data: hexutil.Encode(data),
+	}
+	if len(data) == 0 {
+		reason.Data = ""
+		reason.Message = "execution reverted without reason"
+		return reason
+	}
+	if len(data) < 4 {
+		reason.Message = "execution reverted with malformed data"
+		return reason
+	}
+	reason.Selector = hexutil.Encode(data[:4])
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		msg, err := abi.UnpackRevert(data)
+		if err != nil {
+			reason.Message = fmt.Sprintf("failed to unpack Error(string): %v", err)
+			return reason
+		}
+		reason.Kind = KindError
+		reason.Message = msg
+		return reason
+
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) != 36 {
+			reason.Message = "failed to unpack Panic(uint256): unexpected data length"
+			return reason
+		}
+		code := new(big.Int).SetBytes(data[4:])
+		reason.Kind = KindPanic
+		if !code.IsUint64() {
+			reason.Message = fmt.Sprintf("unknown panic code: %#x", code)
+			return reason
+		}
+		c := code.Uint64()
+		reason.PanicCode = &c
+		if msg, ok := PanicReasons[c]; ok {
+			reason.Message = msg
+		} else {
+			reason.Message = fmt.Sprintf("unknown panic code: %#x", c)
+		}
+		return reason
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+	for _, parsed := range abis {
+		if parsed == nil {
+			continue
+		}
+		abiErr, err := parsed.ErrorByID(selector)
+		if err != nil {
+			continue
+		}
+		values, err := abiErr.Inputs.Unpack(data[4:])
+		if err != nil {
+			continue
+		}
+		args := make(map[string]interface{}, len(values))
+		for i, input := range abiErr.Inputs {
+			args[input.Name] = values[i]
+		}
+		reason.Kind = KindCustom
+		reason.ErrorName = abiErr.Name
+		reason.Args = args
+		reason.Message = abiErr.Sig
+		return reason
+	}
+
+	reason.Message = fmt.Sprintf("unknown custom error %s", reason.Selector)
+	return reason
+}
+
+// Replay re-executes tx with eth_call at blockNumber and returns the revert data.
+// A nil result with nil error means the call did not revert.
+func Replay(ctx context.Context, client *ethclient.Client, tx *types.Transaction, blockNumber *big.Int) ([]byte, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %w", err)
+	}
+
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	}
+
+	_, err = client.CallContract(ctx, msg, blockNumber)
+	if err == nil {
+		return nil, nil
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			data, decodeErr := hexutil.Decode(hexData)
+			if decodeErr == nil {
+				return data, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// Explain replays the transaction of a failed receipt at its parent block and decodes why it reverted.
+func Explain(ctx context.Context, client *ethclient.Client, receipt *types.Receipt, abis ...*abi.ABI) (*toytypes.RevertReason, error) {
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tx %s: %w", receipt.TxHash.Hex(), err)
+	}
+
+	parent := new(big.Int).Sub(receipt.BlockNumber, common.Big1)
+	data, err := Replay(ctx, client, tx, parent)
+	if err != nil {
+		if !IsExecutionError(err) {
+			return nil, fmt.Errorf("failed to replay tx %s: %w", receipt.TxHash.Hex(), err)
+		}
+		// Out-of-gas and friends carry no revert data, the node error is the reason.
+		return &toytypes.RevertReason{Kind: KindUnknown, Message: err.Error()}, nil
+	}
+	if data == nil {
+		return &toytypes.RevertReason{
+			Kind:    KindUnknown,
+			Message: "transaction did not revert when replayed at parent block",
+		}, nil
+	}
+	return Decode(data, abis...), nil
+}
+
+// IsExecutionError tells an EVM failure reported by the node, such as out of gas or an invalid
+// opcode, from transport and RPC failures like an unreachable node or a block without state.
+func IsExecutionError(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	msg := rpcErr.Error()
+	for _, execErr := range executionErrors {
+		if strings.Contains(msg, execErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders a reason the way it shows up in error messages
+func String(reason *toytypes.RevertReason) string {
+	if reason == nil {
+		return ""
+	}
+	if reason.Kind == KindCustom && len(reason.Args) > 0 {
+		return fmt.Sprintf("%s: %s %v", reason.Kind, reason.ErrorName, reason.Args)
+	}
+	return fmt.Sprintf("%s: %s", reason.Kind, reason.Message)
+}
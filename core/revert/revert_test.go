@@ -0,0 +1,138 @@
+package revert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+const insufficientBalanceABI = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`
+
+func packWithSelector(t *testing.T, selector []byte, typ string, value interface{}) []byte {
+	abiType, err := abi.NewType(typ, "", nil)
+	require.NoError(t, err)
+	packed, err := abi.Arguments{{Type: abiType}}.Pack(value)
+	require.NoError(t, err)
+	return append(append([]byte{}, selector...), packed...)
+}
+
+func TestDecodeErrorString(t *testing.T) {
+	data := packWithSelector(t, errorSelector, "string", "Counter is already zero")
+
+	reason := Decode(data)
+	require.Equal(t, KindError, reason.Kind)
+	require.Equal(t, "Counter is already zero", reason.Message)
+	require.Equal(t, "0x08c379a0", reason.Selector)
+	t.Logf("✅ %s", String(reason))
+}
+
+func TestDecodePanicCode(t *testing.T) {
+	data := packWithSelector(t, panicSelector, "uint256", big.NewInt(0x11))
+
+	reason := Decode(data)
+	require.Equal(t, KindPanic, reason.Kind)
+	require.NotNil(t, reason.PanicCode)
+	require.Equal(t, uint64(0x11), *reason.PanicCode)
+	require.Equal(t, "arithmetic underflow or overflow", reason.Message)
+}
+
+func TestDecodeUnknownPanicCode(t *testing.T) {
+	data := packWithSelector(t, panicSelector, "uint256", big.NewInt(0x99))
+
+	reason := Decode(data)
+	require.Equal(t, KindPanic, reason.Kind)
+	require.Contains(t, reason.Message, "unknown panic code")
+}
+
+func TestDecodeCustomError(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(insufficientBalanceABI))
+	require.NoError(t, err)
+	customErr := parsed.Errors["InsufficientBalance"]
+
+	args, err := customErr.Inputs.Pack(big.NewInt(5), big.NewInt(10))
+	require.NoError(t, err)
+	data := append(common.CopyBytes(customErr.ID[:4]), args...)
+
+	reason := Decode(data, nil, &parsed)
+	require.Equal(t, KindCustom, reason.Kind)
+	require.Equal(t, "InsufficientBalance", reason.ErrorName)
+	require.Equal(t, "InsufficientBalance(uint256,uint256)", reason.Message)
+	require.Equal(t, big.NewInt(5), reason.Args["available"])
+	require.Equal(t, big.NewInt(10), reason.Args["required"])
+}
+
+func TestDecodeCustomErrorWithoutABI(t *testing.T) {
+	data := common.FromHex("0xdeadbeef")
+
+	reason := Decode(data)
+	require.Equal(t, KindUnknown, reason.Kind)
+	require.Equal(t, "0xdeadbeef", reason.Selector)
+	require.Contains(t, reason.Message, "unknown custom error")
+}
+
+func TestDecodeEmptyData(t *testing.T) {
+	reason := Decode(nil)
+	require.Equal(t, KindUnknown, reason.Kind)
+	require.Equal(t, "execution reverted without reason", reason.Message)
+	require.Empty(t, reason.Data)
+}
+
+// fakeEth serves the failed tx and answers every eth_call with callErr
+type fakeEth struct {
+	tx      *types.Transaction
+	callErr error
+}
+
+func (f *fakeEth) GetTransactionByHash(hash common.Hash) (*types.Transaction, error) {
+	return f.tx, nil
+}
+
+func (f *fakeEth) Call(args json.RawMessage, block string) (hexutil.Bytes, error) {
+	return nil, f.callErr
+}
+
+func TestExplainSeparatesExecutionFromNodeErrors(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1337), Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), To: &to,
+	})
+	require.NoError(t, err)
+	receipt := &types.Receipt{TxHash: tx.Hash(), BlockNumber: big.NewInt(5)}
+
+	explain := func(callErr error) (*toytypes.RevertReason, error) {
+		server := rpc.NewServer()
+		t.Cleanup(server.Stop)
+		require.NoError(t, server.RegisterName("eth", &fakeEth{tx: tx, callErr: callErr}))
+		return Explain(context.Background(), ethclient.NewClient(rpc.DialInProc(server)), receipt)
+	}
+
+	reason, err := explain(errors.New("out of gas"))
+	require.NoError(t, err)
+	require.Equal(t, KindUnknown, reason.Kind)
+	require.Equal(t, "out of gas", reason.Message)
+
+	reason, err = explain(errors.New("invalid opcode: INVALID"))
+	require.NoError(t, err)
+	require.Equal(t, "invalid opcode: INVALID", reason.Message)
+
+	_, err = explain(errors.New("header not found"))
+	require.ErrorContains(t, err, "header not found")
+
+	require.False(t, IsExecutionError(context.Canceled))
+	require.False(t, IsExecutionError(errors.New("dial tcp 127.0.0.1:8545: connect: connection refused")))
+}
@@ -0,0 +1,176 @@
+package rpcproxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"eth-toy-client/core/httpapi"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Entry is one recorded call and its answer, a line of a recording
+type Entry struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// Recorder writes calls and their answers to a JSON Lines file, one Entry per line
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecorder creates or truncates the recording at path
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RPC recording: %w", err)
+	}
+	return &Recorder{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (r *Recorder) Add(call, answer *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.encoder.Encode(Entry{Method: call.Method, Params: call.Params, Result: answer.Result, Error: answer.Error})
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// Replay answers calls from a recording, standing in for the node in tests. Calls match on method
+// and params; identical calls get their recorded answers in order, the last one repeating once
+// they run out. It serves HTTP POSTs and WebSocket connections, but replays no subscriptions.
+type Replay struct {
+	mu      sync.Mutex
+	answers map[string][]Entry
+	served  map[string]int
+}
+
+func NewReplay(entries []Entry) *Replay {
+	r := &Replay{answers: make(map[string][]Entry), served: make(map[string]int)}
+	for _, entry := range entries {
+		key := replayKey(entry.Method, entry.Params)
+		r.answers[key] = append(r.answers[key], entry)
+	}
+	return r
+}
+
+// LoadReplay reads a recording written by Recorder
+func LoadReplay(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open RPC recording: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	decoder := json.NewDecoder(file)
+	for {
+		var entry Entry
+		if err := decoder.Decode(&entry); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: entry %d: %w", path, len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return NewReplay(entries), nil
+}
+
+func (r *Replay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if websocket.IsWebSocketUpgrade(req) {
+		r.serveWS(w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST and WebSocket upgrades are allowed")
+		return
+	}
+	raw, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxBodyBytes))
+	if err != nil {
+		httpapi.Fail(w, httpapi.ErrInvalidRequest, err.Error())
+		return
+	}
+	if answer := r.answerRaw(raw); answer != nil {
+		writeJSON(w, answer)
+	}
+}
+
+func (r *Replay) serveWS(w http.ResponseWriter, req *http.Request) {
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if answer := r.answerRaw(raw); answer != nil {
+			if err := conn.WriteJSON(answer); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// answerRaw answers a single message or a batch; it returns nil when there is nothing to answer
+func (r *Replay) answerRaw(raw []byte) any {
+	calls, batch, err := decodeMessages(raw)
+	if err != nil {
+		return errorMessage(nil, CodeParseError, err.Error())
+	}
+	answers := make([]*Message, 0, len(calls))
+	for _, call := range calls {
+		if call.isCall() {
+			answers = append(answers, r.answer(call))
+		}
+	}
+	switch {
+	case batch:
+		return answers
+	case len(answers) == 1:
+		return answers[0]
+	default:
+		return nil
+	}
+}
+
+func (r *Replay) answer(call *Message) *Message {
+	key := replayKey(call.Method, call.Params)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recorded := r.answers[key]
+	if len(recorded) == 0 {
+		logger.Warn("⚠️ No recorded answer", "method", call.Method, "params", string(call.Params))
+		return errorMessage(call.ID, CodeServerError, "no recorded answer for "+call.Method)
+	}
+	i := min(r.served[key], len(recorded)-1)
+	r.served[key]++
+	return &Message{JSONRPC: "2.0", ID: call.ID, Result: recorded[i].Result, Error: recorded[i].Error}
+}
+
+// replayKey identifies a call by method and compacted params; absent params equal []
+func replayKey(method string, params json.RawMessage) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, params); err != nil || compact.Len() == 0 {
+		compact.Reset()
+		compact.WriteString("[]")
+	}
+	return method + " " + compact.String()
+}
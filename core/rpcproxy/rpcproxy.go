@@ -0,0 +1,273 @@
+// Package rpcproxy is a JSON-RPC reverse proxy to the node over HTTP and WebSocket. It can answer
+// selected methods itself, logs every call and can record calls and answers for Replay.
+package rpcproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/metrics"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var logger = logutil.For("rpcproxy")
+
+const (
+	TransportHTTP = "http"
+	TransportWS   = "ws"
+
+	maxBodyBytes = 10 << 20
+)
+
+// JSON-RPC error codes the proxy answers with itself
+const (
+	CodeParseError  = -32700
+	CodeServerError = -32000
+)
+
+// codeMethodNotFound is how the node answers a method it does not serve
+const codeMethodNotFound = -32601
+
+// otherMethod labels calls of methods neither an interceptor nor the node serves, so clients
+// cannot grow the metrics with made-up method names
+const otherMethod = "other"
+
+// Message is a JSON-RPC 2.0 request, response or notification
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// isCall tells a request that expects an answer from a notification or a response
+func (m *Message) isCall() bool {
+	return m.Method != "" && len(m.ID) > 0
+}
+
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Interceptor answers a call instead of the node. It returns handled false to let the call through;
+// an error becomes the JSON-RPC error of the answer, with code -32000 unless it is an *Error.
+type Interceptor func(ctx context.Context, params json.RawMessage) (result any, handled bool, err error)
+
+// Proxy forwards JSON-RPC calls to the node: HTTP POSTs to its HTTP endpoint and WebSocket
+// connections to its WebSocket endpoint. Interceptors and the recorder are set before serving.
+type Proxy struct {
+	httpURL      string
+	wsURL        string
+	client       *http.Client
+	interceptors map[string]Interceptor
+	recorder     *Recorder
+}
+
+func New(httpURL, wsURL string) *Proxy {
+	return &Proxy{
+		httpURL:      httpURL,
+		wsURL:        wsURL,
+		client:       &http.Client{Timeout: time.Minute},
+		interceptors: make(map[string]Interceptor),
+	}
+}
+
+// Intercept routes the calls of method to fn before they reach the node
+func (p *Proxy) Intercept(method string, fn Interceptor) {
+	p.interceptors[method] = fn
+}
+
+// Record writes every answered call to rec
+func (p *Proxy) Record(rec *Recorder) {
+	p.recorder = rec
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		p.serveWS(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST and WebSocket upgrades are allowed")
+		return
+	}
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		httpapi.Fail(w, httpapi.ErrInvalidRequest, err.Error())
+		return
+	}
+
+	calls, batch, err := decodeMessages(raw)
+	if err != nil {
+		writeJSON(w, errorMessage(nil, CodeParseError, err.Error()))
+		return
+	}
+	answers := make([]*Message, 0, len(calls))
+	for _, call := range calls {
+		if answer := p.call(r.Context(), TransportHTTP, call); answer != nil {
+			answers = append(answers, answer)
+		}
+	}
+	switch {
+	case batch:
+		writeJSON(w, answers)
+	case len(answers) == 1:
+		writeJSON(w, answers[0])
+	}
+}
+
+// call answers one call through an interceptor or the node's HTTP endpoint; notifications answer nil
+func (p *Proxy) call(ctx context.Context, transport string, call *Message) *Message {
+	start := time.Now()
+	answer, intercepted := p.intercept(ctx, call)
+	if !intercepted {
+		var err error
+		if answer, err = p.forward(ctx, call); err != nil {
+			answer = errorMessage(call.ID, CodeServerError, err.Error())
+		}
+	}
+	p.observe(ctx, transport, call, answer, intercepted, time.Since(start))
+	if !call.isCall() {
+		return nil
+	}
+	return answer
+}
+
+// forward posts a single call to the node's HTTP endpoint
+func (p *Proxy) forward(ctx context.Context, call *Message) (*Message, error) {
+	body, err := json.Marshal(call)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.httpURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("node unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil // notifications get no answer
+	}
+	var answer Message
+	if err := json.Unmarshal(raw, &answer); err != nil {
+		return nil, fmt.Errorf("node answered %s: %s", resp.Status, bytes.TrimSpace(raw))
+	}
+	return &answer, nil
+}
+
+// intercepts tells whether any of the calls has an interceptor
+func (p *Proxy) intercepts(calls []*Message) bool {
+	for _, call := range calls {
+		if _, ok := p.interceptors[call.Method]; ok && call.isCall() {
+			return true
+		}
+	}
+	return false
+}
+
+// intercept runs the interceptor of the call's method; ok is false when the node has to answer
+func (p *Proxy) intercept(ctx context.Context, call *Message) (*Message, bool) {
+	fn, ok := p.interceptors[call.Method]
+	if !ok || !call.isCall() {
+		return nil, false
+	}
+	result, handled, err := fn(ctx, call.Params)
+	if err != nil {
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return &Message{JSONRPC: "2.0", ID: call.ID, Error: rpcErr}, true
+		}
+		return errorMessage(call.ID, CodeServerError, err.Error()), true
+	}
+	if !handled {
+		return nil, false
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return errorMessage(call.ID, CodeServerError, err.Error()), true
+	}
+	return &Message{JSONRPC: "2.0", ID: call.ID, Result: raw}, true
+}
+
+// observe logs, counts and records a call once its answer is known
+func (p *Proxy) observe(ctx context.Context, transport string, call, answer *Message, intercepted bool, took time.Duration) {
+	result := "ok"
+	args := []any{"method", call.Method, "transport", transport, "intercepted", intercepted, "duration", took.Round(time.Microsecond)}
+	if answer != nil && answer.Error != nil {
+		result = "error"
+		args = append(args, "error", answer.Error.Message)
+	}
+	metrics.RPCCalls.WithLabelValues(p.methodLabel(call, answer), transport, result).Inc()
+	logger.InfoContext(ctx, "🔀 RPC call", args...)
+
+	if p.recorder != nil && answer != nil {
+		if err := p.recorder.Add(call, answer); err != nil {
+			logger.ErrorContext(ctx, "❌ Failed to record RPC call", "method", call.Method, "error", err)
+		}
+	}
+}
+
+// methodLabel is the call's method when an interceptor or the node serves it, otherMethod otherwise
+func (p *Proxy) methodLabel(call, answer *Message) string {
+	if _, ok := p.interceptors[call.Method]; ok {
+		return call.Method
+	}
+	if answer == nil || (answer.Error != nil && answer.Error.Code == codeMethodNotFound) {
+		return otherMethod
+	}
+	return call.Method
+}
+
+// decodeMessages reads a single message or a batch
+func decodeMessages(raw []byte) ([]*Message, bool, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var batch []*Message
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			return nil, true, err
+		}
+		return batch, true, nil
+	}
+	var msg Message
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil, false, err
+	}
+	return []*Message{&msg}, false, nil
+}
+
+func errorMessage(id json.RawMessage, code int, message string) *Message {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Message{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
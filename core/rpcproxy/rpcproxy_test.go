@@ -0,0 +1,151 @@
+package rpcproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"eth-toy-client/core/metrics"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNode answers eth_blockNumber and eth_chainId
+type fakeNode struct{}
+
+func (fakeNode) BlockNumber() hexutil.Uint64 { return 42 }
+func (fakeNode) ChainId() hexutil.Uint64     { return 1337 }
+
+func startNode(t *testing.T) (httpURL, wsURL string) {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", fakeNode{}))
+	httpNode := httptest.NewServer(srv)
+	wsNode := httptest.NewServer(srv.WebsocketHandler([]string{"*"}))
+	t.Cleanup(func() {
+		httpNode.Close()
+		wsNode.Close()
+		srv.Stop()
+	})
+	return httpNode.URL, toWS(wsNode.URL)
+}
+
+func toWS(httpURL string) string {
+	return "ws" + strings.TrimPrefix(httpURL, "http")
+}
+
+func dial(t *testing.T, url string) *rpc.Client {
+	client, err := rpc.Dial(url)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestProxyForwardsInterceptsAndRecords(t *testing.T) {
+	nodeHTTP, nodeWS := startNode(t)
+	recording := filepath.Join(t.TempDir(), "rpc.jsonl")
+	rec, err := NewRecorder(recording)
+	require.NoError(t, err)
+
+	proxy := New(nodeHTTP, nodeWS)
+	proxy.Record(rec)
+	proxy.Intercept("eth_accounts", func(ctx context.Context, params json.RawMessage) (any, bool, error) {
+		return []string{"0x00000000000000000000000000000000000000aa"}, true, nil
+	})
+	proxy.Intercept("eth_chainId", func(ctx context.Context, params json.RawMessage) (any, bool, error) {
+		return nil, false, nil // passes through
+	})
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	for _, url := range []string{server.URL, toWS(server.URL)} {
+		client := dial(t, url)
+		var block, chainID hexutil.Uint64
+		require.NoError(t, client.Call(&block, "eth_blockNumber"), url)
+		require.EqualValues(t, 42, block)
+		require.NoError(t, client.Call(&chainID, "eth_chainId"), url)
+		require.EqualValues(t, 1337, chainID)
+
+		var accounts []string
+		require.NoError(t, client.Call(&accounts, "eth_accounts"), url)
+		require.Equal(t, []string{"0x00000000000000000000000000000000000000aa"}, accounts)
+		require.Error(t, client.Call(nil, "eth_unknown"), url)
+
+		batch := []rpc.BatchElem{{Method: "eth_blockNumber", Result: &block}, {Method: "eth_accounts", Result: &accounts}}
+		require.NoError(t, client.BatchCall(batch), url)
+		require.NoError(t, batch[0].Error)
+		require.NoError(t, batch[1].Error)
+	}
+	require.NoError(t, rec.Close())
+
+	replay, err := LoadReplay(recording)
+	require.NoError(t, err)
+	node := httptest.NewServer(replay)
+	defer node.Close()
+	for _, url := range []string{node.URL, toWS(node.URL)} {
+		client := dial(t, url)
+		var block hexutil.Uint64
+		require.NoError(t, client.Call(&block, "eth_blockNumber"), url)
+		require.EqualValues(t, 42, block)
+		var accounts []string
+		require.NoError(t, client.Call(&accounts, "eth_accounts"), url)
+		require.Len(t, accounts, 1)
+		err := client.Call(nil, "eth_unknown")
+		require.ErrorContains(t, err, "the method eth_unknown does not exist", "recorded errors replay too")
+		require.ErrorContains(t, client.Call(nil, "eth_gasPrice"), "no recorded answer")
+	}
+}
+
+func TestReplayServesRepeatedCallsInOrder(t *testing.T) {
+	replay := NewReplay([]Entry{
+		{Method: "eth_blockNumber", Params: json.RawMessage(`[]`), Result: json.RawMessage(`"0x1"`)},
+		{Method: "eth_blockNumber", Result: json.RawMessage(`"0x2"`)},
+		{Method: "eth_getBalance", Params: json.RawMessage(`["0xaa", "latest"]`), Result: json.RawMessage(`"0x10"`)},
+	})
+	node := httptest.NewServer(replay)
+	defer node.Close()
+	client := dial(t, node.URL)
+
+	var got []string
+	for range 3 {
+		var block string
+		require.NoError(t, client.Call(&block, "eth_blockNumber"))
+		got = append(got, block)
+	}
+	require.Equal(t, []string{"0x1", "0x2", "0x2"}, got, "the last answer repeats")
+
+	var balance string
+	require.NoError(t, client.Call(&balance, "eth_getBalance", "0xaa", "latest"))
+	require.Equal(t, "0x10", balance)
+}
+
+func TestMetricsLabelUnknownMethodsAsOther(t *testing.T) {
+	nodeHTTP, nodeWS := startNode(t)
+	proxy := New(nodeHTTP, nodeWS)
+	proxy.Intercept("dev_hello", func(ctx context.Context, params json.RawMessage) (any, bool, error) {
+		return "hi", true, nil
+	})
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+	client := dial(t, server.URL)
+	count := func(method, result string) float64 {
+		return testutil.ToFloat64(metrics.RPCCalls.WithLabelValues(method, TransportHTTP, result))
+	}
+	blockNumber, hello, other := count("eth_blockNumber", "ok"), count("dev_hello", "ok"), count(otherMethod, "error")
+
+	var block hexutil.Uint64
+	var greeting string
+	require.NoError(t, client.Call(&block, "eth_blockNumber"))
+	require.NoError(t, client.Call(&greeting, "dev_hello"))
+	require.Error(t, client.Call(nil, "eth_madeUp1"))
+	require.Error(t, client.Call(nil, "eth_madeUp2"))
+
+	require.Equal(t, blockNumber+1, count("eth_blockNumber", "ok"))
+	require.Equal(t, hello+1, count("dev_hello", "ok"), "intercepted methods keep their name")
+	require.Equal(t, other+2, count(otherMethod, "error"))
+	require.False(t, metrics.RPCCalls.DeleteLabelValues("eth_madeUp1", TransportHTTP, "error"), "no series per made-up method")
+}
@@ -0,0 +1,171 @@
+package rpcproxy
+
+import (
+	"context"
+	"eth-toy-client/core/httpapi"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// upgrader accepts any origin, like a dev node started with --ws.origins "*"
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// wsSession pairs a client connection with its own connection to the node. Intercepted calls are
+// answered directly; everything else, subscription notifications included, passes through.
+// Batches over WebSocket are forwarded whole unless they contain an intercepted call.
+type wsSession struct {
+	proxy    *Proxy
+	ctx      context.Context
+	client   *websocket.Conn
+	upstream *websocket.Conn
+
+	writeMu sync.Mutex // guards writes to client
+
+	mu      sync.Mutex
+	pending map[string]pendingCall // forwarded calls by raw ID, until the node answers
+}
+
+type pendingCall struct {
+	call  *Message
+	start time.Time
+}
+
+func (p *Proxy) serveWS(w http.ResponseWriter, r *http.Request) {
+	upstream, _, err := websocket.DefaultDialer.DialContext(r.Context(), p.wsURL, nil)
+	if err != nil {
+		logger.ErrorContext(r.Context(), "❌ Failed to connect to node WebSocket", "url", p.wsURL, "error", err)
+		httpapi.Fail(w, httpapi.ErrNodeError, "Node WebSocket unreachable: "+err.Error())
+		return
+	}
+	defer upstream.Close()
+
+	client, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader has answered
+	}
+	defer client.Close()
+	logger.DebugContext(r.Context(), "🔌 RPC WebSocket opened")
+
+	s := &wsSession{
+		proxy:    p,
+		ctx:      r.Context(),
+		client:   client,
+		upstream: upstream,
+		pending:  make(map[string]pendingCall),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.pumpUpstream()
+	}()
+	s.pumpClient()
+	upstream.Close()
+	<-done
+	logger.DebugContext(r.Context(), "🔌 RPC WebSocket closed")
+}
+
+// pumpClient reads the client's calls until either side closes
+func (s *wsSession) pumpClient() {
+	for {
+		_, raw, err := s.client.ReadMessage()
+		if err != nil {
+			return
+		}
+		calls, batch, err := decodeMessages(raw)
+		if err != nil {
+			s.writeClient(errorMessage(nil, CodeParseError, err.Error()))
+			continue
+		}
+
+		if batch && s.proxy.intercepts(calls) {
+			s.answerBatch(calls)
+			continue
+		}
+
+		var forward []*Message
+		for _, call := range calls {
+			start := time.Now()
+			if answer, ok := s.proxy.intercept(s.ctx, call); ok {
+				s.proxy.observe(s.ctx, TransportWS, call, answer, true, time.Since(start))
+				s.writeClient(answer)
+				continue
+			}
+			if call.isCall() {
+				s.mu.Lock()
+				s.pending[string(call.ID)] = pendingCall{call: call, start: start}
+				s.mu.Unlock()
+			}
+			forward = append(forward, call)
+		}
+		switch {
+		case len(forward) == 0:
+			continue
+		case batch:
+			err = s.upstream.WriteJSON(forward)
+		default:
+			err = s.upstream.WriteJSON(forward[0])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// pumpUpstream relays the node's messages verbatim and observes the answers to forwarded calls
+func (s *wsSession) pumpUpstream() {
+	defer s.client.Close()
+	for {
+		messageType, raw, err := s.upstream.ReadMessage()
+		if err != nil {
+			return
+		}
+		if answers, _, err := decodeMessages(raw); err == nil {
+			for _, answer := range answers {
+				if pc, ok := s.take(answer.ID); ok {
+					s.proxy.observe(s.ctx, TransportWS, pc.call, answer, false, time.Since(pc.start))
+				}
+			}
+		}
+
+		s.writeMu.Lock()
+		err = s.client.WriteMessage(messageType, raw)
+		s.writeMu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// answerBatch answers a batch with intercepted calls as one message, as clients expect. The other
+// calls go to the node's HTTP endpoint, so their answers need not be picked out of the stream.
+func (s *wsSession) answerBatch(calls []*Message) {
+	answers := make([]*Message, 0, len(calls))
+	for _, call := range calls {
+		if answer := s.proxy.call(s.ctx, TransportWS, call); answer != nil {
+			answers = append(answers, answer)
+		}
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.client.WriteJSON(answers)
+}
+
+func (s *wsSession) take(id []byte) (pendingCall, bool) {
+	if len(id) == 0 {
+		return pendingCall{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pc, ok := s.pending[string(id)]
+	delete(s.pending, string(id))
+	return pc, ok
+}
+
+func (s *wsSession) writeClient(msg *Message) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.client.WriteJSON(msg)
+}
@@ -0,0 +1,237 @@
+package siwe
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"eth-toy-client/accounts"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	toytypes "eth-toy-client/core/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	NoncePath  = "/api/auth/nonce"
+	VerifyPath = "/api/auth/verify"
+	LogoutPath = "/api/auth/logout"
+
+	nonceTTL = 5 * time.Minute
+)
+
+var logger = logutil.For("siwe")
+
+// publicPrefixes stay reachable without a session
+var publicPrefixes = []string{"/ping", "/healthz", "/readyz", "/metrics", "/swagger/", "/api/auth/"}
+
+type Config struct {
+	Domain     string           // expected message domain, defaults to the request's Host
+	ChainID    uint64           // expected chain ID, 0 accepts any
+	Allowlist  []common.Address // empty allows any address that proves ownership
+	SessionTTL time.Duration
+}
+
+type session struct {
+	address   common.Address
+	expiresAt time.Time
+}
+
+// Authenticator issues nonces, verifies signed messages and guards routes with session tokens
+type Authenticator struct {
+	config    Config
+	allowlist map[common.Address]bool
+	now       func() time.Time
+
+	mu       sync.Mutex
+	nonces   map[string]time.Time // nonce → expiry, removed once used
+	sessions map[string]session
+}
+
+func NewAuthenticator(config Config) *Authenticator {
+	if config.SessionTTL == 0 {
+		config.SessionTTL = time.Hour
+	}
+	allowlist := make(map[common.Address]bool, len(config.Allowlist))
+	for _, addr := range config.Allowlist {
+		allowlist[addr] = true
+	}
+	return &Authenticator{
+		config:    config,
+		allowlist: allowlist,
+		now:       time.Now,
+		nonces:    make(map[string]time.Time),
+		sessions:  make(map[string]session),
+	}
+}
+
+// NewNonce returns a single-use nonce for the next sign-in message
+func (a *Authenticator) NewNonce() (string, time.Time) {
+	nonce := randomHex(16)
+	expiresAt := a.now().Add(nonceTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for n, expiry := range a.nonces {
+		if a.now().After(expiry) {
+			delete(a.nonces, n)
+		}
+	}
+	a.nonces[nonce] = expiresAt
+	return nonce, expiresAt
+}
+
+// Verify checks a signed message for the given host and opens a session for its address.
+// It returns the bearer token of the new session.
+func (a *Authenticator) Verify(rawMessage string, signature []byte, host string) (string, error) {
+	msg, err := ParseMessage(rawMessage)
+	if err != nil {
+		return "", err
+	}
+	domain := a.config.Domain
+	if domain == "" {
+		domain = host
+	}
+	if msg.Domain != domain {
+		return "", fmt.Errorf("domain %q does not match %q", msg.Domain, domain)
+	}
+	if a.config.ChainID != 0 && msg.ChainID != a.config.ChainID {
+		return "", fmt.Errorf("chain ID %d does not match %d", msg.ChainID, a.config.ChainID)
+	}
+	now := a.now()
+	if err := msg.ValidAt(now); err != nil {
+		return "", err
+	}
+
+	signer, err := accounts.RecoverText([]byte(rawMessage), signature)
+	if err != nil {
+		return "", err
+	}
+	if signer != msg.Address {
+		return "", errors.New("signature does not match the message address")
+	}
+	if len(a.allowlist) > 0 && !a.allowlist[signer] {
+		return "", fmt.Errorf("address %s is not allowed", signer.Hex())
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	expiry, ok := a.nonces[msg.Nonce]
+	if !ok || now.After(expiry) {
+		return "", errors.New("unknown or expired nonce")
+	}
+	delete(a.nonces, msg.Nonce)
+
+	token := randomHex(32)
+	a.sessions[token] = session{address: signer, expiresAt: now.Add(a.config.SessionTTL)}
+	return token, nil
+}
+
+// Session returns the address and expiry behind a bearer token
+func (a *Authenticator) Session(token string) (common.Address, time.Time, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, ok := a.sessions[token]
+	if !ok {
+		return common.Address{}, time.Time{}, false
+	}
+	if a.now().After(s.expiresAt) {
+		delete(a.sessions, token)
+		return common.Address{}, time.Time{}, false
+	}
+	return s.address, s.expiresAt, true
+}
+
+func (a *Authenticator) Logout(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.sessions, token)
+}
+
+// Protect serves the auth endpoints and requires a valid session for every other non-public route
+func (a *Authenticator) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case NoncePath:
+			a.handleNonce(w, r)
+			return
+		case VerifyPath:
+			a.handleVerify(w, r)
+			return
+		case LogoutPath:
+			a.Logout(bearerToken(r))
+			httpapi.WriteOK(w, &struct{}{})
+			return
+		}
+		for _, prefix := range publicPrefixes {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if _, _, ok := a.Session(bearerToken(r)); !ok {
+			httpapi.Fail(w, httpapi.ErrUnauthorized, "Sign in with Ethereum via "+NoncePath+" and "+VerifyPath)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Authenticator) handleNonce(w http.ResponseWriter, r *http.Request) {
+	nonce, expiresAt := a.NewNonce()
+	httpapi.WriteOK(w, &toytypes.SiweNonceResponse{Nonce: nonce, ExpiresAt: expiresAt})
+}
+
+func (a *Authenticator) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST is allowed")
+		return
+	}
+	var req toytypes.SiweVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpapi.Fail(w, httpapi.ErrInvalidRequest, "Invalid JSON payload")
+		return
+	}
+	if err := toytypes.Validate(&req); err != nil {
+		httpapi.FailWithDetails(w, httpapi.ErrInvalidParameter, err.Error(), map[string]any{"fields": err})
+		return
+	}
+	signature, err := toytypes.DecodeHex(req.Signature)
+	if err != nil {
+		httpapi.Fail(w, httpapi.ErrInvalidSignature, err.Error())
+		return
+	}
+
+	token, err := a.Verify(req.Message, signature, r.Host)
+	if err != nil {
+		logger.WarnContext(r.Context(), "⛔ SIWE sign-in rejected", "error", err)
+		httpapi.Fail(w, httpapi.ErrUnauthorized, err.Error())
+		return
+	}
+	address, expiresAt, _ := a.Session(token)
+	logutil.Annotate(r.Context(), "address", address.Hex())
+	logger.InfoContext(r.Context(), "🔐 SIWE session opened")
+	httpapi.WriteOK(w, &toytypes.SiweSessionResponse{
+		Token:     token,
+		Address:   address.Hex(),
+		ExpiresAt: expiresAt,
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,160 @@
+package siwe
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const preambleSuffix = " wants you to sign in with your Ethereum account:"
+
+// Message is an EIP-4361 Sign-In with Ethereum message
+type Message struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// String renders the message in the exact text form that wallets sign
+func (m *Message) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + preambleSuffix + "\n")
+	b.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n\n")
+	}
+	fmt.Fprintf(&b, "URI: %s\n", m.URI)
+	fmt.Fprintf(&b, "Version: %s\n", m.Version)
+	fmt.Fprintf(&b, "Chain ID: %d\n", m.ChainID)
+	fmt.Fprintf(&b, "Nonce: %s\n", m.Nonce)
+	fmt.Fprintf(&b, "Issued At: %s", m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		fmt.Fprintf(&b, "\nNot Before: %s", m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// ParseMessage parses the text form of an EIP-4361 message
+func ParseMessage(raw string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], preambleSuffix) {
+		return nil, errors.New("missing sign-in preamble")
+	}
+	msg := &Message{Domain: strings.TrimSuffix(lines[0], preambleSuffix)}
+	if msg.Domain == "" {
+		return nil, errors.New("missing domain")
+	}
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("invalid address %q", lines[1])
+	}
+	msg.Address = common.HexToAddress(lines[1])
+	if lines[2] != "" {
+		return nil, errors.New("expected an empty line after the address")
+	}
+
+	rest := lines[3:]
+	if len(rest) > 0 && !strings.HasPrefix(rest[0], "URI: ") {
+		msg.Statement = rest[0]
+		if len(rest) < 2 || rest[1] != "" {
+			return nil, errors.New("expected an empty line after the statement")
+		}
+		rest = rest[2:]
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < len(rest); i++ {
+		line := rest[i]
+		if line == "Resources:" {
+			for i+1 < len(rest) && strings.HasPrefix(rest[i+1], "- ") {
+				i++
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(rest[i], "- "))
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("malformed line %q", line)
+		}
+		seen[key] = true
+		var err error
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			msg.ChainID, err = strconv.ParseUint(value, 10, 64)
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			msg.IssuedAt, err = time.Parse(time.RFC3339, value)
+		case "Expiration Time":
+			msg.ExpirationTime, err = parseTimePtr(value)
+		case "Not Before":
+			msg.NotBefore, err = parseTimePtr(value)
+		case "Request ID":
+			msg.RequestID = value
+		default:
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	for _, required := range []string{"URI", "Version", "Chain ID", "Nonce", "Issued At"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("missing %s", required)
+		}
+	}
+	if msg.Version != "1" {
+		return nil, fmt.Errorf("unsupported version %q", msg.Version)
+	}
+	if len(msg.Nonce) < 8 {
+		return nil, errors.New("nonce must be at least 8 characters")
+	}
+	return msg, nil
+}
+
+// ValidAt checks the expiration and not-before bounds
+func (m *Message) ValidAt(now time.Time) error {
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return errors.New("message has expired")
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return errors.New("message is not valid yet")
+	}
+	return nil
+}
+
+func parseTimePtr(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
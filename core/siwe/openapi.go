@@ -0,0 +1,20 @@
+package siwe
+
+import (
+	"eth-toy-client/core/openapi"
+	toytypes "eth-toy-client/core/types"
+)
+
+// Operations documents the auth endpoints Protect answers while SIWE_ENABLED is set
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: NoncePath, Summary: "Single-use nonce for a sign-in message", Tag: "auth", Response: toytypes.SiweNonceResponse{}},
+		{Method: "POST", Path: VerifyPath, Summary: "Open a session with a signed EIP-4361 message", Tag: "auth", Request: toytypes.SiweVerifyRequest{}, Response: toytypes.SiweSessionResponse{}},
+		{Method: "POST", Path: LogoutPath, Summary: "Close the session of the bearer token", Tag: "auth", Response: struct{}{}},
+	}
+}
+
+// Paths are the routes Protect serves ahead of the wrapped handler
+func Paths() []string {
+	return []string{NoncePath, VerifyPath, LogoutPath}
+}
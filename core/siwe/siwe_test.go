@@ -0,0 +1,160 @@
+package siwe
+
+import (
+	"encoding/json"
+	"eth-toy-client/accounts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	msg := &Message{
+		Domain:         "localhost:8888",
+		Address:        accounts.MustDerive(accounts.DefaultConfig())[0].Address,
+		Statement:      "Sign in to the dev server",
+		URI:            "http://localhost:8888",
+		Version:        "1",
+		ChainID:        1337,
+		Nonce:          "abcdef0123456789",
+		IssuedAt:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpirationTime: &expires,
+		Resources:      []string{"http://localhost:8888/api/accounts"},
+	}
+
+	parsed, err := ParseMessage(msg.String())
+	require.NoError(t, err)
+	require.Equal(t, msg.String(), parsed.String())
+	require.Equal(t, msg.Address, parsed.Address)
+	require.Equal(t, msg.Resources, parsed.Resources)
+
+	require.Error(t, parsed.ValidAt(expires))
+	require.NoError(t, parsed.ValidAt(expires.Add(-time.Second)))
+
+	_, err = ParseMessage(strings.Replace(msg.String(), "Version: 1", "Version: 2", 1))
+	require.Error(t, err)
+}
+
+func TestProtect(t *testing.T) {
+	testAccounts := accounts.MustDerive(accounts.DefaultConfig())
+	alice, bob := testAccounts[0], testAccounts[1]
+
+	auth := NewAuthenticator(Config{ChainID: 1337, Allowlist: []common.Address{alice.Address}})
+	protected := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, &struct{}{})
+	})
+	server := httptest.NewServer(auth.Protect(protected))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	signIn := func(acc *accounts.TestAccount) *http.Response {
+		nonce := getJSON[toytypes.SiweNonceResponse](t, server.URL+NoncePath, "")
+		msg := &Message{
+			Domain:   host,
+			Address:  acc.Address,
+			URI:      server.URL,
+			Version:  "1",
+			ChainID:  1337,
+			Nonce:    nonce.Nonce,
+			IssuedAt: time.Now(),
+		}
+		signature, err := acc.Signer.SignText([]byte(msg.String()))
+		require.NoError(t, err)
+
+		body, err := json.Marshal(toytypes.SiweVerifyRequest{Message: msg.String(), Signature: hexutil.Encode(signature)})
+		require.NoError(t, err)
+		resp, err := http.Post(server.URL+VerifyPath, "application/json", strings.NewReader(string(body)))
+		require.NoError(t, err)
+		return resp
+	}
+
+	// No session yet
+	require.Equal(t, http.StatusUnauthorized, requestStatus(t, server.URL+"/api/accounts", ""))
+	require.Equal(t, http.StatusOK, requestStatus(t, server.URL+"/ping", ""))
+
+	resp := signIn(alice)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var session httpapi.APIResponse[toytypes.SiweSessionResponse]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&session))
+	require.Equal(t, alice.Address.Hex(), session.Data.Address)
+	require.Equal(t, http.StatusOK, requestStatus(t, server.URL+"/api/accounts", session.Data.Token))
+
+	// bob proves ownership but is not on the allowlist
+	resp = signIn(bob)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Logging out revokes the token
+	req, err := http.NewRequest(http.MethodPost, server.URL+LogoutPath, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+session.Data.Token)
+	logout, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	logout.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, requestStatus(t, server.URL+"/api/accounts", session.Data.Token))
+}
+
+func TestVerifyRejectsReusedNonce(t *testing.T) {
+	alice := accounts.MustDerive(accounts.DefaultConfig())[0]
+	auth := NewAuthenticator(Config{Domain: "localhost"})
+
+	nonce, _ := auth.NewNonce()
+	msg := (&Message{
+		Domain:   "localhost",
+		Address:  alice.Address,
+		URI:      "http://localhost",
+		Version:  "1",
+		ChainID:  1337,
+		Nonce:    nonce,
+		IssuedAt: time.Now(),
+	}).String()
+	signature, err := alice.Signer.SignText([]byte(msg))
+	require.NoError(t, err)
+
+	_, err = auth.Verify(msg, signature, "ignored")
+	require.NoError(t, err)
+	_, err = auth.Verify(msg, signature, "ignored")
+	require.ErrorContains(t, err, "nonce")
+
+	// A message that names another address does not match the signature
+	forged := strings.Replace(msg, alice.Address.Hex(), accounts.MustDerive(accounts.DefaultConfig())[1].Address.Hex(), 1)
+	_, err = auth.Verify(forged, signature, "ignored")
+	require.Error(t, err)
+}
+
+func getJSON[T any](t *testing.T, url, token string) *T {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var out httpapi.APIResponse[T]
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return out.Data
+}
+
+func requestStatus(t *testing.T, url, token string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
@@ -0,0 +1,36 @@
+// Package statuswriter wraps a response writer so middleware can see the status code a handler
+// answered with, without hiding the writer's optional interfaces from the handler.
+package statuswriter
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// Recorder remembers the status code written through it; it is 200 until WriteHeader is called
+type Recorder struct {
+	http.ResponseWriter
+	Status int
+}
+
+func Wrap(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *Recorder) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to flush
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Hijack hands the connection over on WebSocket upgrades; gorilla/websocket looks for it with a
+// type assertion, which does not follow Unwrap
+func (r *Recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.Status = http.StatusSwitchingProtocols
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
@@ -0,0 +1,23 @@
+package statuswriter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := httptest.NewRecorder()
+	recorder := Wrap(rec)
+	require.Equal(t, http.StatusOK, recorder.Status, "handlers that only write answer 200")
+
+	recorder.WriteHeader(http.StatusTeapot)
+	require.Equal(t, http.StatusTeapot, recorder.Status)
+	require.Equal(t, http.StatusTeapot, rec.Code)
+	require.NoError(t, http.NewResponseController(recorder).Flush(), "flushing reaches the wrapped writer")
+
+	_, _, err := recorder.Hijack()
+	require.ErrorIs(t, err, http.ErrNotSupported, "httptest.ResponseRecorder cannot be hijacked")
+}
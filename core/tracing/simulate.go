@@ -0,0 +1,215 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"eth-toy-client/core/revert"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"strings"
+)
+
+// methodNotFoundCode is the JSON-RPC error returned when the debug namespace is not enabled
+const methodNotFoundCode = -32601
+
+// callFallbackWarning tells clients what an eth_call simulation leaves out
+const callFallbackWarning = "debug_traceCall is unavailable on the node: logs and the call trace are missing and gasUsed is an estimate"
+
+// CallArgs is the transaction object accepted by eth_call and debug_traceCall
+type CallArgs struct {
+	From  common.Address  `json:"from"`
+	To    *common.Address `json:"to,omitempty"`
+	Gas   hexutil.Uint64  `json:"gas"`
+	Value *hexutil.Big    `json:"value,omitempty"`
+	Data  hexutil.Bytes   `json:"data,omitempty"`
+}
+
+// OverrideAccount is one entry of a state override set
+type OverrideAccount struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      hexutil.Bytes               `json:"code,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// NewOverrideAccount converts the API representation of an override into the RPC one
+func NewOverrideAccount(override toytypes.StateOverride) (OverrideAccount, error) {
+	var account OverrideAccount
+	if override.Balance != "" {
+		balance, err := units.ParseAmount(override.Balance)
+		if err != nil {
+			return account, fmt.Errorf("invalid balance: %w", err)
+		}
+		account.Balance = (*hexutil.Big)(balance)
+	}
+	if override.Nonce != nil {
+		nonce := hexutil.Uint64(*override.Nonce)
+		account.Nonce = &nonce
+	}
+	if override.Code != "" {
+		code, err := hexutil.Decode(override.Code)
+		if err != nil {
+			return account, fmt.Errorf("invalid code: %w", err)
+		}
+		account.Code = code
+	}
+	if override.State != nil && override.StateDiff != nil {
+		return account, errors.New("state and stateDiff are mutually exclusive")
+	}
+	var err error
+	if account.State, err = parseSlots(override.State); err != nil {
+		return account, fmt.Errorf("invalid state: %w", err)
+	}
+	if account.StateDiff, err = parseSlots(override.StateDiff); err != nil {
+		return account, fmt.Errorf("invalid stateDiff: %w", err)
+	}
+	return account, nil
+}
+
+func parseSlots(raw map[string]string) (map[common.Hash]common.Hash, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	slots := make(map[common.Hash]common.Hash, len(raw))
+	for slot, value := range raw {
+		slotHash, err := parseWord(slot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot %q", slot)
+		}
+		valueHash, err := parseWord(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for slot %s", value, slot)
+		}
+		slots[slotHash] = valueHash
+	}
+	return slots, nil
+}
+
+// parseWord reads a storage slot or value. Both are 32-byte words written as 0x-prefixed hex
+// quantities, like eth_getStorageAt positions, so "0x0" and "0x2a" are accepted and left-padded.
+// Unlike byte data (toytypes.DecodeHex), an odd number of digits is not ambiguous here.
+func parseWord(raw string) (common.Hash, error) {
+	if !strings.HasPrefix(raw, "0x") && !strings.HasPrefix(raw, "0X") {
+		return common.Hash{}, errors.New("missing 0x prefix")
+	}
+	digits := raw[2:]
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	word, err := hex.DecodeString(digits)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(word) > common.HashLength {
+		return common.Hash{}, errors.New("longer than 32 bytes")
+	}
+	return common.BytesToHash(word), nil
+}
+
+// Simulate executes a call against the given block with optional state overrides, without broadcasting.
+// It prefers debug_traceCall (gas used, logs, call tree) and falls back to eth_call when the debug
+// namespace is unavailable, flagging the missing logs and trace in the response's Warning.
+func (t *Tracer) Simulate(ctx context.Context, args CallArgs, block rpc.BlockNumberOrHash, overrides map[common.Address]OverrideAccount) (*toytypes.SimulateTxResponse, error) {
+	var frame CallFrame
+	err := t.RPCClient.CallContext(ctx, &frame, "debug_traceCall", args, block, map[string]interface{}{
+		"tracer":         "callTracer",
+		"tracerConfig":   map[string]interface{}{"withLog": true},
+		"stateOverrides": overrides,
+	})
+	if err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+			return t.simulateWithCall(ctx, args, block, overrides)
+		}
+		return nil, fmt.Errorf("debug_traceCall failed: %w", err)
+	}
+
+	root := t.DecodeFrame(frame, 0)
+	resp := &toytypes.SimulateTxResponse{
+		Success:    frame.Error == "",
+		ReturnData: root.Output,
+		Outputs:    root.Outputs,
+		GasUsed:    uint64(frame.GasUsed),
+		Revert:     root.Revert,
+		Trace:      &root,
+	}
+	for _, callLog := range orderedLogs(frame) {
+		resp.Logs = append(resp.Logs, t.DecodeLog(callLog.Address, callLog.Topics, callLog.Data))
+	}
+	if !resp.Success && resp.Revert == nil {
+		resp.Revert = &toytypes.RevertReason{Kind: revert.KindUnknown, Message: frame.Error}
+	}
+	return resp, nil
+}
+
+func (t *Tracer) simulateWithCall(ctx context.Context, args CallArgs, block rpc.BlockNumberOrHash, overrides map[common.Address]OverrideAccount) (*toytypes.SimulateTxResponse, error) {
+	frame := CallFrame{
+		Type:  "CALL",
+		From:  args.From,
+		To:    args.To,
+		Gas:   args.Gas,
+		Value: args.Value,
+		Input: args.Data,
+	}
+	if args.To == nil {
+		frame.Type = "CREATE"
+	}
+
+	var output hexutil.Bytes
+	err := t.RPCClient.CallContext(ctx, &output, "eth_call", args, block, overrides)
+	if err != nil {
+		// Reverts, out of gas and other EVM failures are results; anything else means the node failed
+		if !revert.IsExecutionError(err) {
+			return nil, fmt.Errorf("eth_call failed: %w", err)
+		}
+		frame.Error = err.Error()
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			if hexData, ok := dataErr.ErrorData().(string); ok {
+				frame.Output, _ = hexutil.Decode(hexData)
+			}
+		}
+	} else {
+		frame.Output = output
+		// eth_call does not report gas, an estimate is the closest we get
+		var gas hexutil.Uint64
+		if err := t.RPCClient.CallContext(ctx, &gas, "eth_estimateGas", args, block, overrides); err == nil {
+			frame.GasUsed = gas
+		}
+	}
+
+	root := t.DecodeFrame(frame, 0)
+	resp := &toytypes.SimulateTxResponse{
+		Success:    frame.Error == "",
+		ReturnData: root.Output,
+		Outputs:    root.Outputs,
+		GasUsed:    uint64(frame.GasUsed),
+		Revert:     root.Revert,
+		Warning:    callFallbackWarning,
+	}
+	if !resp.Success && resp.Revert == nil {
+		resp.Revert = &toytypes.RevertReason{Kind: revert.KindUnknown, Message: frame.Error}
+	}
+	return resp, nil
+}
+
+// orderedLogs flattens the logs of a call tree in emission order, using each log's
+// position to interleave it with the frame's child calls
+func orderedLogs(frame CallFrame) []CallLog {
+	var logs []CallLog
+	next := 0
+	for i, child := range frame.Calls {
+		for next < len(frame.Logs) && int(frame.Logs[next].Position) <= i {
+			logs = append(logs, frame.Logs[next])
+			next++
+		}
+		logs = append(logs, orderedLogs(child)...)
+	}
+	return append(logs, frame.Logs[next:]...)
+}
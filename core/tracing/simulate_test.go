@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"eth-toy-client/core/revert"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// debug_traceCall result of a token call that emits one log before and one after a nested call
+const traceCallFixture = `{
+	"type": "CALL",
+	"from": "0x9999999999999999999999999999999999999999",
+	"to": "0x1111111111111111111111111111111111111111",
+	"gas": "0x2dc6c0",
+	"gasUsed": "0x5208",
+	"input": "0x",
+	"output": "0x",
+	"logs": [
+		{"address": "0x1111111111111111111111111111111111111111", "topics": ["0x0000000000000000000000000000000000000000000000000000000000000001"], "data": "0x", "position": "0x0"},
+		{"address": "0x1111111111111111111111111111111111111111", "topics": ["0x0000000000000000000000000000000000000000000000000000000000000003"], "data": "0x", "position": "0x1"}
+	],
+	"calls": [
+		{
+			"type": "CALL",
+			"from": "0x1111111111111111111111111111111111111111",
+			"to": "0x3333333333333333333333333333333333333333",
+			"gas": "0x1000",
+			"gasUsed": "0x800",
+			"input": "0x",
+			"logs": [
+				{"address": "0x3333333333333333333333333333333333333333", "topics": ["0x0000000000000000000000000000000000000000000000000000000000000002"], "data": "0x", "position": "0x0"}
+			]
+		}
+	]
+}`
+
+type debugService struct {
+	overrides map[common.Address]OverrideAccount
+}
+
+func (s *debugService) TraceCall(ctx context.Context, args CallArgs, block rpc.BlockNumberOrHash, config struct {
+	StateOverrides map[common.Address]OverrideAccount `json:"stateOverrides"`
+}) (json.RawMessage, error) {
+	s.overrides = config.StateOverrides
+	return json.RawMessage(traceCallFixture), nil
+}
+
+type revertError struct{ data string }
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorCode() int         { return 3 }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// ethService answers eth_call with callErr, or with a revert when callErr is nil
+type ethService struct {
+	callErr error
+}
+
+func (s *ethService) Call(ctx context.Context, args CallArgs, block rpc.BlockNumberOrHash, overrides map[common.Address]OverrideAccount) (hexutil.Bytes, error) {
+	if s.callErr != nil {
+		return nil, s.callErr
+	}
+	// Error("Counter is already zero")
+	return nil, &revertError{data: "0x08c379a000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000017436f756e74657220697320616c7265616479207a65726f000000000000000000"}
+}
+
+func newInProcTracer(t *testing.T, services map[string]interface{}) *Tracer {
+	server := rpc.NewServer()
+	for namespace, service := range services {
+		require.NoError(t, server.RegisterName(namespace, service))
+	}
+	client := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+	return NewTracer(client, nil)
+}
+
+func TestNewOverrideAccount(t *testing.T) {
+	nonce := uint64(7)
+	account, err := NewOverrideAccount(toytypes.StateOverride{
+		Balance:   "1000000000000000000",
+		Nonce:     &nonce,
+		Code:      "0x6080",
+		StateDiff: map[string]string{"0x0": "0x2a"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1e18), (*big.Int)(account.Balance))
+	require.Equal(t, hexutil.Uint64(7), *account.Nonce)
+	require.Equal(t, hexutil.Bytes{0x60, 0x80}, account.Code)
+	require.Equal(t, common.BigToHash(big.NewInt(42)), account.StateDiff[common.Hash{}])
+
+	_, err = NewOverrideAccount(toytypes.StateOverride{Balance: "lots"})
+	require.Error(t, err)
+
+	account, err = NewOverrideAccount(toytypes.StateOverride{
+		State: map[string]string{"0x1": "0xabc", "0x" + strings.Repeat("f", 64): "0x00ff"},
+	})
+	require.NoError(t, err, "slots and values are quantities, odd digit counts included")
+	require.Equal(t, common.HexToHash("0xabc"), account.State[common.HexToHash("0x1")])
+	require.Equal(t, common.HexToHash("0xff"), account.State[common.HexToHash("0x"+strings.Repeat("f", 64))])
+
+	for _, bad := range []map[string]string{{"1": "0x1"}, {"0x1": "0xzz"}, {"0x1": "0x" + strings.Repeat("1", 65)}} {
+		_, err = NewOverrideAccount(toytypes.StateOverride{StateDiff: bad})
+		require.Error(t, err, "%v", bad)
+	}
+
+	_, err = NewOverrideAccount(toytypes.StateOverride{
+		State:     map[string]string{"0x0": "0x1"},
+		StateDiff: map[string]string{"0x0": "0x1"},
+	})
+	require.Error(t, err)
+}
+
+func TestSimulateWithTraceCall(t *testing.T) {
+	debug := &debugService{}
+	tracer := newInProcTracer(t, map[string]interface{}{"debug": debug})
+
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	overrides := map[common.Address]OverrideAccount{
+		bob: {Balance: (*hexutil.Big)(big.NewInt(5))},
+	}
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	result, err := tracer.Simulate(context.Background(), CallArgs{To: &to, Gas: 3_000_000},
+		rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), overrides)
+	require.NoError(t, err)
+
+	require.True(t, result.Success)
+	require.Equal(t, uint64(0x5208), result.GasUsed)
+	require.NotNil(t, result.Trace)
+	require.Equal(t, big.NewInt(5), (*big.Int)(debug.overrides[bob].Balance), "overrides are forwarded to the node")
+
+	require.Len(t, result.Logs, 3)
+	for i, log := range result.Logs {
+		require.Equal(t, common.BigToHash(big.NewInt(int64(i+1))).Hex(), log.Topics[0], "logs are in emission order")
+	}
+}
+
+func TestSimulateFallsBackToEthCall(t *testing.T) {
+	tracer := newInProcTracer(t, map[string]interface{}{"eth": &ethService{}})
+
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	result, err := tracer.Simulate(context.Background(), CallArgs{To: &to, Gas: 3_000_000},
+		rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	require.NoError(t, err)
+
+	require.False(t, result.Success)
+	require.Nil(t, result.Trace, "no call tree without debug_traceCall")
+	require.NotNil(t, result.Revert)
+	require.Equal(t, revert.KindError, result.Revert.Kind)
+	require.Equal(t, "Counter is already zero", result.Revert.Message)
+	require.NotEmpty(t, result.Warning, "the missing logs are flagged")
+}
+
+func TestSimulateFallbackReportsExecutionErrors(t *testing.T) {
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	block := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	tracer := newInProcTracer(t, map[string]interface{}{"eth": &ethService{callErr: errors.New("out of gas")}})
+	result, err := tracer.Simulate(context.Background(), CallArgs{To: &to, Gas: 21_000}, block, nil)
+	require.NoError(t, err)
+	require.False(t, result.Success)
+	require.Equal(t, revert.KindUnknown, result.Revert.Kind)
+	require.Equal(t, "out of gas", result.Revert.Message)
+
+	tracer = newInProcTracer(t, map[string]interface{}{"eth": &ethService{callErr: errors.New("header not found")}})
+	_, err = tracer.Simulate(context.Background(), CallArgs{To: &to, Gas: 21_000}, block, nil)
+	require.ErrorContains(t, err, "header not found", "node failures are not simulation results")
+}
@@ -0,0 +1,191 @@
+package tracing
+
+import (
+	"context"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// PrestateAccount mirrors one account of geth's prestateTracer output
+type PrestateAccount struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Nonce   *uint64                     `json:"nonce,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// PrestateDiff is the prestateTracer output in diff mode. Post only carries the fields that changed.
+type PrestateDiff struct {
+	Pre  map[common.Address]*PrestateAccount `json:"pre"`
+	Post map[common.Address]*PrestateAccount `json:"post"`
+}
+
+// RawStateDiff runs debug_traceTransaction with the prestateTracer in diff mode
+func (t *Tracer) RawStateDiff(ctx context.Context, txHash common.Hash) (*PrestateDiff, error) {
+	var diff PrestateDiff
+	err := t.RPCClient.CallContext(ctx, &diff, "debug_traceTransaction", txHash, map[string]interface{}{
+		"tracer":       "prestateTracer",
+		"tracerConfig": map[string]interface{}{"diffMode": true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debug_traceTransaction failed: %w", err)
+	}
+	return &diff, nil
+}
+
+// StateDiff returns the balance, nonce, code and storage changes of a mined transaction.
+// mappingKeys are extra addresses tried as mapping keys when labelling storage slots.
+func (t *Tracer) StateDiff(ctx context.Context, txHash common.Hash, mappingKeys ...common.Address) (*toytypes.StateDiffResponse, error) {
+	diff, err := t.RawStateDiff(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return &toytypes.StateDiffResponse{
+		TxHash:   txHash.Hex(),
+		Accounts: t.DecodeStateDiff(diff, mappingKeys...),
+	}, nil
+}
+
+// DecodeStateDiff turns a raw diff into per-account changes, sorted by address
+func (t *Tracer) DecodeStateDiff(diff *PrestateDiff, mappingKeys ...common.Address) []toytypes.AccountStateDiff {
+	touched := make(map[common.Address]struct{})
+	for addr := range diff.Pre {
+		touched[addr] = struct{}{}
+	}
+	for addr := range diff.Post {
+		touched[addr] = struct{}{}
+	}
+
+	// Every touched account is a candidate mapping key (balances[from], balances[to], ...)
+	keys := append([]common.Address{}, mappingKeys...)
+	for addr := range touched {
+		keys = append(keys, addr)
+	}
+	if t.Registry != nil {
+		for _, entry := range t.Registry.All() {
+			keys = append(keys, common.HexToAddress(entry.Address.Address))
+		}
+	}
+
+	addresses := make([]common.Address, 0, len(touched))
+	for addr := range touched {
+		addresses = append(addresses, addr)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Cmp(addresses[j]) < 0
+	})
+
+	accounts := make([]toytypes.AccountStateDiff, 0, len(addresses))
+	for _, addr := range addresses {
+		pre, post := diff.Pre[addr], diff.Post[addr]
+		if pre == nil {
+			pre = &PrestateAccount{}
+		}
+		// Present before but gone afterwards means the account was deleted
+		deleted := post == nil
+		if post == nil {
+			post = &PrestateAccount{}
+		}
+
+		account := toytypes.AccountStateDiff{Address: addr.Hex()}
+		if t.Registry != nil {
+			if info, ok := t.Registry.Lookup(addr); ok {
+				account.Alias = info.Alias
+			}
+		}
+
+		if post.Balance != nil || deleted {
+			account.Balance = &toytypes.ValueChange{
+				Before: bigString(pre.Balance),
+				After:  bigString(post.Balance),
+			}
+			account.BalanceFormatted = &toytypes.ValueChange{
+				Before: units.FormatWei(account.Balance.Before, units.Ether),
+				After:  units.FormatWei(account.Balance.After, units.Ether),
+			}
+		}
+		if post.Nonce != nil || deleted {
+			account.Nonce = &toytypes.ValueChange{
+				Before: uintString(pre.Nonce),
+				After:  uintString(post.Nonce),
+			}
+		}
+		if len(post.Code) > 0 || (deleted && len(pre.Code) > 0) {
+			account.Code = &toytypes.ValueChange{
+				Before: hexutil.Encode(pre.Code),
+				After:  hexutil.Encode(post.Code),
+			}
+		}
+		account.Storage = t.storageChanges(addr, pre.Storage, post.Storage, keys)
+
+		if account.Balance == nil && account.Nonce == nil && account.Code == nil && len(account.Storage) == 0 {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+// storageChanges pairs pre and post slots; a slot missing from post was cleared to zero
+func (t *Tracer) storageChanges(addr common.Address, pre, post map[common.Hash]common.Hash, keys []common.Address) []toytypes.StorageChange {
+	slots := make(map[common.Hash]struct{})
+	for slot := range pre {
+		slots[slot] = struct{}{}
+	}
+	for slot := range post {
+		slots[slot] = struct{}{}
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+
+	var layoutLabel func(common.Hash) string
+	if t.Registry != nil {
+		if info, ok := t.Registry.Lookup(addr); ok && info.ParsedStorageLayout != nil {
+			layoutLabel = func(slot common.Hash) string {
+				return info.ParsedStorageLayout.LabelSlot(slot, keys)
+			}
+		}
+	}
+
+	changes := make([]toytypes.StorageChange, 0, len(slots))
+	for slot := range slots {
+		before, after := pre[slot], post[slot]
+		if before == after {
+			continue
+		}
+		change := toytypes.StorageChange{
+			Slot:   slot.Hex(),
+			Before: before.Hex(),
+			After:  after.Hex(),
+		}
+		if layoutLabel != nil {
+			change.Label = layoutLabel(slot)
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Slot < changes[j].Slot
+	})
+	return changes
+}
+
+func bigString(value *hexutil.Big) string {
+	if value == nil {
+		return "0"
+	}
+	return (*big.Int)(value).String()
+}
+
+func uintString(value *uint64) string {
+	if value == nil {
+		return "0"
+	}
+	return strconv.FormatUint(*value, 10)
+}
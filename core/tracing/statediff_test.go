@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"encoding/json"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+const counterLayout = `{
+	"storage": [{"label": "count", "offset": 0, "slot": "0", "type": "t_uint256"}],
+	"types": {"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"}}
+}`
+
+// CounterV2.increment() sent by 0x9999…: sender pays gas and bumps nonce, count goes 0 → 1
+const prestateDiffFixture = `{
+	"pre": {
+		"0x9999999999999999999999999999999999999999": {"balance": "0xde0b6b3a7640000", "nonce": 3},
+		"0x1111111111111111111111111111111111111111": {
+			"balance": "0x0",
+			"nonce": 1,
+			"code": "0x6080",
+			"storage": {}
+		}
+	},
+	"post": {
+		"0x9999999999999999999999999999999999999999": {"balance": "0xde0b6b3a763f000", "nonce": 4},
+		"0x1111111111111111111111111111111111111111": {
+			"storage": {"0x0000000000000000000000000000000000000000000000000000000000000000": "0x0000000000000000000000000000000000000000000000000000000000000001"}
+		}
+	}
+}`
+
+func newCounterTracer(t *testing.T) *Tracer {
+	registry := contract.NewRegistry()
+	meta := contract.DeployedContractMetaJSON{
+		Alias:         "CounterV2",
+		Address:       "0x1111111111111111111111111111111111111111",
+		StorageLayout: counterLayout,
+	}
+	info := meta.ToDeployedContractInfo(false)
+	layout, err := contract.ParseStorageLayout(meta.StorageLayout)
+	require.NoError(t, err)
+	info.ParsedStorageLayout = layout
+	require.NoError(t, registry.Add(*info))
+	return NewTracer(nil, registry)
+}
+
+func TestDecodeStateDiff(t *testing.T) {
+	var diff PrestateDiff
+	require.NoError(t, json.Unmarshal([]byte(prestateDiffFixture), &diff))
+
+	accounts := newCounterTracer(t).DecodeStateDiff(&diff)
+	require.Len(t, accounts, 2)
+
+	counter := accounts[0]
+	require.Equal(t, "0x1111111111111111111111111111111111111111", counter.Address)
+	require.Equal(t, "CounterV2", counter.Alias)
+	require.Nil(t, counter.Balance, "unchanged balance is omitted")
+	require.Nil(t, counter.Nonce)
+	require.Nil(t, counter.Code)
+	require.Len(t, counter.Storage, 1)
+	require.Equal(t, "count", counter.Storage[0].Label)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", counter.Storage[0].Before)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", counter.Storage[0].After)
+
+	sender := accounts[1]
+	require.Equal(t, "0x9999999999999999999999999999999999999999", sender.Address)
+	require.Equal(t, "1000000000000000000", sender.Balance.Before)
+	require.Equal(t, "999999999999995904", sender.Balance.After)
+	require.Equal(t, "3", sender.Nonce.Before)
+	require.Equal(t, "4", sender.Nonce.After)
+	require.Empty(t, sender.Storage)
+}
+
+func TestDecodeStateDiffClearedSlot(t *testing.T) {
+	raw := `{
+		"pre": {"0x1111111111111111111111111111111111111111": {"balance": "0x0", "storage": {"0x0000000000000000000000000000000000000000000000000000000000000000": "0x0000000000000000000000000000000000000000000000000000000000000005"}}},
+		"post": {"0x1111111111111111111111111111111111111111": {}}
+	}`
+	var diff PrestateDiff
+	require.NoError(t, json.Unmarshal([]byte(raw), &diff))
+
+	accounts := newCounterTracer(t).DecodeStateDiff(&diff)
+	require.Len(t, accounts, 1)
+	require.Len(t, accounts[0].Storage, 1)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", accounts[0].Storage[0].After)
+}
@@ -0,0 +1,250 @@
+package tracing
+
+import (
+	"context"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/revert"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+)
+
+// CallFrame mirrors one frame of geth's callTracer output
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Logs         []CallLog       `json:"logs,omitempty"`
+	Calls        []CallFrame     `json:"calls,omitempty"`
+}
+
+// CallLog is a log emitted by a frame, reported by the callTracer when withLog is set.
+// Position is the number of child calls made before the log was emitted.
+type CallLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"`
+}
+
+// Tracer runs geth's built-in tracers and decodes the result with the ABIs in the registry
+type Tracer struct {
+	RPCClient *rpc.Client
+	Registry  *contract.Registry
+}
+
+func NewTracer(rpcClient *rpc.Client, registry *contract.Registry) *Tracer {
+	return &Tracer{
+		RPCClient: rpcClient,
+		Registry:  registry,
+	}
+}
+
+// RawCallTrace runs debug_traceTransaction with the callTracer
+func (t *Tracer) RawCallTrace(ctx context.Context, txHash common.Hash) (*CallFrame, error) {
+	var frame CallFrame
+	err := t.RPCClient.CallContext(ctx, &frame, "debug_traceTransaction", txHash, map[string]interface{}{
+		"tracer": "callTracer",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debug_traceTransaction failed: %w", err)
+	}
+	return &frame, nil
+}
+
+// TxCallFrame is one entry of debug_traceBlockByNumber
+type TxCallFrame struct {
+	TxHash common.Hash `json:"txHash"`
+	Result CallFrame   `json:"result"`
+}
+
+// RawBlockCallTraces runs the callTracer over every transaction of a block
+func (t *Tracer) RawBlockCallTraces(ctx context.Context, blockNumber uint64) ([]TxCallFrame, error) {
+	var frames []TxCallFrame
+	err := t.RPCClient.CallContext(ctx, &frames, "debug_traceBlockByNumber", hexutil.Uint64(blockNumber), map[string]interface{}{
+		"tracer": "callTracer",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("debug_traceBlockByNumber failed: %w", err)
+	}
+	return frames, nil
+}
+
+// TraceTransaction builds the decoded call tree of a mined transaction
+func (t *Tracer) TraceTransaction(ctx context.Context, txHash common.Hash) (*toytypes.TxTraceResponse, error) {
+	frame, err := t.RawCallTrace(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	root := t.DecodeFrame(*frame, 0)
+	return &toytypes.TxTraceResponse{
+		TxHash:               txHash.Hex(),
+		InternalTransactions: len(Flatten(root)),
+		Root:                 root,
+	}, nil
+}
+
+// DecodeFrame converts a raw frame and its children, resolving method names, arguments
+// and revert reasons for callees that have a registered ABI
+func (t *Tracer) DecodeFrame(frame CallFrame, depth int) toytypes.CallTraceFrame {
+	decoded := toytypes.CallTraceFrame{
+		Type:    frame.Type,
+		Depth:   depth,
+		From:    frame.From.Hex(),
+		Gas:     uint64(frame.Gas),
+		GasUsed: uint64(frame.GasUsed),
+		Error:   frame.Error,
+	}
+	if len(frame.Input) > 0 {
+		decoded.Input = hexutil.Encode(frame.Input)
+	}
+	if len(frame.Output) > 0 {
+		decoded.Output = hexutil.Encode(frame.Output)
+	}
+	if frame.Value != nil {
+		decoded.Value = (*big.Int)(frame.Value).String()
+		decoded.ValueFormatted = units.Format((*big.Int)(frame.Value), units.Ether)
+	}
+
+	var abis []*abi.ABI
+	if frame.To != nil {
+		decoded.To = frame.To.Hex()
+		if t.Registry != nil {
+			abis = t.Registry.ABIsFor(*frame.To)
+			if info, ok := t.Registry.Lookup(*frame.To); ok {
+				decoded.Alias = info.Alias
+				decodeMethod(&decoded, info.ParsedABI, frame)
+			}
+		}
+	}
+
+	if frame.Error != "" {
+		if len(frame.Output) > 0 {
+			decoded.Revert = revert.Decode(frame.Output, abis...)
+		} else if frame.RevertReason != "" {
+			decoded.Revert = &toytypes.RevertReason{Kind: revert.KindError, Message: frame.RevertReason}
+		}
+	}
+
+	for _, callLog := range frame.Logs {
+		decoded.Logs = append(decoded.Logs, t.DecodeLog(callLog.Address, callLog.Topics, callLog.Data))
+	}
+	for _, child := range frame.Calls {
+		decoded.Calls = append(decoded.Calls, t.DecodeFrame(child, depth+1))
+	}
+	return decoded
+}
+
+// DecodeLog resolves the event name and arguments of a log using the emitter's registered ABI
+func (t *Tracer) DecodeLog(address common.Address, topics []common.Hash, data []byte) toytypes.DecodedLog {
+	decoded := toytypes.DecodedLog{
+		Address: address.Hex(),
+		Topics:  make([]string, 0, len(topics)),
+	}
+	for _, topic := range topics {
+		decoded.Topics = append(decoded.Topics, topic.Hex())
+	}
+	if len(data) > 0 {
+		decoded.Data = hexutil.Encode(data)
+	}
+	if t.Registry == nil || len(topics) == 0 {
+		return decoded
+	}
+
+	info, ok := t.Registry.Lookup(address)
+	if !ok {
+		return decoded
+	}
+	decoded.Alias = info.Alias
+	if info.ParsedABI == nil {
+		return decoded
+	}
+	event, err := info.ParsedABI.EventByID(topics[0])
+	if err != nil {
+		return decoded
+	}
+	decoded.Event = event.Name
+
+	args := make(map[string]interface{})
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(args, data); err != nil {
+		return decoded
+	}
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, topics[1:]); err != nil {
+		return decoded
+	}
+	decoded.Args = args
+	return decoded
+}
+
+func decodeMethod(decoded *toytypes.CallTraceFrame, parsedABI *abi.ABI, frame CallFrame) {
+	if parsedABI == nil || len(frame.Input) < 4 || isCreate(frame.Type) {
+		return
+	}
+	method, err := parsedABI.MethodById(frame.Input[:4])
+	if err != nil {
+		return
+	}
+	decoded.Method = method.Name
+
+	if values, err := method.Inputs.Unpack(frame.Input[4:]); err == nil {
+		decoded.Args = argumentsToMap(method.Inputs, values)
+	}
+	if frame.Error == "" && len(frame.Output) > 0 {
+		if values, err := method.Outputs.Unpack(frame.Output); err == nil {
+			decoded.Outputs = argumentsToMap(method.Outputs, values)
+		}
+	}
+}
+
+// argumentsToMap names unpacked values, falling back to argN for unnamed ones
+func argumentsToMap(arguments abi.Arguments, values []interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		name := ""
+		if i < len(arguments) {
+			name = arguments[i].Name
+		}
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		out[name] = value
+	}
+	return out
+}
+
+func isCreate(frameType string) bool {
+	return frameType == "CREATE" || frameType == "CREATE2"
+}
+
+// Flatten returns every nested frame (the internal transactions) in execution order
+func Flatten(root toytypes.CallTraceFrame) []toytypes.CallTraceFrame {
+	var frames []toytypes.CallTraceFrame
+	var walk func(frame toytypes.CallTraceFrame)
+	walk = func(frame toytypes.CallTraceFrame) {
+		for _, child := range frame.Calls {
+			frames = append(frames, child)
+			walk(child)
+		}
+	}
+	walk(root)
+	return frames
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/revert"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenABI = `[{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}]`
+
+const tokenAddress = "0x1111111111111111111111111111111111111111"
+
+// transfer(0x2222…, 100) on the token, which calls out to an unknown contract that reverts
+const callTracerFixture = `{
+	"type": "CALL",
+	"from": "0x9999999999999999999999999999999999999999",
+	"to": "0x1111111111111111111111111111111111111111",
+	"value": "0x0",
+	"gas": "0x2dc6c0",
+	"gasUsed": "0xbeef",
+	"input": "0xa9059cbb00000000000000000000000022222222222222222222222222222222222222220000000000000000000000000000000000000000000000000000000000000064",
+	"output": "0x0000000000000000000000000000000000000000000000000000000000000001",
+	"calls": [
+		{
+			"type": "CALL",
+			"from": "0x1111111111111111111111111111111111111111",
+			"to": "0x3333333333333333333333333333333333333333",
+			"value": "0xde0b6b3a7640000",
+			"gas": "0x1000",
+			"gasUsed": "0x800",
+			"input": "0x",
+			"output": "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000b6e6f7420616c6c6f7765640000000000000000000000000000000000000000",
+			"error": "execution reverted",
+			"calls": [
+				{
+					"type": "STATICCALL",
+					"from": "0x3333333333333333333333333333333333333333",
+					"to": "0x4444444444444444444444444444444444444444",
+					"gas": "0x100",
+					"gasUsed": "0x10",
+					"input": "0x"
+				}
+			]
+		}
+	]
+}`
+
+func newTestTracer(t *testing.T) *Tracer {
+	registry := contract.NewRegistry()
+	meta := contract.DeployedContractMetaJSON{
+		Alias:   "MockUSDC",
+		Address: tokenAddress,
+		ABI:     tokenABI,
+	}
+	info := meta.ToDeployedContractInfo(false)
+	parsed, err := contract.ParseABI(meta.ABI)
+	require.NoError(t, err)
+	info.ParsedABI = parsed
+	require.NoError(t, registry.Add(*info))
+	return NewTracer(nil, registry)
+}
+
+func TestDecodeFrameResolvesMethodAndArgs(t *testing.T) {
+	var frame CallFrame
+	require.NoError(t, json.Unmarshal([]byte(callTracerFixture), &frame))
+
+	root := newTestTracer(t).DecodeFrame(frame, 0)
+
+	require.Equal(t, "CALL", root.Type)
+	require.Equal(t, "MockUSDC", root.Alias)
+	require.Equal(t, "transfer", root.Method)
+	require.Equal(t, common.HexToAddress("0x2222222222222222222222222222222222222222"), root.Args["to"])
+	require.Equal(t, big.NewInt(100), root.Args["amount"])
+	require.Equal(t, true, root.Outputs["arg0"])
+	require.Equal(t, uint64(0xbeef), root.GasUsed)
+}
+
+func TestDecodeFrameChildRevertAndValue(t *testing.T) {
+	var frame CallFrame
+	require.NoError(t, json.Unmarshal([]byte(callTracerFixture), &frame))
+
+	root := newTestTracer(t).DecodeFrame(frame, 0)
+	require.Len(t, root.Calls, 1)
+
+	child := root.Calls[0]
+	require.Equal(t, 1, child.Depth)
+	require.Equal(t, "1000000000000000000", child.Value)
+	require.Empty(t, child.Method)
+	require.Equal(t, "execution reverted", child.Error)
+	require.NotNil(t, child.Revert)
+	require.Equal(t, revert.KindError, child.Revert.Kind)
+	require.Equal(t, "not allowed", child.Revert.Message)
+}
+
+func TestFlattenReturnsNestedFramesInOrder(t *testing.T) {
+	var frame CallFrame
+	require.NoError(t, json.Unmarshal([]byte(callTracerFixture), &frame))
+
+	frames := Flatten(newTestTracer(t).DecodeFrame(frame, 0))
+	require.Len(t, frames, 2)
+	require.Equal(t, "CALL", frames[0].Type)
+	require.Equal(t, "STATICCALL", frames[1].Type)
+	require.Equal(t, 2, frames[1].Depth)
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type ContractAddress struct {
 	Address string `json:"address"`
 }
@@ -10,17 +15,18 @@ type SignTxAPIResponse struct {
 }
 
 type SignTxRequest struct {
-	From    string  `json:"from"`              // alias, e.g. "alice"
-	To      string  `json:"to"`                // "" or omitted for contract deployment
-	Value   string  `json:"value"`             // amount in wei (as string)
-	Data    string  `json:"data,omitempty"`    // hex-encoded bytecode or calldata
-	Nonce   *uint64 `json:"nonce,omitempty"`   // optional
-	ChainID *int64  `json:"chainId,omitempty"` // optional
-	Type    string  `json:"type,omitempty"`    // e.g. "deploy", "call", "raw"
+	From    string  `json:"from" validate:"required,alias"`     // alias, e.g. "alice"
+	To      string  `json:"to" validate:"account"`              // "" or omitted for contract deployment
+	Value   string  `json:"value" validate:"amount"`            // wei, or a decimal with a unit such as "0.5 ether"
+	Data    string  `json:"data,omitempty" validate:"hex"`      // hex-encoded bytecode or calldata
+	Nonce   *uint64 `json:"nonce,omitempty" validate:"nonce"`   // optional
+	ChainID *int64  `json:"chainId,omitempty" validate:"min=1"` // optional
+	Type    string  `json:"type,omitempty"`                     // e.g. "deploy", "call", "raw"
+	Wait    bool    `json:"wait,omitempty"`                     // wait for the receipt before responding
 }
 
 type PendingNonceRequest struct {
-	Alias string `json:"alias"`
+	Alias string `json:"alias" validate:"required,alias"`
 }
 
 type PendingNonceResponse struct {
@@ -29,13 +35,14 @@ type PendingNonceResponse struct {
 }
 
 type DeployContractRequest struct {
-	From  string  `json:"from"` // alias, e.g. "alice"
-	Nonce *uint64 `json:"nonce"`
-	Data  string  `json:"data"` // hex-encoded bytecode
+	From  string  `json:"from" validate:"required,alias"` // alias, e.g. "alice"
+	Nonce *uint64 `json:"nonce" validate:"nonce"`
+	Data  string  `json:"data" validate:"required,hex"` // hex-encoded bytecode
 }
 
 type SendTxAPIResponse struct {
-	TxHash string `json:"txHash"`
+	TxHash  string             `json:"txHash"`
+	Receipt *TxReceiptResponse `json:"receipt,omitempty"` // only set when the request asked to wait
 }
 
 type ContractDeploymentResponse struct {
@@ -47,3 +54,408 @@ type AliasRegisterResponse struct {
 	Status string `json:"status"`
 	Alias  string `json:"alias"`
 }
+
+type RevertReason struct {
+	Kind      string                 `json:"kind"`                // "Error", "Panic", "CustomError" or "Unknown"
+	Message   string                 `json:"message"`             // human-readable reason
+	Selector  string                 `json:"selector,omitempty"`  // 4-byte selector of the revert data
+	PanicCode *uint64                `json:"panicCode,omitempty"` // set for Panic(uint256)
+	ErrorName string                 `json:"errorName,omitempty"` // set for ABI custom errors
+	Args      map[string]interface{} `json:"args,omitempty"`      // decoded custom error arguments
+	Data      string                 `json:"data,omitempty"`      // raw revert data (0x-prefixed)
+}
+
+type TxReceiptResponse struct {
+	TxHash          string        `json:"txHash"`
+	Status          uint64        `json:"status"` // 1 = success, 0 = failure
+	BlockNumber     uint64        `json:"blockNumber"`
+	GasUsed         uint64        `json:"gasUsed"`
+	ContractAddress string        `json:"contractAddress,omitempty"`
+	Revert          *RevertReason `json:"revert,omitempty"` // decoded when status is 0
+}
+
+type CallTraceFrame struct {
+	Type           string                 `json:"type"` // CALL, STATICCALL, DELEGATECALL, CREATE, ...
+	Depth          int                    `json:"depth"`
+	From           string                 `json:"from"`
+	To             string                 `json:"to,omitempty"`
+	Value          string                 `json:"value,omitempty"`          // wei (decimal string)
+	ValueFormatted string                 `json:"valueFormatted,omitempty"` // e.g. "0.5 ether"
+	Gas            uint64                 `json:"gas"`
+	GasUsed        uint64                 `json:"gasUsed"`
+	Input          string                 `json:"input,omitempty"`
+	Output         string                 `json:"output,omitempty"`
+	Alias          string                 `json:"alias,omitempty"`   // registered alias of the callee
+	Method         string                 `json:"method,omitempty"`  // decoded via the callee's registered ABI
+	Args           map[string]interface{} `json:"args,omitempty"`    // decoded method arguments
+	Outputs        map[string]interface{} `json:"outputs,omitempty"` // decoded return values
+	Error          string                 `json:"error,omitempty"`
+	Revert         *RevertReason          `json:"revert,omitempty"`
+	Logs           []DecodedLog           `json:"logs,omitempty"` // only when traced with logs
+	Calls          []CallTraceFrame       `json:"calls,omitempty"`
+}
+
+type TxTraceResponse struct {
+	TxHash               string         `json:"txHash"`
+	InternalTransactions int            `json:"internalTransactions"` // number of nested frames
+	Root                 CallTraceFrame `json:"root"`
+}
+
+type ValueChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type StorageChange struct {
+	Slot   string `json:"slot"`
+	Label  string `json:"label,omitempty"` // state variable name, when a storage layout is registered
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type AccountStateDiff struct {
+	Address          string          `json:"address"`
+	Alias            string          `json:"alias,omitempty"`
+	Balance          *ValueChange    `json:"balance,omitempty"`          // wei (decimal strings)
+	BalanceFormatted *ValueChange    `json:"balanceFormatted,omitempty"` // in ether
+	Nonce            *ValueChange    `json:"nonce,omitempty"`
+	Code             *ValueChange    `json:"code,omitempty"` // 0x-prefixed bytecode
+	Storage          []StorageChange `json:"storage,omitempty"`
+}
+
+type StateDiffResponse struct {
+	TxHash   string             `json:"txHash"`
+	Accounts []AccountStateDiff `json:"accounts"`
+}
+
+type StateOverride struct {
+	Balance   string            `json:"balance,omitempty" validate:"amount"` // wei, or a decimal with a unit
+	Nonce     *uint64           `json:"nonce,omitempty"`
+	Code      string            `json:"code,omitempty"`      // hex bytecode
+	State     map[string]string `json:"state,omitempty"`     // replaces the whole storage (slot → value, hex quantities such as "0x2a")
+	StateDiff map[string]string `json:"stateDiff,omitempty"` // patches individual slots (slot → value, hex quantities such as "0x2a")
+}
+
+type SimulateTxRequest struct {
+	From           string                   `json:"from" validate:"required,alias"`     // alias, e.g. "alice"
+	To             string                   `json:"to,omitempty" validate:"account"`    // account alias, contract alias or address; "" deploys
+	Value          string                   `json:"value,omitempty" validate:"amount"`  // wei, or a decimal with a unit such as "0.5 ether"
+	Data           string                   `json:"data,omitempty" validate:"hex"`      // hex-encoded bytecode or calldata
+	Gas            *uint64                  `json:"gas,omitempty" validate:"min=21000"` // optional, defaults to 3M
+	BlockNumber    *uint64                  `json:"blockNumber,omitempty"`              // optional, defaults to latest
+	StateOverrides map[string]StateOverride `json:"stateOverrides,omitempty"`           // keyed by alias or address
+}
+
+type DecodedLog struct {
+	Address string                 `json:"address"`
+	Alias   string                 `json:"alias,omitempty"`
+	Event   string                 `json:"event,omitempty"` // decoded via the emitter's registered ABI
+	Args    map[string]interface{} `json:"args,omitempty"`
+	Topics  []string               `json:"topics"`
+	Data    string                 `json:"data,omitempty"`
+}
+
+type SimulateTxResponse struct {
+	Success    bool                   `json:"success"`
+	ReturnData string                 `json:"returnData,omitempty"`
+	Outputs    map[string]interface{} `json:"outputs,omitempty"` // decoded return values
+	GasUsed    uint64                 `json:"gasUsed"`
+	Logs       []DecodedLog           `json:"logs,omitempty"`
+	Revert     *RevertReason          `json:"revert,omitempty"`
+	Trace      *CallTraceFrame        `json:"trace,omitempty"`   // only when debug_traceCall is available
+	Warning    string                 `json:"warning,omitempty"` // set when the node could only run eth_call
+}
+
+type SendBatchRequest struct {
+	Txs         []SignTxRequest `json:"txs" validate:"required"` // submitted in this order; per-item nonce and wait are ignored
+	Wait        bool            `json:"wait,omitempty"`          // wait for every receipt before responding
+	StopOnError bool            `json:"stopOnError,omitempty"`   // skip the remaining items after the first failure
+}
+
+const (
+	BatchItemSent    = "sent"
+	BatchItemFailed  = "failed"
+	BatchItemSkipped = "skipped"
+)
+
+type BatchTxResult struct {
+	Index   int                `json:"index"`
+	From    string             `json:"from"`
+	Status  string             `json:"status"` // sent, failed or skipped
+	TxHash  string             `json:"txHash,omitempty"`
+	Nonce   *uint64            `json:"nonce,omitempty"`
+	Error   string             `json:"error,omitempty"`
+	Receipt *TxReceiptResponse `json:"receipt,omitempty"` // only set when the batch asked to wait
+}
+
+type SendBatchResponse struct {
+	Sent    int             `json:"sent"`
+	Failed  int             `json:"failed"`
+	Skipped int             `json:"skipped"`
+	Results []BatchTxResult `json:"results"`
+}
+
+const (
+	TxKindSend    = "send"
+	TxKindSpeedup = "speedup"
+	TxKindCancel  = "cancel"
+)
+
+// TxRecord is one entry of DevServer's tx history
+type TxRecord struct {
+	TxHash             string    `json:"txHash"`
+	Kind               string    `json:"kind"` // send, speedup or cancel
+	From               string    `json:"from"` // alias
+	To                 string    `json:"to,omitempty"`
+	Nonce              uint64    `json:"nonce"`
+	GasTipCap          string    `json:"gasTipCap"`                    // wei
+	GasFeeCap          string    `json:"gasFeeCap"`                    // wei
+	GasTipCapFormatted string    `json:"gasTipCapFormatted,omitempty"` // in gwei
+	GasFeeCapFormatted string    `json:"gasFeeCapFormatted,omitempty"` // in gwei
+	Replaces           string    `json:"replaces,omitempty"`           // hash of the tx this one replaced
+	ReplacedBy         string    `json:"replacedBy,omitempty"`         // hash of the tx that replaced this one
+	SentAt             time.Time `json:"sentAt"`
+}
+
+type ReplaceTxRequest struct {
+	GasTipCap string `json:"gasTipCap,omitempty" validate:"amount"` // wei or e.g. "2 gwei", defaults to the original bumped by the replacement minimum
+	GasFeeCap string `json:"gasFeeCap,omitempty" validate:"amount"` // wei, same default
+	Wait      bool   `json:"wait,omitempty"`
+}
+
+type ReplaceTxResponse struct {
+	TxHash   string             `json:"txHash"`
+	Replaces string             `json:"replaces"`
+	Kind     string             `json:"kind"`
+	Nonce    uint64             `json:"nonce"`
+	Receipt  *TxReceiptResponse `json:"receipt,omitempty"`
+}
+
+const (
+	AccountSourceRandom     = "random"
+	AccountSourceDerived    = "derived"
+	AccountSourcePrivateKey = "privateKey"
+	AccountSourceKeystore   = "keystore"
+)
+
+type CreateAccountRequest struct {
+	Name       string `json:"name" validate:"required,alias"`
+	Source     string `json:"source,omitempty" validate:"oneof=random|derived|privateKey|keystore"` // random (default), derived, privateKey or keystore
+	Index      *int   `json:"index,omitempty" validate:"min=0"`                                     // derived only, defaults to the next unused index
+	PrivateKey string `json:"privateKey,omitempty" validate:"hex"`                                  // privateKey only, hex
+	Keystore   string `json:"keystore,omitempty"`                                                   // keystore only, encrypted keystore JSON
+	Password   string `json:"password,omitempty"`                                                   // keystore only
+	Fund       string `json:"fund,omitempty" validate:"amount"`                                     // optional amount to send from the dev account right away, e.g. "1 ether"
+}
+
+type AccountInfo struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Path    string `json:"path,omitempty"` // derivation path, empty for random or imported keys
+}
+
+type CreateAccountResponse struct {
+	Account    AccountInfo `json:"account"`
+	FundTxHash string      `json:"fundTxHash,omitempty"`
+}
+
+type FundAccountRequest struct {
+	Value string `json:"value,omitempty" validate:"amount"` // wei or e.g. "0.5 ether", defaults to 1 ETH
+	Wait  bool   `json:"wait,omitempty"`
+}
+
+type FundAccountResponse struct {
+	TxHash           string `json:"txHash"`
+	Balance          string `json:"balance,omitempty"`          // wei, only known when the request waited
+	BalanceFormatted string `json:"balanceFormatted,omitempty"` // in ether
+}
+
+const (
+	MessageEncodingUTF8 = "utf8"
+	MessageEncodingHex  = "hex"
+)
+
+type SignMessageRequest struct {
+	From     string `json:"from" validate:"required,alias"` // alias
+	Message  string `json:"message"`
+	Encoding string `json:"encoding,omitempty" validate:"oneof=utf8|hex"` // utf8 (default) or hex
+}
+
+type SignTypedDataRequest struct {
+	From      string          `json:"from" validate:"required,alias"` // alias
+	TypedData json.RawMessage `json:"typedData" validate:"required"`  // eth_signTypedData_v4 payload: types, primaryType, domain, message
+}
+
+type SignatureResponse struct {
+	Address   string `json:"address"`
+	Hash      string `json:"hash"`      // digest that was signed
+	Signature string `json:"signature"` // 65 bytes r ‖ s ‖ v with v = 27/28
+}
+
+// VerifySignatureRequest carries either a message or typed data
+type VerifySignatureRequest struct {
+	Message   string          `json:"message,omitempty"`
+	Encoding  string          `json:"encoding,omitempty" validate:"oneof=utf8|hex"`
+	TypedData json.RawMessage `json:"typedData,omitempty"`
+	Signature string          `json:"signature" validate:"required,hex"`
+	Address   string          `json:"address,omitempty" validate:"account"` // optional expected signer: alias or address
+}
+
+type VerifySignatureResponse struct {
+	Address string `json:"address"` // recovered signer
+	Alias   string `json:"alias,omitempty"`
+	Hash    string `json:"hash"`
+	Valid   *bool  `json:"valid,omitempty"` // only set when an expected address was given
+}
+
+type SiweNonceResponse struct {
+	Nonce     string    `json:"nonce"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type SiweVerifyRequest struct {
+	Message   string `json:"message" validate:"required"`       // EIP-4361 text exactly as signed
+	Signature string `json:"signature" validate:"required,hex"` // personal_sign signature, hex
+}
+
+type SiweSessionResponse struct {
+	Token     string    `json:"token"` // send as "Authorization: Bearer <token>"
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type BalanceResponse struct {
+	Address string `json:"address"`
+	Alias   string `json:"alias,omitempty"`
+	Block   uint64 `json:"block"` // block the balance was read at
+	Wei     string `json:"wei"`
+	Ether   string `json:"ether"`
+}
+
+const (
+	TokenStandardERC20  = "erc20"
+	TokenStandardERC721 = "erc721"
+)
+
+type TokenBalance struct {
+	Alias     string `json:"alias"`
+	Address   string `json:"address"`
+	Standard  string `json:"standard"`
+	Symbol    string `json:"symbol,omitempty"`
+	Decimals  *uint8 `json:"decimals,omitempty"`
+	Balance   string `json:"balance,omitempty"`   // raw balanceOf result
+	Formatted string `json:"formatted,omitempty"` // balance scaled by decimals
+	Error     string `json:"error,omitempty"`
+}
+
+type TokenBalancesResponse struct {
+	Address string         `json:"address"`
+	Alias   string         `json:"alias,omitempty"`
+	Block   uint64         `json:"block"`
+	Tokens  []TokenBalance `json:"tokens"`
+}
+
+// ParseAmountResponse is a human-readable amount converted to its raw integer value
+type ParseAmountResponse struct {
+	Input     string `json:"input"`
+	Value     string `json:"value"` // wei for ETH units, base units for tokens
+	Unit      string `json:"unit"`
+	Decimals  uint8  `json:"decimals"`
+	Token     bool   `json:"token"` // unit is a registered ERC-20 symbol
+	Formatted string `json:"formatted"`
+}
+
+type NonceResponse struct {
+	Address      string `json:"address"`
+	Alias        string `json:"alias,omitempty"`
+	Nonce        uint64 `json:"nonce"`        // mined transactions (eth_getTransactionCount at latest)
+	PendingNonce uint64 `json:"pendingNonce"` // next nonce including the mempool
+}
+
+// ChainCapabilities reports which dev-chain controls the connected backend supports
+type ChainCapabilities struct {
+	Backend               string   `json:"backend"` // simulated, geth, anvil or hardhat
+	ClientVersion         string   `json:"clientVersion,omitempty"`
+	Snapshot              bool     `json:"snapshot"`
+	Revert                bool     `json:"revert"`
+	Mine                  bool     `json:"mine"`
+	SetNextBlockTimestamp bool     `json:"setNextBlockTimestamp"`
+	IncreaseTime          bool     `json:"increaseTime"`
+	Notes                 []string `json:"notes,omitempty"`
+}
+
+type ChainSnapshotResponse struct {
+	ID    string `json:"id"`
+	Block uint64 `json:"block"`
+}
+
+type ChainRevertRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type ChainMineRequest struct {
+	Blocks uint64 `json:"blocks,omitempty"` // defaults to 1
+}
+
+type ChainTimeRequest struct {
+	Timestamp uint64 `json:"timestamp,omitempty"` // unix seconds, for next-timestamp
+	Seconds   uint64 `json:"seconds,omitempty"`   // for increase-time
+}
+
+// ChainHeadResponse describes the head after a chain-control call
+type ChainHeadResponse struct {
+	Block     uint64 `json:"block"`
+	Hash      string `json:"hash"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// DevInfoResponse is returned by DevServer's /info
+type DevInfoResponse struct {
+	RPCURL        string `json:"rpcUrl"`
+	RPCPort       string `json:"rpcPort"`
+	AccountsCount int    `json:"accountsCount"`
+}
+
+// DevAccountResponse is returned by DevServer's /dev-account
+type DevAccountResponse struct {
+	Address string `json:"address"`
+}
+
+// TestAccountResponse is one entry of DevServer's /accounts
+type TestAccountResponse struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	PrivateKey string `json:"privateKey,omitempty"` // only for in-memory keys while key exposure is on
+}
+
+const (
+	HealthStarting = "starting" // serving HTTP, background workers not started yet
+	HealthOK       = "ok"
+	HealthDegraded = "degraded" // running, but at least one check fails
+	HealthStopping = "stopping" // shutting down, draining requests and workers
+)
+
+// HealthResponse is returned by /healthz and /readyz
+type HealthResponse struct {
+	Status string              `json:"status"`
+	Ready  bool                `json:"ready"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+// HealthCheckResult is one dependency check, such as node connectivity or a log subscription
+type HealthCheckResult struct {
+	Name      string `json:"name"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// LogLevelRequest changes the log level of a running server through PUT /loglevel
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=debug|info|warn|error"`
+}
+
+// LogLevelResponse is the log level in effect
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
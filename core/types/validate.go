@@ -0,0 +1,190 @@
+package types
+
+import (
+	"encoding/hex"
+	"errors"
+	"eth-toy-client/core/units"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Request fields declare their checks in a `validate` tag, e.g. `validate:"required,amount"`.
+// Rules other than required are skipped for empty fields, so optional fields stay optional.
+//
+//	required  non-empty string, slice or map, non-nil pointer
+//	alias     a name without whitespace that is not itself an address
+//	account   an alias or a 0x-prefixed address
+//	address   a 0x-prefixed 20-byte hex address
+//	hex       hex data, with or without 0x
+//	amount    wei as a decimal integer, or a decimal with a unit such as "0.5 ether"
+//	nonce     an account nonce below 2^64-1 (EIP-2681)
+//	min=N     lower bound for integers
+//	max=N     upper bound for integers
+//	oneof=a|b one of the listed strings
+
+// FieldError names one invalid request field by its JSON name
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a request
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	parts := make([]string, len(e))
+	for i, fieldErr := range e {
+		parts[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks the validate tags of a request struct and returns a ValidationError when any fail
+func Validate(req any) error {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationError
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		field := v.Field(i)
+		name := jsonName(t.Field(i))
+		for _, rule := range strings.Split(tag, ",") {
+			if rule != "required" && field.IsZero() {
+				break
+			}
+			if msg := checkRule(rule, field); msg != "" {
+				errs = append(errs, FieldError{Field: name, Message: msg})
+				break
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+func checkRule(rule string, field reflect.Value) string {
+	rule, arg, _ := strings.Cut(rule, "=")
+	for field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
+
+	switch rule {
+	case "required":
+		if field.IsZero() || (field.Kind() == reflect.Slice || field.Kind() == reflect.Map) && field.Len() == 0 {
+			return "is required"
+		}
+	case "alias":
+		s := field.String()
+		if strings.ContainsFunc(s, isSpace) {
+			return "must not contain whitespace"
+		}
+		if strings.HasPrefix(s, "0x") && addressPattern.MatchString(s) {
+			return "must be a name, not an address"
+		}
+	case "account":
+		s := field.String()
+		if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+			if !addressPattern.MatchString(s) {
+				return fmt.Sprintf("'%s' is not a valid address", s)
+			}
+		} else if strings.ContainsFunc(s, isSpace) {
+			return "must be an alias or an address"
+		}
+	case "address":
+		if !addressPattern.MatchString(field.String()) {
+			return fmt.Sprintf("'%s' is not a valid address", field.String())
+		}
+	case "hex":
+		if _, err := DecodeHex(field.String()); err != nil {
+			return err.Error()
+		}
+	case "amount":
+		if _, err := units.ParseAmount(field.String()); err != nil {
+			return err.Error()
+		}
+	case "nonce":
+		if field.Uint() >= math.MaxUint64 {
+			return "nonce must be below 2^64-1"
+		}
+	case "min", "max":
+		bound, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: bad %s bound %q", rule, arg))
+		}
+		var n int64
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = field.Int()
+		default:
+			if field.Uint() > math.MaxInt64 {
+				n = math.MaxInt64
+			} else {
+				n = int64(field.Uint())
+			}
+		}
+		if rule == "min" && n < bound {
+			return fmt.Sprintf("must be at least %d", bound)
+		}
+		if rule == "max" && n > bound {
+			return fmt.Sprintf("must be at most %d", bound)
+		}
+	case "oneof":
+		options := strings.Split(arg, "|")
+		for _, option := range options {
+			if field.String() == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	default:
+		panic(fmt.Sprintf("validate: unknown rule %q", rule))
+	}
+	return ""
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// DecodeHex decodes hex data with or without the 0x prefix. Every byte takes two digits, an odd
+// number of digits is rejected rather than guessed at.
+func DecodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 == 1 {
+		return nil, errors.New("invalid hex data: odd number of digits")
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex data: %w", err)
+	}
+	return data, nil
+}
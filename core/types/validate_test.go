@@ -0,0 +1,71 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHex(t *testing.T) {
+	for _, raw := range []string{"0x6080", "6080", "0X6080"} {
+		data, err := DecodeHex(raw)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x60, 0x80}, data)
+	}
+	_, err := DecodeHex("0xabc")
+	require.ErrorContains(t, err, "odd number of digits")
+
+	err = Validate(SignTxRequest{From: "alice", Data: "0x608"})
+	var fields ValidationError
+	require.ErrorAs(t, err, &fields)
+	require.Equal(t, []string{"data"}, fieldNames(fields))
+
+	_, err = DecodeHex("0xzz")
+	require.Error(t, err)
+}
+
+func TestValidateReportsEveryField(t *testing.T) {
+	nonce := uint64(1<<64 - 1)
+	err := Validate(&SignTxRequest{
+		To:    "0x1234",
+		Value: "1 dogecoin",
+		Data:  "not hex",
+		Nonce: &nonce,
+	})
+
+	var fields ValidationError
+	require.ErrorAs(t, err, &fields)
+	require.Equal(t, []string{"from", "to", "value", "data", "nonce"}, fieldNames(fields))
+	require.Equal(t, "is required", fields[0].Message)
+}
+
+func TestValidateAcceptsOptionalFieldsWhenEmpty(t *testing.T) {
+	require.NoError(t, Validate(SignTxRequest{From: "alice"}))
+	require.NoError(t, Validate(&SignTxRequest{
+		From:  "alice",
+		To:    "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+		Value: "0.25 ether",
+		Data:  "0x6080",
+	}))
+	require.NoError(t, Validate(&SignTxRequest{From: "alice", To: "bob"}))
+}
+
+func TestValidateRules(t *testing.T) {
+	index := -1
+	err := Validate(&CreateAccountRequest{Name: "0x5FbDB2315678afecb367f032d93F642f64180aa3", Source: "magic", Index: &index})
+	var fields ValidationError
+	require.ErrorAs(t, err, &fields)
+	require.Equal(t, []string{"name", "source", "index"}, fieldNames(fields))
+
+	require.Error(t, Validate(&SignMessageRequest{From: "alice", Encoding: "base64"}))
+	require.Error(t, Validate(&SendBatchRequest{}))
+	require.NoError(t, Validate(&SignMessageRequest{From: "alice", Encoding: MessageEncodingHex}))
+}
+
+func fieldNames(errs ValidationError) []string {
+	names := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		names[i] = fieldErr.Field
+	}
+	return names
+}
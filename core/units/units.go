@@ -0,0 +1,167 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Unit is a named power of ten, either an ETH denomination or a token's decimals
+type Unit struct {
+	Name     string
+	Decimals uint8
+}
+
+var (
+	Wei   = Unit{Name: "wei", Decimals: 0}
+	Gwei  = Unit{Name: "gwei", Decimals: 9}
+	Ether = Unit{Name: "ether", Decimals: 18}
+)
+
+var denominations = map[string]Unit{
+	"wei":    Wei,
+	"kwei":   {Name: "kwei", Decimals: 3},
+	"mwei":   {Name: "mwei", Decimals: 6},
+	"gwei":   Gwei,
+	"szabo":  {Name: "szabo", Decimals: 12},
+	"finney": {Name: "finney", Decimals: 15},
+	"ether":  Ether,
+	"eth":    Ether,
+}
+
+// ErrUnknownUnit is returned for a unit that is neither an ETH denomination nor a known token
+var ErrUnknownUnit = errors.New("unknown unit")
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// LookupUnit finds an ETH denomination by name, case-insensitively
+func LookupUnit(name string) (Unit, bool) {
+	unit, ok := denominations[strings.ToLower(name)]
+	return unit, ok
+}
+
+// One returns a single unit in its base denomination, e.g. Ether.One() is 10^18 wei
+func (u Unit) One() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(u.Decimals)), nil)
+}
+
+// TokenResolver finds the unit of a token symbol such as "USDC", typically from a registered ERC-20
+type TokenResolver interface {
+	TokenUnit(ctx context.Context, symbol string) (Unit, error)
+}
+
+// Amount is a parsed value in the base denomination of its unit (wei for ETH units)
+type Amount struct {
+	Value *big.Int
+	Unit  Unit
+	Token bool // unit is a token symbol rather than an ETH denomination
+}
+
+// Parse reads "<number> <unit>" where unit is an ETH denomination or, when tokens is not nil, a token
+// symbol. A bare integer is taken as wei.
+func Parse(ctx context.Context, s string, tokens TokenResolver) (*Amount, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid amount '%s'", s)
+	}
+	if len(fields) == 1 {
+		value, err := ParseUnits(fields[0], 0)
+		if err != nil {
+			return nil, err
+		}
+		return &Amount{Value: value, Unit: Wei}, nil
+	}
+
+	amount := &Amount{}
+	if unit, ok := LookupUnit(fields[1]); ok {
+		amount.Unit = unit
+	} else if tokens != nil {
+		unit, err := tokens.TokenUnit(ctx, fields[1])
+		if err != nil {
+			return nil, err
+		}
+		amount.Unit, amount.Token = unit, true
+	} else {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownUnit, fields[1])
+	}
+
+	value, err := ParseUnits(fields[0], amount.Unit.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	amount.Value = value
+	return amount, nil
+}
+
+// ParseAmount reads wei from a bare integer or a number with an ETH unit, e.g. "0.5 ether" or "20 gwei"
+func ParseAmount(s string) (*big.Int, error) {
+	amount, err := Parse(context.Background(), s, nil)
+	if err != nil {
+		return nil, err
+	}
+	return amount.Value, nil
+}
+
+// MustParseAmount is ParseAmount for constants, it panics on bad input
+func MustParseAmount(s string) *big.Int {
+	wei, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return wei
+}
+
+// ParseUnits scales a non-negative decimal number by 10^decimals, e.g. ParseUnits("1.5", 6) → 1500000
+func ParseUnits(number string, decimals uint8) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(number, ".")
+	if len(frac) > int(decimals) {
+		return nil, fmt.Errorf("'%s' has more than %d decimals", number, decimals)
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+	if whole == "" || strings.ContainsFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) {
+		return nil, fmt.Errorf("invalid amount '%s'", number)
+	}
+	value, _ := new(big.Int).SetString(digits, 10)
+	if value.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("amount '%s' does not fit in 256 bits", number)
+	}
+	return value, nil
+}
+
+// FormatUnits renders value as a decimal number of 10^decimals, e.g. FormatUnits(1500000, 6) → "1.5"
+func FormatUnits(value *big.Int, decimals uint8) string {
+	quo, rem := new(big.Int).QuoRem(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo.String()
+	}
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+		quo.Abs(quo)
+		rem.Abs(rem)
+	}
+	// Pad the remainder to the number of decimal places and drop trailing zeros
+	frac := strings.Repeat("0", int(decimals)-len(rem.String())) + rem.String()
+	return sign + quo.String() + "." + strings.TrimRight(frac, "0")
+}
+
+// Format renders value with its unit name, e.g. Format(wei, Ether) → "1.5 ether"
+func Format(value *big.Int, unit Unit) string {
+	return FormatUnits(value, unit.Decimals) + " " + unit.Name
+}
+
+// FormatEther renders a wei amount in ETH without the unit name
+func FormatEther(wei *big.Int) string {
+	return FormatUnits(wei, Ether.Decimals)
+}
+
+// FormatWei parses a decimal wei string and renders it in unit, returning "" when raw is not a number
+func FormatWei(raw string, unit Unit) string {
+	value, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return ""
+	}
+	return Format(value, unit)
+}
@@ -0,0 +1,85 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTokens map[string]uint8
+
+func (f fakeTokens) TokenUnit(_ context.Context, symbol string) (Unit, error) {
+	decimals, ok := f[strings.ToUpper(symbol)]
+	if !ok {
+		return Unit{}, ErrUnknownUnit
+	}
+	return Unit{Name: strings.ToUpper(symbol), Decimals: decimals}, nil
+}
+
+func TestParseAmount(t *testing.T) {
+	for raw, want := range map[string]string{
+		"1000":              "1000",
+		"0.5 ether":         "500000000000000000",
+		"20 gwei":           "20000000000",
+		"1.5 ETH":           "1500000000000000000",
+		"7 wei":             "7",
+		"2 finney":          "2000000000000000",
+		"0.000000001 ether": "1000000000",
+	} {
+		wei, err := ParseAmount(raw)
+		require.NoError(t, err, raw)
+		require.Equal(t, want, wei.String(), raw)
+	}
+
+	for _, raw := range []string{"", "-1", "1.5", "0.1 gwei wei", "0x10", "1e18", ".5 ether", "1.0000000001 gwei"} {
+		_, err := ParseAmount(raw)
+		require.Error(t, err, raw)
+	}
+	_, err := ParseAmount("100 USDC")
+	require.ErrorIs(t, err, ErrUnknownUnit)
+	_, err = ParseAmount(new(big.Int).Lsh(big.NewInt(1), 256).String())
+	require.ErrorContains(t, err, "256 bits")
+}
+
+func TestParseTokenAmount(t *testing.T) {
+	tokens := fakeTokens{"USDC": 6}
+
+	amount, err := Parse(context.Background(), "100 usdc", tokens)
+	require.NoError(t, err)
+	require.Equal(t, "100000000", amount.Value.String())
+	require.Equal(t, Unit{Name: "USDC", Decimals: 6}, amount.Unit)
+	require.True(t, amount.Token)
+
+	amount, err = Parse(context.Background(), "1 ether", tokens)
+	require.NoError(t, err)
+	require.False(t, amount.Token, "ETH denominations win over token symbols")
+
+	_, err = Parse(context.Background(), "1.0000001 USDC", tokens)
+	require.Error(t, err)
+	_, err = Parse(context.Background(), "1 DAI", tokens)
+	require.True(t, errors.Is(err, ErrUnknownUnit))
+}
+
+func TestFormat(t *testing.T) {
+	require.Equal(t, "1", FormatEther(Ether.One()))
+	require.Equal(t, "0.01", FormatEther(MustParseAmount("0.01 ether")))
+	require.Equal(t, "-0.000000001", FormatEther(new(big.Int).Neg(Gwei.One())))
+	require.Equal(t, "0", FormatEther(new(big.Int)))
+	require.Equal(t, "1.5", FormatUnits(big.NewInt(1_500_000), 6))
+	require.Equal(t, "20 gwei", Format(MustParseAmount("20 gwei"), Gwei))
+	require.Equal(t, "0.25 ether", FormatWei("250000000000000000", Ether))
+	require.Equal(t, "", FormatWei("lots", Ether))
+}
+
+func TestFormatRoundTrips(t *testing.T) {
+	for _, raw := range []string{"0", "1", "123456789012345678901234567890", "1000000000000000001"} {
+		wei, _ := new(big.Int).SetString(raw, 10)
+		parsed, err := ParseUnits(FormatUnits(wei, 18), 18)
+		require.NoError(t, err)
+		require.Equal(t, raw, parsed.String())
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"eth-toy-client/core/devutil"
 	"eth-toy-client/core/httpapi"
 	"eth-toy-client/core/logutil"
-	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,6 +15,8 @@ import (
 	"time"
 )
 
+var logger = logutil.For("contractkit")
+
 // Mode determines what task to run
 type Mode string
 
@@ -56,11 +57,11 @@ func CompileContract(opts CompileOptions) (*BuildResult, error) {
 	}
 
 	if _, err := os.Stat(opts.OutBaseDir); os.IsNotExist(err) {
-		logutil.Infof("Output base dir not found — creating: %s", opts.OutBaseDir)
+		logger.Info("Output base dir not found, creating it", "dir", opts.OutBaseDir)
 		if err := os.MkdirAll(opts.OutBaseDir, 0o755); err != nil {
 			return nil, logutil.ErrorErrf("failed to create OutBaseDir: %w", err)
 		}
-		logutil.Infof("✅ Created OutBaseDir: %s", opts.OutBaseDir)
+		logger.Info("✅ Created OutBaseDir", "dir", opts.OutBaseDir)
 	}
 
 	contractName := strings.TrimSuffix(
@@ -68,9 +69,11 @@ func CompileContract(opts CompileOptions) (*BuildResult, error) {
 		filepath.Ext(opts.SolContractPath),
 	)
 
-	logutil.Infof(
-		"Compiling contract: contract=%s solContractPath=%s outBaseDir=%s clean=%v",
-		contractName, opts.SolContractPath, opts.OutBaseDir, opts.Clean)
+	logger.Info("Compiling contract",
+		"contract", contractName,
+		"solContractPath", opts.SolContractPath,
+		"outBaseDir", opts.OutBaseDir,
+		"clean", opts.Clean)
 
 	buildDir := filepath.Join(opts.OutBaseDir, contractName)
 
@@ -86,6 +89,7 @@ func CompileContract(opts CompileOptions) (*BuildResult, error) {
 		"solc",
 		"--abi",
 		"--bin",
+		"--storage-layout",
 		"-o",
 		buildDir,
 		opts.SolContractPath,
@@ -95,7 +99,7 @@ func CompileContract(opts CompileOptions) (*BuildResult, error) {
 		return nil, logutil.ErrorErrf("solc failed: %w\nOutput: %s", err, string(out))
 	}
 
-	logutil.Infof("✅ Compiled %s → %s", opts.SolContractPath, buildDir)
+	logger.Info("✅ Compiled contract", "source", opts.SolContractPath, "buildDir", buildDir)
 
 	binPath := filepath.Join(buildDir, contractName+".bin")
 	binBytes, err := os.ReadFile(binPath)
@@ -105,7 +109,7 @@ func CompileContract(opts CompileOptions) (*BuildResult, error) {
 
 	checksumBytes := sha256.Sum256(binBytes)
 	checksum := hex.EncodeToString(checksumBytes[:])
-	logutil.Infof("🔐 Bytecode SHA256: %s", checksum)
+	logger.Info("🔐 Bytecode checksum", "sha256", checksum)
 
 	return &BuildResult{
 		BuildDir:     buildDir,
@@ -124,9 +128,7 @@ func RunBind(compileOpts CompileOptions) (*BuildResult, error) {
 	goFile := filepath.Join(result.BuildDir, strings.ToLower(result.ContractName)+".go")
 	packageName := strings.ToLower(result.ContractName)
 
-	logutil.Infof("ABI file: %s", abiFile)
-	logutil.Infof("BIN file: %s", binFile)
-	logutil.Infof("Go file: %s", goFile)
+	logger.Debug("Binding contract", "abi", abiFile, "bin", binFile, "go", goFile)
 
 	cmd := exec.Command(
 		"abigen",
@@ -140,13 +142,13 @@ func RunBind(compileOpts CompileOptions) (*BuildResult, error) {
 		return nil, logutil.ErrorErrf("abigen failed: %w\nOutput: %s", err, string(out))
 	}
 
-	logutil.Infof("✅ abigen bind go file: %s → %s", abiFile, goFile)
+	logger.Info("✅ abigen bound contract", "abi", abiFile, "go", goFile)
 	return result, nil
 }
 
 func RunAliasDeploy(alias string, compileOpts CompileOptions, opts DeployOptions) error {
 
-	logutil.Infof("🚀 Deploying contract from alias: %s", opts.FromAlias)
+	logger.Info("🚀 Deploying contract", "from", opts.FromAlias)
 
 	devCtx, err := devutil.GetDevContext(opts.FromAlias)
 	if err != nil {
@@ -160,14 +162,15 @@ func RunAliasDeploy(alias string, compileOpts CompileOptions, opts DeployOptions
 	}
 
 	binPath := filepath.Join(result.BuildDir, result.ContractName+".bin")
+	abiPath := filepath.Join(result.BuildDir, result.ContractName+".abi")
+	storageLayoutPath := filepath.Join(result.BuildDir, result.ContractName+"_storage.json")
 	bytecode, err := os.ReadFile(binPath)
 	if err != nil {
 		return logutil.ErrorErrf("failed to read bin file: %w", err)
 	}
 
 	byteCodeString := string(bytecode)
-	//logutil.Infof("Bytecode: string %s", byteCodeString)
-	fmt.Println("Length of original bytecode:", len(bytecode)) // client
+	logger.Debug("Read bytecode", "bytes", len(bytecode))
 
 	addr, txHash, err := contract.DeployContract(
 		context.Background(),
@@ -179,17 +182,19 @@ func RunAliasDeploy(alias string, compileOpts CompileOptions, opts DeployOptions
 		return logutil.ErrorErrf("contract deployment failed: %w", err)
 	}
 
-	logutil.Infof("✅ Contract deployed at: %s (tx: %s)", addr.Hex(), txHash)
+	logger.Info("✅ Contract deployed", "contract", addr.Hex(), "tx", txHash)
 
-	logutil.Infof("🚀 Register contract from alias: %s", alias)
+	logger.Info("🚀 Registering contract alias", "alias", alias)
 	meta := contract.DeployedContractMetaJSON{
 		Alias:     alias,
 		Address:   addr.Hex(),
 		TxHash:    txHash,
-		ABI:       stringOrEmpty(binPath),
+		ABI:       stringOrEmpty(abiPath),
 		Bytecode:  byteCodeString,
 		Timestamp: time.Now().Unix(),
 		Owner:     opts.FromAlias,
+
+		StorageLayout: stringOrEmpty(storageLayoutPath),
 	}
 
 	_, apiErr, err := httpapi.PostWithAPIResponse[any](devCtx.ServerURL+"/api/register-alias", meta)
@@ -200,7 +205,7 @@ func RunAliasDeploy(alias string, compileOpts CompileOptions, opts DeployOptions
 		return logutil.ErrorErrf("api error: %s — %s", apiErr.Code, apiErr.Message)
 	}
 
-	logutil.Infof("📇 Registered contract alias: %s", alias)
+	logger.Info("📇 Registered contract alias", "alias", alias)
 	return nil
 }
 
@@ -214,7 +219,7 @@ func stringOrEmpty(path string) string {
 }
 
 func RunDeploy(compileOpts CompileOptions, opts DeployOptions) error {
-	logutil.Infof("🚀 Deploying contract from alias: %s", opts.FromAlias)
+	logger.Info("🚀 Deploying contract", "from", opts.FromAlias)
 
 	devCtx, err := devutil.GetDevContext(opts.FromAlias)
 	if err != nil {
@@ -243,6 +248,6 @@ func RunDeploy(compileOpts CompileOptions, opts DeployOptions) error {
 		return logutil.ErrorErrf("contract deployment failed: %w", err)
 	}
 
-	logutil.Infof("✅ Contract deployed at: %s (tx: %s)", addr.Hex(), txHash)
+	logger.Info("✅ Contract deployed", "contract", addr.Hex(), "tx", txHash)
 	return nil
 }
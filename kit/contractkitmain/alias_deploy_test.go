@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"eth-toy-client/accounts"
 	"eth-toy-client/core/logutil"
 	"eth-toy-client/kit/contractkit"
 	"eth-toy-client/kit/mockusdc"
@@ -46,7 +47,7 @@ func deploySingleContract(contractName string, owner string, alias string) {
 
 	err := contractkit.RunAliasDeploy(alias, compileOptions, deployOpts)
 	if err != nil {
-		logutil.Exitf("Deployment failed: %v", err)
+		logutil.Fatal(logger, "💀 Deployment failed", "error", err)
 	}
 }
 
@@ -54,7 +55,7 @@ func deploySingleContract(contractName string, owner string, alias string) {
 
 func TestComposeContractPath(t *testing.T) {
 	counter := Contract("Counter")
-	logutil.Infof("Counter path: %s", counter.ContractPath())
+	logger.Info("Counter path", "path", counter.ContractPath())
 }
 
 func TestALiasDeploySingleContract(t *testing.T) {
@@ -69,7 +70,7 @@ func TestALiasDeployContracts(t *testing.T) {
 	alias := "alice"
 
 	for contractKey, contractPath := range ContractsMap {
-		logutil.Infof("Deploying Contract : %s, Path: %s", contractKey, contractPath)
+		logger.Info("Deploying contract", "contract", contractKey, "path", contractPath)
 		compileOptions := contractkit.CompileOptions{
 			SolContractPath: contractPath,
 			OutBaseDir:      OutBasePath,
@@ -80,7 +81,7 @@ func TestALiasDeployContracts(t *testing.T) {
 		}
 		err := contractkit.RunAliasDeploy(contractKey, compileOptions, deployOpts)
 		if err != nil {
-			logutil.Warnf("Deployment failed: %v Contract: %s", err, contractKey)
+			logger.Warn("⚠️ Deployment failed", "contract", contractKey, "error", err)
 		}
 	}
 
@@ -124,13 +125,8 @@ func TestBindDeployUSDC(t *testing.T) {
 	}
 	defer client.Close()
 
-	//key := "0x4f3edf983ac636a65a842ce7c78d9aa706d3b113b37b1e1a7e3975d3fef1fdc3"
-	//// 2. Load your private key (from funded dev account)
-	//privateKeyHex := key[:2] // ⛔ keep secret!
-	privateKey, err := crypto.HexToECDSA("4f3edf983ac636a65a842ce7c78d9aa706d3b113b37b1e1a7e3975d3fef1fdc3")
-	if err != nil {
-		log.Fatalf("❌ Failed to load private key: %v", err)
-	}
+	// 2. Load alice's key (funded by DevServer)
+	privateKey, _ := accounts.MustDerive(accounts.DefaultConfig())[0].PrivateKey()
 
 	// 3. Prepare auth transactor
 	fromAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
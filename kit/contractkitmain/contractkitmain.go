@@ -3,15 +3,16 @@ package main
 import (
 	"eth-toy-client/core/logutil"
 	"eth-toy-client/kit/contractkit"
-	"fmt"
 	"os"
 )
 
+var logger = logutil.For("contractkitmain")
+
 func main() {
-	logutil.Infof("📣 super duper main started")
+	logger.Info("📣 super duper main started")
 
 	if len(os.Args) < 2 {
-		logutil.Exitf("Usage: contractkitmain [compile|bind|deploy]")
+		logutil.Fatal(logger, "Usage: contractkitmain [compile|bind|deploy]")
 	}
 
 	mode := contractkit.Mode(os.Args[1])
@@ -26,37 +27,37 @@ func main() {
 
 	switch mode {
 	case contractkit.ModeCompile:
-		fmt.Println("🛠️ Running in COMPILE mode")
+		logger.Info("🛠️ Running in COMPILE mode")
 		_, err := contractkit.CompileContract(compileOptions)
 		if err != nil {
 			panic(err)
 		}
 	case contractkit.ModeBind:
-		fmt.Println("🔧 Running in BIND mode")
+		logger.Info("🔧 Running in BIND mode")
 		_, err := contractkit.RunBind(compileOptions)
 		if err != nil {
 			panic(err)
 		}
 	case contractkit.ModeDeploy:
-		logutil.Infof("🚀 Running in DEPLOY mode")
+		logger.Info("🚀 Running in DEPLOY mode")
 
 		deployOpts := contractkit.DeployOptions{
 			FromAlias: alias,
 		}
 		err := contractkit.RunDeploy(compileOptions, deployOpts)
 		if err != nil {
-			logutil.Exitf("Deployment failed: %v", err)
+			logutil.Fatal(logger, "💀 Deployment failed", "error", err)
 		}
 	case contractkit.ModeAliasDeploy:
-		logutil.Infof("🚀 Running in ALIAS DEPLOY mode")
+		logger.Info("🚀 Running in ALIAS DEPLOY mode")
 		deployOpts := contractkit.DeployOptions{
 			FromAlias: alias,
 		}
 		err := contractkit.RunAliasDeploy("CounterV1", compileOptions, deployOpts)
 		if err != nil {
-			logutil.Exitf("Deployment failed: %v", err)
+			logutil.Fatal(logger, "💀 Deployment failed", "error", err)
 		}
 	default:
-		logutil.Exitf("Usage: contractkitmain [compile|bind|deploy]")
+		logutil.Fatal(logger, "Usage: contractkitmain [compile|bind|deploy]")
 	}
 }
@@ -1,6 +1,8 @@
 package logbus
 
 import (
+	"eth-toy-client/core/metrics"
+	"fmt"
 	"github.com/ethereum/go-ethereum/core/types"
 	"sync"
 )
@@ -17,43 +19,58 @@ type LogEvent struct {
 
 type LogBroadcaster interface {
 	Subscribe(chan<- LogEvent)
+	// SubscribeAs names the subscriber in the delivered and dropped event metrics
+	SubscribeAs(name string, ch chan<- LogEvent)
 	Unsubscribe(chan<- LogEvent)
 	Publish(LogEvent)
 }
 
+type subscriber struct {
+	name string
+	ch   chan<- LogEvent
+}
+
 type inMemoryBroadcaster struct {
-	subscribers []chan<- LogEvent
+	subscribers []subscriber
 	mu          sync.Mutex
 }
 
 func NewLogBroadcaster() LogBroadcaster {
 	return &inMemoryBroadcaster{
-		subscribers: make([]chan<- LogEvent, 0),
+		subscribers: make([]subscriber, 0),
 	}
 }
 
 func (b *inMemoryBroadcaster) Subscribe(ch chan<- LogEvent) {
+	b.SubscribeAs("", ch)
+}
+
+func (b *inMemoryBroadcaster) SubscribeAs(name string, ch chan<- LogEvent) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	for _, sub := range b.subscribers {
-		if sub == ch {
+		if sub.ch == ch {
 			panic("channel already subscribed") // or return an error/log
 		}
 	}
 
-	b.subscribers = append(b.subscribers, ch)
+	if name == "" {
+		name = fmt.Sprintf("subscriber-%d", len(b.subscribers)+1)
+	}
+	b.subscribers = append(b.subscribers, subscriber{name: name, ch: ch})
 }
 
 func (b *inMemoryBroadcaster) Publish(event LogEvent) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	for _, ch := range b.subscribers {
+	for _, sub := range b.subscribers {
 		select {
-		case ch <- event:
-			// sent successfully
+		case sub.ch <- event:
+			metrics.LogEventsDelivered.WithLabelValues(sub.name).Inc()
 		default:
-			// optional: drop or log if subscriber is full
+			// a full subscriber is skipped so it cannot block the others
+			metrics.LogEventsDropped.WithLabelValues(sub.name).Inc()
 		}
 	}
 }
@@ -62,7 +79,7 @@ func (b *inMemoryBroadcaster) Unsubscribe(ch chan<- LogEvent) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	for i, sub := range b.subscribers {
-		if sub == ch {
+		if sub.ch == ch {
 			b.subscribers = append(b.subscribers[:i], b.subscribers[i+1:]...)
 			break
 		}
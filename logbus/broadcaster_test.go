@@ -1,7 +1,9 @@
 package logbus
 
 import (
+	"eth-toy-client/core/metrics"
 	"fmt"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"log"
 	"time"
@@ -190,3 +192,15 @@ func TestPublisherLoop2(t *testing.T) {
 	log.Printf("👋 App Exiting")
 	close(done)
 }
+
+func TestFullSubscriberCountsDroppedEvents(t *testing.T) {
+	b := NewLogBroadcaster()
+	ch := make(chan LogEvent, 1)
+	b.SubscribeAs("FullConsumer", ch)
+
+	b.Publish(LogEvent{LogType: UnknownEventLog, TxHash: "0x1"})
+	b.Publish(LogEvent{LogType: UnknownEventLog, TxHash: "0x2"})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.LogEventsDelivered.WithLabelValues("FullConsumer")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.LogEventsDropped.WithLabelValues("FullConsumer")))
+}
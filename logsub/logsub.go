@@ -2,16 +2,18 @@ package logsub
 
 import (
 	"context"
+	"eth-toy-client/core/logutil"
 	"eth-toy-client/logbus"
 	"fmt"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"log"
 	"time"
 )
 
+var logger = logutil.For("logsub")
+
 type ListenerConfig struct {
 	WebSocketURL string // WebSocket URL to connect to the Ethereum node
 }
@@ -38,7 +40,7 @@ func NewLogListener(config *ListenerConfig, broadcaster logbus.LogBroadcaster, d
 }
 
 func (l *LogListener) Listen(ctx context.Context) {
-	log.Printf("✅ LogListener started. Listening for logs...")
+	logger.InfoContext(ctx, "✅ LogListener started, listening for logs")
 
 	// Example log filter: change as needed
 	filter := ethereum.FilterQuery{}
@@ -47,19 +49,19 @@ func (l *LogListener) Listen(ctx context.Context) {
 
 	sub, err := l.Client.SubscribeFilterLogs(ctx, filter, logs)
 	if err != nil {
-		log.Fatalf("❌ Failed to subscribe to logs: %v", err)
+		logutil.Fatal(logger, "❌ Failed to subscribe to logs", "error", err)
 	}
 
 	for {
 		select {
 		case logEvent := <-logs:
 			// Print the received log to console
-			log.Printf("🎤 Received Log: %v", logEvent)
+			logger.DebugContext(ctx, "🎤 Received log", "address", logEvent.Address.Hex(), "tx", logEvent.TxHash.Hex(), "index", logEvent.Index)
 
 			// Decode the log using the DefaultDecoder
 			event, err := l.Decoder.DecodeLog(logEvent)
 			if err != nil {
-				log.Printf("❌ Failed to decode log: %v", err)
+				logger.ErrorContext(ctx, "❌ Failed to decode log", "tx", logEvent.TxHash.Hex(), "error", err)
 				continue
 			}
 
@@ -67,17 +69,17 @@ func (l *LogListener) Listen(ctx context.Context) {
 			l.Broadcaster.Publish(event)
 
 		case err := <-sub.Err():
-			log.Printf("⚠️ Subscription error: %v", err)
+			logger.WarnContext(ctx, "⚠️ Subscription error", "error", err)
 
 		case <-ctx.Done():
-			log.Println("👋 LogListener exiting...")
+			logger.InfoContext(ctx, "👋 LogListener exiting")
 			return
 		}
 	}
 }
 
 func (l *LogListener) Listen2(ctx context.Context) {
-	log.Printf("✅ LogListener started. Listening for logs...")
+	logger.InfoContext(ctx, "✅ LogListener started, listening for logs")
 
 	// Example log filter: change as needed
 	filter := ethereum.FilterQuery{
@@ -88,14 +90,14 @@ func (l *LogListener) Listen2(ctx context.Context) {
 
 	sub, err := l.Client.SubscribeFilterLogs(ctx, filter, logs)
 	if err != nil {
-		log.Fatalf("❌ Failed to subscribe to logs: %v", err)
+		logutil.Fatal(logger, "❌ Failed to subscribe to logs", "error", err)
 	}
 
 	for {
 		select {
 		case logEvent := <-logs:
 			// Print the received log to console
-			log.Printf("🎤 Received Log: %v", logEvent)
+			logger.DebugContext(ctx, "🎤 Received log", "address", logEvent.Address.Hex(), "tx", logEvent.TxHash.Hex(), "index", logEvent.Index)
 
 			// Convert the Ethereum log into a LogEvent
 			event := logbus.LogEvent{
@@ -110,10 +112,10 @@ func (l *LogListener) Listen2(ctx context.Context) {
 			// Publish this log event to LogBroadcaster
 			l.Broadcaster.Publish(event)
 		case err := <-sub.Err():
-			log.Printf("⚠️ Subscription error: %v", err)
+			logger.WarnContext(ctx, "⚠️ Subscription error", "error", err)
 
 		case <-ctx.Done():
-			log.Println("👋 LogListener exiting...")
+			logger.InfoContext(ctx, "👋 LogListener exiting")
 			return
 		}
 	}
@@ -123,7 +125,7 @@ func (l *LogListener) StartSimulateListening() {
 	// Here you would start the actual logic for listening to the Ethereum logs
 	// This will involve setting up log filters, subscribing to logs, etc.
 
-	log.Println("✅LogListener started. Listening for logs...")
+	logger.Info("✅ LogListener started, listening for logs")
 
 	event := logbus.LogEvent{
 		LogType: logbus.UnknownEventLog,
@@ -141,13 +143,12 @@ type PrintToConsole struct {
 
 func (p *PrintToConsole) Consume() {
 	for event := range p.Events {
-		log.Printf(
-			"🚀 %s received event: %v, address:%s, TxHash:%s and Args:%v",
-			p.Name,
-			event.LogType,
-			event.Contract,
-			event.TxHash,
-			event.Args)
+		logger.Info("🚀 Received event",
+			"consumer", p.Name,
+			"type", event.LogType,
+			"address", event.Contract,
+			"tx", event.TxHash,
+			"args", event.Args)
 	}
 }
 
@@ -44,7 +44,7 @@ func main() {
 
 	// Loop through test accounts and fund each
 	for _, acct := range TestAccounts {
-		log.Printf("💸 Funding %s (%s)...", acct.Name, acct.Addr.Hex())
+		log.Printf("💸 Funding %s (%s)...", acct.Name, acct.Address.Hex())
 
 		// Get dev's latest nonce
 		nonce, err := client.PendingNonceAt(ctx, devAddr)
@@ -62,7 +62,7 @@ func main() {
 			GasTipCap: tip,
 			GasFeeCap: feeCap,
 			Gas:       21000,
-			To:        &acct.Addr,
+			To:        &acct.Address,
 			Value:     big.NewInt(1e18), // 1 ETH
 		})
 
@@ -80,7 +80,7 @@ func main() {
 
 	// Optional: confirm balances
 	for _, acct := range TestAccounts {
-		bal, err := client.BalanceAt(ctx, acct.Addr, nil)
+		bal, err := client.BalanceAt(ctx, acct.Address, nil)
 		if err != nil {
 			log.Printf("⚠️  Failed to get balance: %v", err)
 			continue
@@ -0,0 +1,125 @@
+package devserver
+
+import (
+	"eth-toy-client/accounts"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"sort"
+	"sync"
+)
+
+// AccountStore holds DevServer's named accounts. Accounts can be added and removed while the
+// server runs, so every access goes through the lock.
+type AccountStore struct {
+	mu        sync.RWMutex
+	config    accounts.Config
+	byName    map[string]*TestAccount
+	nextIndex int // next unused derivation index of the mnemonic
+}
+
+func NewAccountStore(config accounts.Config, initial []*TestAccount) *AccountStore {
+	store := &AccountStore{
+		config:    config,
+		byName:    make(map[string]*TestAccount, len(initial)),
+		nextIndex: len(initial),
+	}
+	for _, acc := range initial {
+		store.byName[acc.Name] = acc
+	}
+	return store
+}
+
+// ExposeKeys reports whether HTTP handlers may return in-memory private keys
+func (s *AccountStore) ExposeKeys() bool {
+	return s.config.ExposeKeys
+}
+
+func (s *AccountStore) Get(name string) (*TestAccount, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	acc, ok := s.byName[name]
+	return acc, ok
+}
+
+func (s *AccountStore) ByAddress(address common.Address) (*TestAccount, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, acc := range s.byName {
+		if acc.Address == address {
+			return acc, true
+		}
+	}
+	return nil, false
+}
+
+// List returns a snapshot of the accounts sorted by name
+func (s *AccountStore) List() []*TestAccount {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*TestAccount, 0, len(s.byName))
+	for _, acc := range s.byName {
+		list = append(list, acc)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func (s *AccountStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byName)
+}
+
+// Add registers an account, rejecting names and addresses that are already taken
+func (s *AccountStore) Add(acc *TestAccount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addLocked(acc)
+}
+
+// Derive adds the account at the given mnemonic index, or at the next unused one when index is nil
+func (s *AccountStore) Derive(name string, index *int) (*TestAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.nextIndex
+	if index != nil {
+		i = *index
+	}
+	acc, err := accounts.DeriveAccount(s.config, name, i)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addLocked(acc); err != nil {
+		return nil, err
+	}
+	if i >= s.nextIndex {
+		s.nextIndex = i + 1
+	}
+	return acc, nil
+}
+
+func (s *AccountStore) addLocked(acc *TestAccount) error {
+	if _, ok := s.byName[acc.Name]; ok {
+		return fmt.Errorf("account '%s' already exists", acc.Name)
+	}
+	for _, existing := range s.byName {
+		if existing.Address == acc.Address {
+			return fmt.Errorf("address %s is already registered as '%s'", acc.Address.Hex(), existing.Name)
+		}
+	}
+	s.byName[acc.Name] = acc
+	return nil
+}
+
+func (s *AccountStore) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.byName[name]; !ok {
+		return false
+	}
+	delete(s.byName, name)
+	return true
+}
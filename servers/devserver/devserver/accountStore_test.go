@@ -0,0 +1,70 @@
+package devserver
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"eth-toy-client/accounts"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *AccountStore {
+	cfg := accounts.DefaultConfig()
+	cfg.Count = 2
+	return NewAccountStore(cfg, accounts.MustDerive(cfg))
+}
+
+func TestAccountStoreDeriveUsesNextIndex(t *testing.T) {
+	store := newTestStore(t)
+
+	acc, err := store.Derive("carol", nil)
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/60'/0'/0/2", acc.Path)
+
+	_, err = store.Derive("alice2", new(int))
+	require.Error(t, err, "index 0 is alice's address")
+
+	_, err = store.Derive("bob", nil)
+	require.Error(t, err, "names are unique")
+	require.Equal(t, 3, store.Len())
+}
+
+func TestCreateAccountSources(t *testing.T) {
+	store := newTestStore(t)
+
+	random, err := createAccount(store, toytypes.CreateAccountRequest{Name: "temp"})
+	require.NoError(t, err)
+	found, ok := store.ByAddress(random.Address)
+	require.True(t, ok)
+	require.Equal(t, "temp", found.Name)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	ksAccount, err := ks.ImportECDSA(key, "secret")
+	require.NoError(t, err)
+	encrypted, err := ks.Export(ksAccount, "secret", "secret")
+	require.NoError(t, err)
+
+	imported, err := createAccount(store, toytypes.CreateAccountRequest{
+		Name:     "imported",
+		Source:   toytypes.AccountSourceKeystore,
+		Keystore: string(encrypted),
+		Password: "secret",
+	})
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), imported.Address)
+
+	_, err = createAccount(store, toytypes.CreateAccountRequest{
+		Name:       "again",
+		Source:     toytypes.AccountSourcePrivateKey,
+		PrivateKey: "0x" + hex.EncodeToString(crypto.FromECDSA(key)),
+	})
+	require.Error(t, err, "the same key cannot be registered twice")
+
+	require.True(t, store.Remove("imported"))
+	require.False(t, store.Remove("imported"))
+}
@@ -0,0 +1,182 @@
+package devserver
+
+import (
+	"errors"
+	"eth-toy-client/accounts"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"eth-toy-client/servers/servers"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+func handleListAccounts(accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list := make([]toytypes.AccountInfo, 0, accounts.Len())
+		for _, acc := range accounts.List() {
+			list = append(list, accountInfo(acc))
+		}
+		httpapi.WriteOK(w, &list)
+	}
+}
+
+func handleCreateAccount(nodeClient *servers.NodeClient, devAccount common.Address, accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.CreateAccountRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.Name == "" || common.IsHexAddress(req.Name) {
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, "Account name is required and must not be an address")
+			return
+		}
+
+		var fund *big.Int
+		if req.Fund != "" {
+			var err error
+			if fund, err = units.ParseAmount(req.Fund); err != nil {
+				fieldError(w, "fund", err.Error())
+				return
+			}
+		}
+
+		acc, err := createAccount(accounts, req)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Failed to create account", "alias", req.Name, "error", err)
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+			return
+		}
+		logger.InfoContext(r.Context(), "🆕 Created account", "alias", acc.Name, "address", acc.Address.Hex())
+
+		resp := &toytypes.CreateAccountResponse{Account: accountInfo(acc)}
+		if fund != nil && fund.Sign() > 0 {
+			txHash, err := FundAccount(r.Context(), nodeClient.RPCClient, devAccount, acc.Address, fund)
+			if err != nil {
+				// Roll back so a retry with the same name does not collide
+				accounts.Remove(acc.Name)
+				logger.ErrorContext(r.Context(), "❌ Failed to fund account", "alias", acc.Name, "error", err)
+				httpapi.Fail(w, httpapi.ErrTxRejected, err.Error())
+				return
+			}
+			resp.FundTxHash = txHash.Hex()
+		}
+
+		httpapi.WriteOK(w, resp)
+	}
+}
+
+func createAccount(store *AccountStore, req toytypes.CreateAccountRequest) (*TestAccount, error) {
+	switch req.Source {
+	case "", toytypes.AccountSourceRandom:
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		acc := accounts.NewTestAccount(req.Name, key)
+		return acc, store.Add(acc)
+
+	case toytypes.AccountSourceDerived:
+		return store.Derive(req.Name, req.Index)
+
+	case toytypes.AccountSourcePrivateKey:
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(req.PrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		acc := accounts.NewTestAccount(req.Name, key)
+		return acc, store.Add(acc)
+
+	case toytypes.AccountSourceKeystore:
+		if req.Keystore == "" {
+			return nil, errors.New("keystore JSON is required")
+		}
+		key, err := keystore.DecryptKey([]byte(req.Keystore), req.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+		}
+		acc := accounts.NewTestAccount(req.Name, key.PrivateKey)
+		return acc, store.Add(acc)
+	}
+	return nil, fmt.Errorf("unknown source '%s'", req.Source)
+}
+
+func handleDeleteAccount(accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		alias := r.PathValue("alias")
+		logutil.Annotate(r.Context(), "alias", alias)
+		if !accounts.Remove(alias) {
+			httpapi.Fail(w, httpapi.ErrNotFound, fmt.Sprintf("Account '%s' not found", alias))
+			return
+		}
+		logger.InfoContext(r.Context(), "🗑️ Removed account", "alias", alias)
+		httpapi.WriteOK(w, &toytypes.AccountInfo{Name: alias})
+	}
+}
+
+func handleFundAccount(nodeClient *servers.NodeClient, devAccount common.Address, accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		alias := r.PathValue("alias")
+		logutil.Annotate(r.Context(), "alias", alias)
+		acc, ok := accounts.Get(alias)
+		if !ok {
+			httpapi.Fail(w, httpapi.ErrNotFound, fmt.Sprintf("Account '%s' not found", alias))
+			return
+		}
+
+		var req toytypes.FundAccountRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		value := DefaultFunding
+		if req.Value != "" {
+			var err error
+			if value, err = units.ParseAmount(req.Value); err != nil || value.Sign() == 0 {
+				fieldError(w, "value", "must be a positive amount")
+				return
+			}
+		}
+
+		ctx := r.Context()
+		txHash, err := FundAccount(ctx, nodeClient.RPCClient, devAccount, acc.Address, value)
+		if err != nil {
+			logger.ErrorContext(ctx, "❌ Failed to fund account", "alias", alias, "error", err)
+			httpapi.Fail(w, httpapi.ErrTxRejected, err.Error())
+			return
+		}
+		logutil.Annotate(ctx, "tx", txHash.Hex())
+		logger.InfoContext(ctx, "📤 Funded account", "alias", alias, "address", acc.Address.Hex(), "wei", value)
+
+		resp := &toytypes.FundAccountResponse{TxHash: txHash.Hex()}
+		if req.Wait {
+			if _, err := waitForReceipt(ctx, nodeClient.Client, txHash); err != nil {
+				logger.ErrorContext(ctx, "❌ Failed waiting for receipt", "error", err)
+				httpapi.Fail(w, httpapi.ErrTimeout, err.Error())
+				return
+			}
+			balance, err := nodeClient.Client.BalanceAt(ctx, acc.Address, nil)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+				return
+			}
+			resp.Balance = balance.String()
+			resp.BalanceFormatted = units.Format(balance, units.Ether)
+		}
+
+		httpapi.WriteOK(w, resp)
+	}
+}
+
+func accountInfo(acc *TestAccount) toytypes.AccountInfo {
+	return toytypes.AccountInfo{
+		Name:    acc.Name,
+		Address: acc.Address.Hex(),
+		Path:    acc.Path,
+	}
+}
@@ -0,0 +1,222 @@
+package devserver
+
+import (
+	"context"
+	"errors"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"eth-toy-client/servers/servers"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"sort"
+)
+
+func handleBalance(nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		address, alias, block, ok := balanceTarget(w, r, nodeClient, accounts, reg)
+		if !ok {
+			return
+		}
+
+		wei, err := nodeClient.Client.BalanceAt(r.Context(), address, new(big.Int).SetUint64(block))
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Failed to read balance", "address", address.Hex(), "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		httpapi.WriteOK(w, &toytypes.BalanceResponse{
+			Address: address.Hex(),
+			Alias:   alias,
+			Block:   block,
+			Wei:     wei.String(),
+			Ether:   units.FormatEther(wei),
+		})
+	}
+}
+
+// handleTokenBalances reads balanceOf for every registered contract whose ABI looks like an ERC-20 or ERC-721
+func handleTokenBalances(nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		address, alias, block, ok := balanceTarget(w, r, nodeClient, accounts, reg)
+		if !ok {
+			return
+		}
+
+		tokens := []toytypes.TokenBalance{}
+		for _, info := range reg.All() {
+			standard := tokenStandard(info.ParsedABI)
+			if standard == "" {
+				continue
+			}
+			token := tokenBalance(r.Context(), nodeClient.Client, info, standard, address, new(big.Int).SetUint64(block))
+			if token.Error != "" {
+				logger.WarnContext(r.Context(), "⚠️ Failed to read token balance", "token", info.Alias, "address", address.Hex(), "error", token.Error)
+			}
+			tokens = append(tokens, token)
+		}
+		sort.Slice(tokens, func(i, j int) bool {
+			return tokens[i].Alias < tokens[j].Alias
+		})
+
+		httpapi.WriteOK(w, &toytypes.TokenBalancesResponse{
+			Address: address.Hex(),
+			Alias:   alias,
+			Block:   block,
+			Tokens:  tokens,
+		})
+	}
+}
+
+func handleNonce(nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		alias := r.PathValue("alias")
+		logutil.Annotate(r.Context(), "alias", alias)
+		address, err := resolveAddress(alias, accounts, reg)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrNotFound, err.Error())
+			return
+		}
+
+		nonce, err := nodeClient.Client.NonceAt(r.Context(), address, nil)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+		pending, err := nodeClient.Client.PendingNonceAt(r.Context(), address)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		httpapi.WriteOK(w, &toytypes.NonceResponse{
+			Address:      address.Hex(),
+			Alias:        aliasFor(alias, address),
+			Nonce:        nonce,
+			PendingNonce: pending,
+		})
+	}
+}
+
+// balanceTarget resolves the {alias} path value and the optional ?block= query, pinning "latest" to a
+// concrete number so every read of one request sees the same state
+func balanceTarget(w http.ResponseWriter, r *http.Request, nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry) (common.Address, string, uint64, bool) {
+	alias := r.PathValue("alias")
+	logutil.Annotate(r.Context(), "alias", alias)
+	address, err := resolveAddress(alias, accounts, reg)
+	if err != nil {
+		httpapi.Fail(w, httpapi.ErrNotFound, err.Error())
+		return common.Address{}, "", 0, false
+	}
+
+	block, err := parseBlockParam(r.URL.Query().Get("block"))
+	if err != nil {
+		httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+		return common.Address{}, "", 0, false
+	}
+	if block == nil {
+		latest, err := nodeClient.Client.BlockNumber(r.Context())
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return common.Address{}, "", 0, false
+		}
+		block = &latest
+	}
+	return address, aliasFor(alias, address), *block, true
+}
+
+// parseBlockParam accepts a decimal or 0x-prefixed block number, or "" / "latest" for the head
+func parseBlockParam(raw string) (*uint64, error) {
+	if raw == "" || raw == "latest" {
+		return nil, nil
+	}
+	n, ok := new(big.Int).SetString(raw, 0)
+	if !ok || n.Sign() < 0 || !n.IsUint64() {
+		return nil, fmt.Errorf("invalid block '%s'", raw)
+	}
+	block := n.Uint64()
+	return &block, nil
+}
+
+// aliasFor hides the alias when the caller passed a raw address
+func aliasFor(alias string, address common.Address) string {
+	if common.IsHexAddress(alias) {
+		return ""
+	}
+	return alias
+}
+
+// tokenStandard guesses the token standard from the ABI: both have balanceOf(address), only ERC-721 has ownerOf
+func tokenStandard(parsed *abi.ABI) string {
+	if parsed == nil {
+		return ""
+	}
+	balanceOf, ok := parsed.Methods["balanceOf"]
+	if !ok || len(balanceOf.Inputs) != 1 || balanceOf.Inputs[0].Type.T != abi.AddressTy {
+		return ""
+	}
+	if _, ok := parsed.Methods["ownerOf"]; ok {
+		return toytypes.TokenStandardERC721
+	}
+	return toytypes.TokenStandardERC20
+}
+
+func tokenBalance(ctx context.Context, caller ethereum.ContractCaller, info contract.DeployedContractInfo, standard string, owner common.Address, block *big.Int) toytypes.TokenBalance {
+	token := common.HexToAddress(info.Address.Address)
+	result := toytypes.TokenBalance{
+		Alias:    info.Alias,
+		Address:  token.Hex(),
+		Standard: standard,
+	}
+
+	var balance *big.Int
+	if err := callView(ctx, caller, info.ParsedABI, token, block, "balanceOf", &balance, owner); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Balance = balance.String()
+
+	// symbol and decimals are optional in both standards
+	var symbol string
+	if callView(ctx, caller, info.ParsedABI, token, block, "symbol", &symbol) == nil {
+		result.Symbol = symbol
+	}
+	var decimals uint8
+	if standard == toytypes.TokenStandardERC20 && callView(ctx, caller, info.ParsedABI, token, block, "decimals", &decimals) == nil {
+		result.Decimals = &decimals
+		result.Formatted = units.FormatUnits(balance, decimals)
+	} else {
+		result.Formatted = balance.String()
+	}
+	return result
+}
+
+// callView calls a single-output view method and stores its result in out
+func callView(ctx context.Context, caller ethereum.ContractCaller, parsed *abi.ABI, to common.Address, block *big.Int, method string, out interface{}, args ...interface{}) error {
+	if _, ok := parsed.Methods[method]; !ok {
+		return fmt.Errorf("ABI has no %s method", method)
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, block)
+	if err != nil {
+		return err
+	}
+	values, err := parsed.Unpack(method, output)
+	if err != nil {
+		return err
+	}
+	if len(values) != 1 {
+		return errors.New("expected exactly one return value")
+	}
+	return parsed.Methods[method].Outputs.Copy(out, values)
+}
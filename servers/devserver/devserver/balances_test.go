@@ -0,0 +1,71 @@
+package devserver
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testERC20ABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`
+
+// fakeToken answers view calls from fixed return values keyed by method name
+type fakeToken struct {
+	abi     *abi.ABI
+	returns map[string][]interface{}
+}
+
+func (f *fakeToken) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := f.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(f.returns[method.Name]...)
+}
+
+func TestTokenBalanceFormatsWithDecimals(t *testing.T) {
+	parsed, err := contract.ParseABI(testERC20ABI)
+	require.NoError(t, err)
+	require.Equal(t, toytypes.TokenStandardERC20, tokenStandard(parsed))
+
+	caller := &fakeToken{abi: parsed, returns: map[string][]interface{}{
+		"balanceOf": {big.NewInt(1_500_000)},
+		"symbol":    {"USDC"},
+		"decimals":  {uint8(6)},
+	}}
+	info := contract.DeployedContractInfo{
+		Alias:     "usdc",
+		Address:   toytypes.ContractAddress{Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"},
+		ParsedABI: parsed,
+	}
+
+	balance := tokenBalance(context.Background(), caller, info, toytypes.TokenStandardERC20, common.Address{}, big.NewInt(1))
+	require.Empty(t, balance.Error)
+	require.Equal(t, "1500000", balance.Balance)
+	require.Equal(t, "1.5", balance.Formatted)
+	require.Equal(t, "USDC", balance.Symbol)
+	require.Equal(t, uint8(6), *balance.Decimals)
+}
+
+func TestParseBlockParam(t *testing.T) {
+	block, err := parseBlockParam("latest")
+	require.NoError(t, err)
+	require.Nil(t, block)
+
+	block, err = parseBlockParam("0x10")
+	require.NoError(t, err)
+	require.Equal(t, uint64(16), *block)
+
+	_, err = parseBlockParam("-1")
+	require.Error(t, err)
+}
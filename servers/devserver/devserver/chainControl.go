@@ -0,0 +1,126 @@
+package devserver
+
+import (
+	"errors"
+	"eth-toy-client/core/devchain"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+)
+
+func handleChainCapabilities(chain devchain.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		caps := chain.Capabilities()
+		httpapi.WriteOK(w, &caps)
+	}
+}
+
+func handleChainSnapshot(chain devchain.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := chain.Snapshot(r.Context())
+		if err != nil {
+			writeChainError(w, "snapshot", err)
+			return
+		}
+		head, err := chain.Head(r.Context())
+		if err != nil {
+			writeChainError(w, "snapshot", err)
+			return
+		}
+		logger.InfoContext(r.Context(), "📸 Chain snapshot taken", "id", id, "block", head.Number.Uint64())
+		httpapi.WriteOK(w, &toytypes.ChainSnapshotResponse{ID: id, Block: head.Number.Uint64()})
+	}
+}
+
+func handleChainRevert(chain devchain.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.ChainRevertRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if err := chain.Revert(r.Context(), req.ID); err != nil {
+			writeChainError(w, "revert", err)
+			return
+		}
+		logger.InfoContext(r.Context(), "⏪ Chain reverted to snapshot", "id", req.ID)
+		writeChainHead(w, r, chain)
+	}
+}
+
+func handleChainMine(chain devchain.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.ChainMineRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.Blocks == 0 {
+			req.Blocks = 1
+		}
+		if err := chain.Mine(r.Context(), req.Blocks); err != nil {
+			writeChainError(w, "mine", err)
+			return
+		}
+		logger.InfoContext(r.Context(), "⛏️ Mined blocks", "blocks", req.Blocks)
+		writeChainHead(w, r, chain)
+	}
+}
+
+func handleChainNextTimestamp(chain devchain.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.ChainTimeRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.Timestamp == 0 {
+			fieldError(w, "timestamp", "is required")
+			return
+		}
+		if err := chain.SetNextBlockTimestamp(r.Context(), req.Timestamp); err != nil {
+			writeChainError(w, "set next block timestamp", err)
+			return
+		}
+		logger.InfoContext(r.Context(), "🕰️ Next block timestamp set", "timestamp", req.Timestamp)
+		writeChainHead(w, r, chain)
+	}
+}
+
+func handleChainIncreaseTime(chain devchain.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.ChainTimeRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		if req.Seconds == 0 {
+			fieldError(w, "seconds", "is required")
+			return
+		}
+		if err := chain.IncreaseTime(r.Context(), req.Seconds); err != nil {
+			writeChainError(w, "increase time", err)
+			return
+		}
+		logger.InfoContext(r.Context(), "⏩ Chain time advanced", "seconds", req.Seconds)
+		writeChainHead(w, r, chain)
+	}
+}
+
+func writeChainHead(w http.ResponseWriter, r *http.Request, chain devchain.Controller) {
+	head, err := chain.Head(r.Context())
+	if err != nil {
+		httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+		return
+	}
+	httpapi.WriteOK(w, &toytypes.ChainHeadResponse{
+		Block:     head.Number.Uint64(),
+		Hash:      head.Hash().Hex(),
+		Timestamp: head.Time,
+	})
+}
+
+func writeChainError(w http.ResponseWriter, action string, err error) {
+	if errors.Is(err, devchain.ErrUnsupported) {
+		httpapi.Fail(w, httpapi.ErrNotImplemented, "Cannot "+action+": "+err.Error())
+		return
+	}
+	logger.Error("❌ Chain control failed", "action", action, "error", err)
+	httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+}
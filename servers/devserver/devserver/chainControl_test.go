@@ -0,0 +1,134 @@
+package devserver
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"eth-toy-client/core/devchain"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain records the controls it receives; time travel is unsupported, like on a geth --dev node
+type fakeChain struct {
+	head     types.Header
+	mined    uint64
+	revertID string
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{head: types.Header{Number: big.NewInt(10), Time: 1_700_000_000}}
+}
+
+func (c *fakeChain) Capabilities() toytypes.ChainCapabilities {
+	return toytypes.ChainCapabilities{Backend: "geth", Snapshot: true, Revert: true, Mine: true}
+}
+
+func (c *fakeChain) Head(ctx context.Context) (*types.Header, error) {
+	head := c.head
+	return &head, nil
+}
+
+func (c *fakeChain) Snapshot(ctx context.Context) (string, error) {
+	return fmt.Sprintf("0x%x", c.head.Number), nil
+}
+
+func (c *fakeChain) Revert(ctx context.Context, id string) error {
+	c.revertID = id
+	return nil
+}
+
+func (c *fakeChain) Mine(ctx context.Context, blocks uint64) error {
+	c.mined += blocks
+	c.head.Number = new(big.Int).Add(c.head.Number, new(big.Int).SetUint64(blocks))
+	return nil
+}
+
+func (c *fakeChain) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) error {
+	return fmt.Errorf("next block timestamp: %w", devchain.ErrUnsupported)
+}
+
+func (c *fakeChain) IncreaseTime(ctx context.Context, seconds uint64) error {
+	return fmt.Errorf("increase time: %w", devchain.ErrUnsupported)
+}
+
+func TestChainMineDecodesBlocks(t *testing.T) {
+	chain := newFakeChain()
+
+	code, resp := postJSON[toytypes.ChainHeadResponse](t, handleChainMine(chain), struct{}{})
+	require.Equal(t, http.StatusOK, code)
+	require.EqualValues(t, 1, chain.mined, "blocks defaults to 1")
+	require.EqualValues(t, 11, resp.Data.Block)
+
+	code, resp = postJSON[toytypes.ChainHeadResponse](t, handleChainMine(chain), toytypes.ChainMineRequest{Blocks: 3})
+	require.Equal(t, http.StatusOK, code)
+	require.EqualValues(t, 4, chain.mined)
+	require.EqualValues(t, 14, resp.Data.Block)
+
+	code, _ = postJSON[toytypes.ChainHeadResponse](t, handleChainMine(chain), map[string]any{"blocks": "many"})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestChainRevertRequiresID(t *testing.T) {
+	chain := newFakeChain()
+
+	code, _ := postJSON[toytypes.ChainHeadResponse](t, handleChainRevert(chain), toytypes.ChainRevertRequest{})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Empty(t, chain.revertID)
+
+	code, _ = postJSON[toytypes.ChainHeadResponse](t, handleChainRevert(chain), toytypes.ChainRevertRequest{ID: "0xa"})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "0xa", chain.revertID)
+}
+
+func TestChainTimeTravelValidatesBeforeCallingTheChain(t *testing.T) {
+	chain := newFakeChain()
+
+	code, resp := postJSON[toytypes.ChainHeadResponse](t, handleChainIncreaseTime(chain), toytypes.ChainTimeRequest{})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "InvalidParameter", resp.Error.Code)
+
+	code, resp = postJSON[toytypes.ChainHeadResponse](t, handleChainNextTimestamp(chain), toytypes.ChainTimeRequest{Seconds: 60})
+	require.Equal(t, http.StatusBadRequest, code, "seconds is not a timestamp")
+	require.Equal(t, "InvalidParameter", resp.Error.Code)
+}
+
+func TestChainUnsupportedControlIsNotImplemented(t *testing.T) {
+	chain := newFakeChain()
+
+	code, resp := postJSON[toytypes.ChainHeadResponse](t, handleChainIncreaseTime(chain), toytypes.ChainTimeRequest{Seconds: 60})
+	require.Equal(t, http.StatusNotImplemented, code)
+	require.Equal(t, "NotImplemented", resp.Error.Code)
+	require.Contains(t, resp.Error.Message, "Cannot increase time")
+
+	code, resp = postJSON[toytypes.ChainHeadResponse](t, handleChainNextTimestamp(chain), toytypes.ChainTimeRequest{Timestamp: 1_800_000_000})
+	require.Equal(t, http.StatusNotImplemented, code)
+	require.Equal(t, "NotImplemented", resp.Error.Code)
+}
+
+func TestChainControlsOnSimulatedBackend(t *testing.T) {
+	backend := simulated.NewBackend(types.GenesisAlloc{})
+	defer backend.Close()
+	chain := devchain.NewSimulatedController(backend)
+
+	code, snapshot := postJSON[toytypes.ChainSnapshotResponse](t, handleChainSnapshot(chain), struct{}{})
+	require.Equal(t, http.StatusOK, code)
+
+	code, mined := postJSON[toytypes.ChainHeadResponse](t, handleChainMine(chain), toytypes.ChainMineRequest{Blocks: 3})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, snapshot.Data.Block+3, mined.Data.Block)
+
+	code, later := postJSON[toytypes.ChainHeadResponse](t, handleChainIncreaseTime(chain), toytypes.ChainTimeRequest{Seconds: 3600})
+	require.Equal(t, http.StatusOK, code, "the simulated backend travels in time")
+	require.Equal(t, mined.Data.Timestamp+3600, later.Data.Timestamp)
+
+	code, reverted := postJSON[toytypes.ChainHeadResponse](t, handleChainRevert(chain), toytypes.ChainRevertRequest{ID: snapshot.Data.ID})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, snapshot.Data.Block, reverted.Data.Block)
+	require.Equal(t, snapshot.Data.ID, reverted.Data.Hash)
+}
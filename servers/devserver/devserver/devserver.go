@@ -3,85 +3,97 @@ package devserver
 import (
 	"bytes"
 	"context"
-	"crypto/ecdsa"
 	"encoding/hex"
+	"eth-toy-client/accounts"
+	"eth-toy-client/config"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/units"
 	"eth-toy-client/servers/servers"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
-	"log"
 	"math/big"
-	"strings"
 )
 
-type TestAccount struct {
-	Address common.Address
-	Name    string
-	PrivKey *ecdsa.PrivateKey
+var logger = logutil.For("devserver")
+
+// TestAccount is the canonical mnemonic-derived account from the accounts package
+type TestAccount = accounts.TestAccount
+
+// DefaultFunding is what every test account receives from the dev account, 1 ETH unless configured
+var DefaultFunding = big.NewInt(1_000_000_000_000_000_000)
+
+// GasPolicy is the fees and gas limit of the txs DevServer builds
+type GasPolicy struct {
+	TipCap *big.Int
+	FeeCap *big.Int
+	Limit  uint64
 }
 
-var rawAccounts = map[string]string{
-	"alice":   "0x4f3edf983ac636a65a842ce7c78d9aa706d3b113b37b1e1a7e3975d3fef1fdc3",
-	"bob":     "0x6c8750b42f08aabb2645f65f38d013ce59b2a2b3d2a2b9c54b541714aa30353b",
-	"charlie": "0x5c7d2381d08d6579e9af64a2b7d00e33a1f519896f17f0bb3e2ff894f9b8c67f",
-	"diana":   "0x646f1ce2b44b655e5d34f4313ffb4c7b06d93b219a22993b7824b8b36aa5488e",
-	"eric":    "0xadd53f9a7e588d21953d7cc5c5e1e89f9dfd4e0f8ee0b0b98f71f5b38d9e3a3e",
-	"frank":   "0x395df67f0c2f20f3c3f4817c88c7b4c2dbd0493a34f52fd0e9f6f0017cba94d7",
-	"grace":   "0xe485d098507f9e4cdbf3d0107c4b74757b6a12a7b1300a23a1f2fffae6d1c0a7",
-	"helen":   "0xa453611d9419d0e3fdae7d16b1b5c470fe15f0ff9891753c603a00f226b84aa1",
-	"ivan":    "0x69ee0de5eae9c6f3b674e41c2c425b6f1d50dfe2e6ec8ab3403d0fd9e3f84b07",
-	"judy":    "0xdbda1821b80551c9e1c03fa0c27f14f85f3bdd8e991b52e3b0a38a42f4c1d2a3",
+// TxGas is the gas policy in effect: 1 wei tip, 1 gwei fee cap and 3M gas unless configured
+var TxGas = GasPolicy{TipCap: big.NewInt(1), FeeCap: big.NewInt(1_000_000_000), Limit: 3_000_000}
+
+// Configure applies the funding amount and gas policy of a validated config
+func Configure(cfg *config.Config) {
+	DefaultFunding = units.MustParseAmount(cfg.Funding.Amount)
+	TxGas = GasPolicy{
+		TipCap: units.MustParseAmount(cfg.Gas.TipCap),
+		FeeCap: units.MustParseAmount(cfg.Gas.FeeCap),
+		Limit:  cfg.Gas.Limit,
+	}
 }
 
-func LoadTestAccounts() *map[string]*TestAccount {
-	var testAccounts = make(map[string]*TestAccount)
-	for name, hexKey := range rawAccounts {
-		privKey, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
-		if err != nil {
-			log.Fatalf("❌ Invalid private key for %s: %v", name, err)
-		}
-		addr := crypto.PubkeyToAddress(privKey.PublicKey)
-		testAccounts[name] = &TestAccount{
-			Name:    name,
-			Address: addr,
-			PrivKey: privKey,
-		}
+// LoadTestAccounts opens the configured test accounts
+func LoadTestAccounts(cfg accounts.Config) *AccountStore {
+	list, err := accounts.Open(cfg)
+	if err != nil {
+		logutil.Fatal(logger, "❌ Failed to derive test accounts", "error", err)
 	}
 
-	log.Printf("🧾 Loaded %d test accounts:", len(testAccounts))
-	for name, acc := range testAccounts {
-		log.Printf("  %s => %s", name, acc.Address.Hex())
+	logger.Info("🧾 Loaded test accounts", "count", len(list), "signer", cfg.SignerMode)
+	for _, acc := range list {
+		logger.Debug("🧾 Test account", "alias", acc.Name, "address", acc.Address.Hex(), "path", acc.Path)
 	}
-	return &testAccounts
+	return NewAccountStore(cfg, list)
 }
 
+// FundTestAccounts sends DefaultFunding to every account; a failed transfer is logged and skipped
 func FundTestAccounts(
 	devAccount common.Address,
 	rpcClient *rpc.Client,
-	testAccounts *map[string]*TestAccount) *map[string]*TestAccount {
+	testAccounts *AccountStore) *AccountStore {
 	ctx := context.Background()
 
-	for name, acc := range *testAccounts {
-		// Build a call to eth_sendTransaction with from, to, value (in hex)
-		var txHash string
-		err := rpcClient.CallContext(ctx, &txHash, "eth_sendTransaction", map[string]interface{}{
-			"from":  devAccount.Hex(),
-			"to":    acc.Address.Hex(),
-			"value": "0xde0b6b3a7640000", // 1 ETH = 10^18 = 0xde0b6b3a7640000
-		})
+	for _, acc := range testAccounts.List() {
+		txHash, err := FundAccount(ctx, rpcClient, devAccount, acc.Address, DefaultFunding)
 		if err != nil {
-			log.Fatalf("❌ eth_sendTransaction failed for %s: %v", name, err)
+			logger.Error("❌ Failed to fund account", "alias", acc.Name, "error", err)
+			continue
 		}
-
-		log.Printf("📤 Funded %s (%s) with 1 ETH (tx: %s)", name, acc.Address.Hex(), txHash)
+		logger.Info("📤 Funded account", "alias", acc.Name, "address", acc.Address.Hex(), "amount", units.Format(DefaultFunding, units.Ether), "tx", txHash)
 	}
 	return testAccounts
 }
 
+// FundAccount transfers wei from the unlocked dev account via eth_sendTransaction
+func FundAccount(ctx context.Context, rpcClient *rpc.Client, devAccount common.Address, to common.Address, wei *big.Int) (common.Hash, error) {
+	var txHash common.Hash
+	err := rpcClient.CallContext(ctx, &txHash, "eth_sendTransaction", map[string]interface{}{
+		"from":  devAccount.Hex(),
+		"to":    to.Hex(),
+		"value": (*hexutil.Big)(wei),
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendTransaction failed: %w", err)
+	}
+	return txHash, nil
+}
+
 type SignTxResponse struct {
 	Tx string `json:"tx"` // signed RLP hex
 }
@@ -90,21 +102,20 @@ type SendTxResponse struct {
 	TxHash string `json:"txHash"`
 }
 
+// BuildAndSignTx signs a tx at the sender's pending nonce; callers that send it hold the sender's
+// nonceLocks entry until it is sent
 func BuildAndSignTx(
-	privKey *ecdsa.PrivateKey,
+	signer accounts.Signer,
 	from common.Address,
 	to *common.Address, // ✅ nil means contract deployment
 	value *big.Int,
-	rpcPort string,
+	client *ethclient.Client,
 	data []byte, // ✅ Optional data (contract bytecode or calldata)
 ) (*types.Transaction, *types.Transaction, error) {
-	client, err := ethclient.Dial("http://localhost:" + rpcPort)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to connect to dev node: %w", err)
-	}
-	defer client.Close()
-
 	ctx := context.Background()
+	if value == nil {
+		value = new(big.Int)
+	}
 
 	nonce, err := client.PendingNonceAt(ctx, from)
 	if err != nil {
@@ -117,21 +128,20 @@ func BuildAndSignTx(
 	}
 
 	address := crypto.CreateAddress(from, nonce)
-	log.Printf(
-		"Expected address: %s\n", address)
+	logger.Debug("Expected contract address", "address", address.Hex())
 
 	tx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:   chainID,
 		Nonce:     nonce,
-		GasTipCap: big.NewInt(1),
-		GasFeeCap: big.NewInt(1_000_000_000), // 1 gwei
-		Gas:       3_000_000,                 // ⛽ for deployment or interaction
+		GasTipCap: TxGas.TipCap,
+		GasFeeCap: TxGas.FeeCap,
+		Gas:       TxGas.Limit, // ⛽ for deployment or interaction
 		To:        to,
-		Value:     big.NewInt(0),
+		Value:     value,
 		Data:      data, // 🧠 smart contract bytecode or calldata
 	})
 
-	signedTx, err := SignTx(chainID, tx, privKey)
+	signedTx, err := SignTx(chainID, tx, signer)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to sign tx: %w", err)
 	}
@@ -140,18 +150,12 @@ func BuildAndSignTx(
 }
 
 func SignContract(
-	privKey *ecdsa.PrivateKey,
+	signer accounts.Signer,
 	from common.Address,
 	nonce *uint64,
-	rpcPort string,
+	client *ethclient.Client,
 	data []byte, // ✅ Optional data (contract bytecode or calldata)
 ) (*types.Transaction, *common.Address, *types.Transaction, error) {
-	client, err := ethclient.Dial("http://localhost:" + rpcPort)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to connect to dev node: %w", err)
-	}
-	defer client.Close()
-
 	ctx := context.Background()
 
 	chainID, err := client.ChainID(ctx)
@@ -167,44 +171,34 @@ func SignContract(
 	tx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:   chainID,
 		Nonce:     *nonce,
-		GasTipCap: big.NewInt(1),
-		GasFeeCap: big.NewInt(1_000_000_000), // 1 gwei
-		Gas:       3_000_000,                 // ⛽ for deployment or interaction
+		GasTipCap: TxGas.TipCap,
+		GasFeeCap: TxGas.FeeCap,
+		Gas:       TxGas.Limit, // ⛽ for deployment or interaction
 		To:        nil,
 		Value:     big.NewInt(0),
 		Data:      data, // 🧠 smart contract bytecode or calldata
 	})
 
-	signedTx, err := SignTx(chainID, tx, privKey)
+	signedTx, err := SignTx(chainID, tx, signer)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to sign tx: %w", err)
 	}
 
 	address := crypto.CreateAddress(from, *nonce)
-	log.Printf(
-		"Expected address: %s\n", address)
+	logger.Debug("Expected contract address", "address", address.Hex())
 	return tx, &address, signedTx, nil
 }
 
-func SignTx(chainID servers.ChainId, rawTx *types.Transaction, key *ecdsa.PrivateKey) (*types.Transaction, error) {
-	singer := types.NewPragueSigner(chainID)
-	digest := singer.Hash(rawTx).Bytes()
-	fmt.Printf("🦄 digestLength: %x\n", len(digest))
-	signature, err := crypto.Sign(digest, key)
-	if err != nil {
-		return nil, err
-	}
-	return rawTx.WithSignature(
-		singer,
-		signature)
-
+// SignTx signs through the account's signer, which may be an in-memory key, a keystore or an external process
+func SignTx(chainID servers.ChainId, rawTx *types.Transaction, signer accounts.Signer) (*types.Transaction, error) {
+	return signer.SignTx(rawTx, chainID)
 }
 
 // RlpEncodeBytes returns raw RLP-encoded tx bytes
 func RlpEncodeBytes(tx *types.Transaction) []byte {
 	var buf bytes.Buffer
 	if err := rlp.Encode(&buf, tx); err != nil {
-		log.Fatalf("❌ Failed to RLP-encode tx: %v", err)
+		logutil.Fatal(logger, "❌ Failed to RLP-encode tx", "error", err)
 	}
 	return buf.Bytes()
 }
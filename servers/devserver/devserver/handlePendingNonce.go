@@ -2,42 +2,38 @@ package devserver
 
 import (
 	"context"
-	"encoding/json"
 	"eth-toy-client/core/httpapi"
 	toytypes "eth-toy-client/core/types"
 	"eth-toy-client/servers/servers"
 	"fmt"
 	"github.com/ethereum/go-ethereum/crypto"
-	"log"
 	"net/http"
 )
 
-func handlePendingNonce(nodeClient *servers.NodeClient, accounts *map[string]*TestAccount) func(w http.ResponseWriter, r *http.Request) {
+func handlePendingNonce(nodeClient *servers.NodeClient, accounts *AccountStore) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			log.Printf("⚠️ Invalid method: %s", r.Method)
-			httpapi.WriteError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "Only POST is allowed")
+			logger.WarnContext(r.Context(), "⚠️ Invalid method", "method", r.Method)
+			httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST is allowed")
 			return
 		}
 
 		var req toytypes.PendingNonceRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			log.Printf("❌ Failed to decode JSON: %v", err)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidRequest", "Invalid JSON payload")
+		if !decodeRequest(w, r, &req) {
 			return
 		}
 
-		from, ok := (*accounts)[req.Alias]
+		from, ok := accounts.Get(req.Alias)
 		if !ok {
-			log.Printf("⚠️ Sender not found: %s", req.Alias)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidAccount", fmt.Sprintf("Sender '%s' not found", req.Alias))
+			logger.WarnContext(r.Context(), "⚠️ Sender not found", "alias", req.Alias)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender '%s' not found", req.Alias))
 			return
 		}
 
 		nonce, err := nodeClient.Client.PendingNonceAt(context.Background(), from.Address)
 		if err != nil {
-			log.Printf("❌ Failed request pending Nonce: %v", err)
-			httpapi.WriteError(w, http.StatusInternalServerError, "PendingNonceAt", err.Error())
+			logger.ErrorContext(r.Context(), "❌ Failed to get pending nonce", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
 			return
 		}
 
@@ -47,7 +43,7 @@ func handlePendingNonce(nodeClient *servers.NodeClient, accounts *map[string]*Te
 			Nonce:   &nonce,
 			Address: address.Hex(),
 		}
-		log.Printf("Sending nonce: %v", response)
+		logger.DebugContext(r.Context(), "Sending pending nonce", "alias", req.Alias, "nonce", response.Nonce)
 		httpapi.WriteOK[toytypes.PendingNonceResponse](w, &response)
 	}
 }
@@ -0,0 +1,152 @@
+package devserver
+
+import (
+	"context"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/revert"
+	"eth-toy-client/core/tracing"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/servers"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"net/http"
+	"time"
+)
+
+const receiptPollAttempts = 60
+
+func handleTxReceipt(nodeClient *servers.NodeClient, reg *contract.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		txHash, ok := parseTxHash(w, r)
+		if !ok {
+			return
+		}
+
+		ctx := r.Context()
+		receipt, err := nodeClient.Client.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			if err == ethereum.NotFound {
+				httpapi.Fail(w, httpapi.ErrNotFound, fmt.Sprintf("Receipt for '%s' not found", txHash.Hex()))
+				return
+			}
+			logger.ErrorContext(ctx, "❌ Failed to fetch receipt", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		httpapi.WriteOK(w, buildReceiptResponse(ctx, nodeClient.Client, reg, receipt))
+	}
+}
+
+func handleTxTrace(tracer *tracing.Tracer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		txHash, ok := parseTxHash(w, r)
+		if !ok {
+			return
+		}
+
+		trace, err := tracer.TraceTransaction(r.Context(), txHash)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Failed to trace tx", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		logger.InfoContext(r.Context(), "🧬 Traced tx", "internalTxs", trace.InternalTransactions)
+		httpapi.WriteOK(w, trace)
+	}
+}
+
+func handleTxStateDiff(tracer *tracing.Tracer, accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		txHash, ok := parseTxHash(w, r)
+		if !ok {
+			return
+		}
+
+		// 🔑 Test accounts double as mapping keys, so balances[alice] gets a label
+		mappingKeys := make([]common.Address, 0, accounts.Len())
+		for _, acc := range accounts.List() {
+			mappingKeys = append(mappingKeys, acc.Address)
+		}
+
+		diff, err := tracer.StateDiff(r.Context(), txHash, mappingKeys...)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Failed to diff state of tx", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		logger.InfoContext(r.Context(), "🧮 Diffed state of tx", "accountsChanged", len(diff.Accounts))
+		httpapi.WriteOK(w, diff)
+	}
+}
+
+// parseTxHash reads the {hash} path segment, writing the error response itself when it is invalid
+func parseTxHash(w http.ResponseWriter, r *http.Request) (common.Hash, bool) {
+	raw := r.PathValue("hash")
+	if raw == "" {
+		httpapi.Fail(w, httpapi.ErrInvalidRequest, "Transaction hash is required in the path")
+		return common.Hash{}, false
+	}
+	if len(common.FromHex(raw)) != common.HashLength {
+		httpapi.Fail(w, httpapi.ErrInvalidParameter, fmt.Sprintf("'%s' is not a transaction hash", raw))
+		return common.Hash{}, false
+	}
+	logutil.Annotate(r.Context(), "tx", raw)
+	return common.HexToHash(raw), true
+}
+
+// buildReceiptResponse converts a receipt and, for failed txs, replays it to decode the revert reason
+func buildReceiptResponse(ctx context.Context, client *ethclient.Client, reg *contract.Registry, receipt *types.Receipt) *toytypes.TxReceiptResponse {
+	resp := &toytypes.TxReceiptResponse{
+		TxHash:      receipt.TxHash.Hex(),
+		Status:      receipt.Status,
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		GasUsed:     receipt.GasUsed,
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		resp.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return resp
+	}
+
+	var abis []*abi.ABI
+	if tx, _, err := client.TransactionByHash(ctx, receipt.TxHash); err == nil && tx.To() != nil {
+		abis = reg.ABIsFor(*tx.To())
+	} else {
+		abis = reg.ABIsFor(common.Address{})
+	}
+
+	reason, err := revert.Explain(ctx, client, receipt, abis...)
+	if err != nil {
+		logger.WarnContext(ctx, "⚠️ Could not explain failed tx", "tx", receipt.TxHash.Hex(), "error", err)
+		return resp
+	}
+	logger.InfoContext(ctx, "💥 Tx reverted", "tx", receipt.TxHash.Hex(), "reason", revert.String(reason))
+	resp.Revert = reason
+	return resp
+}
+
+// waitForReceipt polls the node until the tx is mined or the attempts run out
+func waitForReceipt(ctx context.Context, client *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
+	for i := 0; i < receiptPollAttempts; i++ {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil && receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+	return nil, fmt.Errorf("⏱️ timeout waiting for tx %s", txHash.Hex())
+}
@@ -10,84 +10,97 @@ import (
 	"eth-toy-client/core/httpapi"
 	"eth-toy-client/core/logutil"
 	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
 	"eth-toy-client/servers/servers"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"log"
 	"math/big"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
 
 func handleDevAccounts(devAccount common.Address) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		resp := struct {
-			Address string `json:"address"`
-		}{
+		httpapi.WriteOK(w, &toytypes.DevAccountResponse{
 			Address: devAccount.Hex(),
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		})
 	}
 }
 
-func handleAccounts(accounts *map[string]*TestAccount) func(w http.ResponseWriter, r *http.Request) {
+func handleAccounts(accounts *AccountStore) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var list []accountResponse
-		for name, acc := range *accounts {
-			privKeyBytes := crypto.FromECDSA(acc.PrivKey) // import "github.com/ethereum/go-ethereum/crypto"
-			list = append(list, accountResponse{
-				Name:       name,
-				Address:    acc.Address.Hex(),
-				PrivateKey: hex.EncodeToString(privKeyBytes),
-			})
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(list)
+		list := []toytypes.TestAccountResponse{}
+		for _, acc := range accounts.List() {
+			entry := toytypes.TestAccountResponse{
+				Name:    acc.Name,
+				Address: acc.Address.Hex(),
+			}
+			// 🔒 Keystore and external accounts have no key to give, and exposure can be switched off
+			if key, ok := acc.PrivateKey(); ok && accounts.ExposeKeys() {
+				entry.PrivateKey = hex.EncodeToString(crypto.FromECDSA(key))
+			}
+			list = append(list, entry)
+		}
+		httpapi.WriteOK(w, &list)
 	}
 }
 
-func handleInfo(nodeClient *servers.NodeClient, accounts *map[string]*TestAccount) func(w http.ResponseWriter, r *http.Request) {
+func handleInfo(nodeClient *servers.NodeClient, accounts *AccountStore) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		resp := struct {
-			RPCURL        string `json:"rpcUrl"`
-			RPCPort       string `json:"rpcPort"`
-			AccountsCount int    `json:"accountsCount"`
-		}{
-			RPCURL:        "http://localhost:" + nodeClient.Config.Port,
-			RPCPort:       nodeClient.Config.Port,
-			AccountsCount: len(*accounts),
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		httpapi.WriteOK(w, &toytypes.DevInfoResponse{
+			RPCURL:        nodeClient.Config.HTTPURL,
+			RPCPort:       rpcPort(nodeClient.Config.HTTPURL),
+			AccountsCount: accounts.Len(),
+		})
+	}
+}
+
+// rpcPort is the port of a node URL, or the scheme's default port when the URL has none
+func rpcPort(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	if port := parsed.Port(); port != "" {
+		return port
+	}
+	if parsed.Scheme == "https" {
+		return "443"
 	}
+	return "80"
 }
 
-func signTxHandler(nodeClient *servers.NodeClient, accounts *map[string]*TestAccount) http.HandlerFunc {
+func signTxHandler(nodeClient *servers.NodeClient, accounts *AccountStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req toytypes.SignTxRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		if !decodeRequest(w, r, &req) {
 			return
 		}
 
-		from, ok := (*accounts)[req.From]
+		from, ok := accounts.Get(req.From)
 		if !ok {
-			http.Error(w, "Unknown sender account", http.StatusBadRequest)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender '%s' not found", req.From))
 			return
 		}
 
-		toAddr := common.HexToAddress(req.To)
+		var toAddr common.Address
+		if to, ok := accounts.Get(req.To); ok {
+			toAddr = to.Address
+		} else if common.IsHexAddress(req.To) {
+			toAddr = common.HexToAddress(req.To)
+		} else {
+			fieldError(w, "to", fmt.Sprintf("'%s' is neither a test account nor an address", req.To))
+			return
+		}
 		value := big.NewInt(consts.DefaultTransferAmount) // default
 		if req.Value != "" {
-			v, ok := new(big.Int).SetString(req.Value, 10)
-			if !ok {
-				http.Error(w, "Invalid value field", http.StatusBadRequest)
+			v, err := units.ParseAmount(req.Value)
+			if err != nil {
+				fieldError(w, "value", err.Error())
 				return
 			}
 			value = v
@@ -99,9 +112,10 @@ func signTxHandler(nodeClient *servers.NodeClient, accounts *map[string]*TestAcc
 		if req.Nonce != nil {
 			nonce = *req.Nonce
 		} else {
+			defer nonceLocks.lock(from.Address)() // a running batch may have planned the pending nonce
 			nonce, err = nodeClient.Client.PendingNonceAt(ctx, from.Address)
 			if err != nil {
-				http.Error(w, "Failed to get nonce", http.StatusInternalServerError)
+				httpapi.Fail(w, httpapi.ErrNodeError, fmt.Sprintf("Failed to get nonce: %v", err))
 				return
 			}
 		}
@@ -112,7 +126,7 @@ func signTxHandler(nodeClient *servers.NodeClient, accounts *map[string]*TestAcc
 		} else {
 			chainID, err = nodeClient.Client.ChainID(ctx)
 			if err != nil {
-				http.Error(w, "Failed to get chain ID", http.StatusInternalServerError)
+				httpapi.Fail(w, httpapi.ErrNodeError, fmt.Sprintf("Failed to get chain ID: %v", err))
 				return
 			}
 		}
@@ -121,62 +135,67 @@ func signTxHandler(nodeClient *servers.NodeClient, accounts *map[string]*TestAcc
 		tx := types.NewTx(&types.DynamicFeeTx{
 			ChainID:   chainID,
 			Nonce:     nonce,
-			GasTipCap: big.NewInt(1),
-			GasFeeCap: big.NewInt(1e9),
-			Gas:       1_500_000,
+			GasTipCap: TxGas.TipCap,
+			GasFeeCap: TxGas.FeeCap,
+			Gas:       TxGas.Limit,
 			To:        &toAddr,
 			Value:     value,
 		})
 
 		// ✍️ Sign it
-		signer := types.NewLondonSigner(chainID)
-		signedTx, err := types.SignTx(tx, signer, from.PrivKey)
+		signedTx, err := from.Signer.SignTx(tx, chainID)
 		if err != nil {
-			http.Error(w, "Failed to sign tx", http.StatusInternalServerError)
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
 			return
 		}
 
 		var buff bytes.Buffer
 		signedTx.EncodeRLP(&buff)
 
-		resp := SignTxResponse{
+		httpapi.WriteOK(w, &SignTxResponse{
 			Tx: "0x" + hex.EncodeToString(buff.Bytes()),
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+		})
 	}
 
 }
 
-func handleSendTx(nodeClient *servers.NodeClient, accounts *map[string]*TestAccount) http.HandlerFunc {
+func handleSendTx(nodeClient *servers.NodeClient, accounts *AccountStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req toytypes.SignTxRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		if !decodeRequest(w, r, &req) {
 			return
 		}
 
-		fromAcc, ok := (*accounts)[req.From]
+		fromAcc, ok := accounts.Get(req.From)
 		if !ok {
-			http.Error(w, "From account not found", http.StatusNotFound)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender '%s' not found", req.From))
 			return
 		}
 
-		toAcc, ok := (*accounts)[req.To]
+		toAcc, ok := accounts.Get(req.To)
 		if !ok {
-			http.Error(w, "To account not found", http.StatusNotFound)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Recipient '%s' not found", req.To))
 			return
 		}
 
 		ctx := context.Background()
-		chainID, _ := nodeClient.Client.ChainID(ctx)
-		nonce, _ := nodeClient.Client.PendingNonceAt(ctx, fromAcc.Address)
+		chainID, err := nodeClient.Client.ChainID(ctx)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrNodeError, fmt.Sprintf("Failed to get chain ID: %v", err))
+			return
+		}
+		defer nonceLocks.lock(fromAcc.Address)() // a running batch may have planned the pending nonce
+		nonce, err := nodeClient.Client.PendingNonceAt(ctx, fromAcc.Address)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrNodeError, fmt.Sprintf("Failed to get nonce: %v", err))
+			return
+		}
 
 		value := big.NewInt(consts.DefaultTransferAmount) // default
 		if req.Value != "" {
-			v, ok := new(big.Int).SetString(req.Value, 10)
-			if !ok {
-				http.Error(w, "Invalid value field", http.StatusBadRequest)
+			v, err := units.ParseAmount(req.Value)
+			if err != nil {
+				fieldError(w, "value", err.Error())
 				return
 			}
 			value = v
@@ -186,78 +205,80 @@ func handleSendTx(nodeClient *servers.NodeClient, accounts *map[string]*TestAcco
 		tx := types.NewTx(&types.DynamicFeeTx{
 			ChainID:   chainID,
 			Nonce:     nonce,
-			GasTipCap: big.NewInt(1),
-			GasFeeCap: big.NewInt(1e9),
-			Gas:       1_500_000,
+			GasTipCap: TxGas.TipCap,
+			GasFeeCap: TxGas.FeeCap,
+			Gas:       TxGas.Limit,
 			To:        &toAcc.Address,
 			Value:     value,
 		})
 
-		signer := types.NewLondonSigner(chainID)
-		signedTx, _ := types.SignTx(tx, signer, fromAcc.PrivKey)
+		signedTx, err := fromAcc.Signer.SignTx(tx, chainID)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
+			return
+		}
 
-		err := nodeClient.Client.SendTransaction(ctx, signedTx)
+		err = sendTransaction(ctx, nodeClient.Client, signedTx, toytypes.TxKindSend)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to send tx: %v", err), http.StatusInternalServerError)
+			httpapi.Fail(w, httpapi.ErrTxRejected, err.Error())
 			return
 		}
 
-		json.NewEncoder(w).Encode(SendTxResponse{TxHash: signedTx.Hash().Hex()})
+		httpapi.WriteOK(w, &SendTxResponse{TxHash: signedTx.Hash().Hex()})
 	}
 }
 
-func handleSignTx(nodeClient *servers.NodeClient, accounts *map[string]*TestAccount) http.HandlerFunc {
+func handleSignTx(nodeClient *servers.NodeClient, accounts *AccountStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			log.Printf("⚠️ Invalid method: %s", r.Method)
-			httpapi.WriteError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "Only POST is allowed")
+			logger.WarnContext(r.Context(), "⚠️ Invalid method", "method", r.Method)
+			httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST is allowed")
 			return
 		}
 
 		var req toytypes.SignTxRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			log.Printf("❌ Failed to decode JSON: %v", err)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidRequest", "Invalid JSON payload")
+		if !decodeRequest(w, r, &req) {
 			return
 		}
 
-		log.Printf("📨 /sign-tx: from=%s → to=%s | value=%s", req.From, req.To, req.Value)
+		logger.InfoContext(r.Context(), "📨 Signing tx", "from", req.From, "to", req.To, "value", req.Value)
 
-		from, ok := (*accounts)[req.From]
+		from, ok := accounts.Get(req.From)
 		if !ok {
-			log.Printf("⚠️ Sender not found: %s", req.From)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidAccount", fmt.Sprintf("Sender '%s' not found", req.From))
+			logger.WarnContext(r.Context(), "⚠️ Sender not found", "alias", req.From)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender '%s' not found", req.From))
 			return
 		}
 
-		to, ok := (*accounts)[req.To]
+		to, ok := accounts.Get(req.To)
 		if !ok {
-			log.Printf("⚠️ Recipient not found: %s", req.To)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidAccount", fmt.Sprintf("Recipient '%s' not found", req.To))
+			logger.WarnContext(r.Context(), "⚠️ Recipient not found", "alias", req.To)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Recipient '%s' not found", req.To))
 			return
 		}
 
-		val := new(big.Int)
-		_, ok = val.SetString(req.Value, 10)
-		if !ok {
-			log.Printf("❌ Invalid value format: %s", req.Value)
-			httpapi.WriteError(w, http.StatusBadRequest, "INVALID_VALUE", "Invalid value format")
+		val, err := units.ParseAmount(req.Value)
+		if err != nil {
+			logger.WarnContext(r.Context(), "❌ Invalid value format", "value", req.Value)
+			fieldError(w, "value", err.Error())
 			return
 		}
 
-		tx, signedTx, err := BuildAndSignTx(from.PrivKey, from.Address, &to.Address, val, nodeClient.Config.Port, nil)
+		unlock := nonceLocks.lock(from.Address) // a running batch may have planned the pending nonce
+		_, signedTx, err := BuildAndSignTx(from.Signer, from.Address, &to.Address, val, nodeClient.Client, nil)
+		unlock()
 		if err != nil {
-			log.Printf("❌ Signing failed: %v", err)
-			httpapi.WriteError(w, http.StatusInternalServerError, "SigningFailed", err.Error())
+			logger.ErrorContext(r.Context(), "❌ Signing failed", "error", err)
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
 			return
 		}
 
-		log.Printf("✅ Signed TX from %s → %s | value=%s | hash=%s",
-			from.Address.Hex(), to.Address.Hex(), val.String(), tx.Hash().Hex())
+		logutil.Annotate(r.Context(), "tx", signedTx.Hash().Hex())
+		logger.InfoContext(r.Context(), "✅ Signed tx", "from", from.Address.Hex(), "to", to.Address.Hex(), "value", val.String())
 
 		resp := &toytypes.SignTxAPIResponse{
 			SignedTx: hex.EncodeToString(RlpEncodeBytes(signedTx)),
-			TxHash:   tx.Hash().Hex(),
+			TxHash:   signedTx.Hash().Hex(),
 		}
 
 		httpapi.WriteOK[toytypes.SignTxAPIResponse](w, resp)
@@ -268,13 +289,13 @@ func handleRegisterAlias(reg *contract.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var meta contract.DeployedContractMetaJSON
 		if err := json.NewDecoder(r.Body).Decode(&meta); err != nil {
-			httpapi.WriteError(w, 400, "InvalidRequest", "Could not parse JSON")
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Could not parse JSON")
 			return
 		}
 
 		// 🧪 Validate required fields
 		if meta.Alias == "" || meta.Address == "" || meta.TxHash == "" {
-			httpapi.WriteError(w, 400, "MissingFields", "Alias, address, and txHash are required")
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Alias, address, and txHash are required")
 			return
 		}
 
@@ -283,11 +304,29 @@ func handleRegisterAlias(reg *contract.Registry) http.HandlerFunc {
 			meta.Timestamp = time.Now().Unix()
 		}
 
-		logutil.Infof("📦 Registering alias: %s → %s", meta.Alias, meta.Address)
+		logutil.Annotate(r.Context(), "alias", meta.Alias)
+		logger.InfoContext(r.Context(), "📦 Registering alias", "address", meta.Address)
 
 		contractInfo := meta.ToDeployedContractInfo(false)
+		if meta.ABI != "" {
+			// 🧬 Keep the parsed ABI around so reverts can be decoded into custom errors
+			parsedABI, err := contract.ParseABI(meta.ABI)
+			if err != nil {
+				logger.WarnContext(r.Context(), "⚠️ Could not parse ABI", "error", err)
+			} else {
+				contractInfo.ParsedABI = parsedABI
+			}
+		}
+		if meta.StorageLayout != "" {
+			layout, err := contract.ParseStorageLayout(meta.StorageLayout)
+			if err != nil {
+				logger.WarnContext(r.Context(), "⚠️ Could not parse storage layout", "error", err)
+			} else {
+				contractInfo.ParsedStorageLayout = layout
+			}
+		}
 		if err := reg.Add(*contractInfo); err != nil {
-			httpapi.WriteError(w, 400, "DuplicateAlias", err.Error())
+			httpapi.Fail(w, httpapi.ErrConflict, err.Error())
 			return
 		}
 
@@ -306,6 +345,8 @@ func handleGetContracts(reg *contract.Registry) http.HandlerFunc {
 		for _, entry := range all {
 			entry.ABI = "" // Strip heavy fields
 			entry.ParsedABI = nil
+			entry.StorageLayout = ""
+			entry.ParsedStorageLayout = nil
 			summaries = append(summaries, entry)
 		}
 
@@ -317,13 +358,13 @@ func handleGetContractByAlias(reg *contract.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		address := strings.TrimPrefix(r.URL.Path, "/api/contracts/")
 		if address == "" {
-			httpapi.WriteError(w, 400, "MissingContract", "Contract address is required in the path")
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Contract address is required in the path")
 			return
 		}
 		contractAddress := toytypes.ContractAddress{Address: address}
 		meta, ok := reg.Get(contractAddress)
 		if !ok {
-			httpapi.WriteError(w, 404, "NotFound", fmt.Sprintf("Address '%s' not found", contractAddress.Address))
+			httpapi.Fail(w, httpapi.ErrNotFound, fmt.Sprintf("Address '%s' not found", contractAddress.Address))
 			return
 		}
 
@@ -0,0 +1,51 @@
+package devserver
+
+import (
+	"bytes"
+	"github.com/ethereum/go-ethereum/common"
+	"sort"
+	"sync"
+)
+
+// senderLocks serialises nonce assignment per sender, so two requests never pick the same pending
+// nonce. A batch holds the locks of all its senders until every item has been submitted.
+type senderLocks struct {
+	mu    sync.Mutex
+	locks map[common.Address]*sync.Mutex
+}
+
+var nonceLocks = &senderLocks{locks: make(map[common.Address]*sync.Mutex)}
+
+// lock locks the senders in address order, so overlapping batches cannot deadlock, and returns
+// the function that unlocks them
+func (l *senderLocks) lock(senders ...common.Address) func() {
+	unique := make(map[common.Address]bool, len(senders))
+	sorted := make([]common.Address, 0, len(senders))
+	for _, sender := range senders {
+		if !unique[sender] {
+			unique[sender] = true
+			sorted = append(sorted, sender)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+
+	held := make([]*sync.Mutex, 0, len(sorted))
+	for _, sender := range sorted {
+		l.mu.Lock()
+		m, ok := l.locks[sender]
+		if !ok {
+			m = new(sync.Mutex)
+			l.locks[sender] = m
+		}
+		l.mu.Unlock()
+		m.Lock()
+		held = append(held, m)
+	}
+	return func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			held[i].Unlock()
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package devserver
+
+import (
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/openapi"
+	"eth-toy-client/core/rpcproxy"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/servers"
+	"eth-toy-client/swagger"
+)
+
+// SpecFile is DevServer's document in the swagger package
+const SpecFile = "devserver.json"
+
+var blockParam = openapi.Param{Name: "block", Description: "decimal or 0x block number, latest by default"}
+
+// Operations documents registerRoutes
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/dev-account", Summary: "The dev node's funded account", Tag: "accounts", Response: toytypes.DevAccountResponse{}},
+		{Method: "GET", Path: "/accounts", Summary: "Test accounts, with keys while key exposure is on", Tag: "accounts", Response: []toytypes.TestAccountResponse{}},
+		{Method: "GET", Path: "/info", Summary: "Node URL and account count", Tag: "accounts", Response: toytypes.DevInfoResponse{}},
+		{Method: "GET", Path: "/api/accounts", Summary: "List test accounts", Tag: "accounts", Response: []toytypes.AccountInfo{}},
+		{Method: "POST", Path: "/api/accounts", Summary: "Create and optionally fund an account", Tag: "accounts", Request: toytypes.CreateAccountRequest{}, Response: toytypes.CreateAccountResponse{}},
+		{Method: "DELETE", Path: "/api/accounts/{alias}", Summary: "Remove an account", Tag: "accounts", Response: toytypes.AccountInfo{}},
+		{Method: "POST", Path: "/api/accounts/{alias}/fund", Summary: "Send ETH from the dev account", Tag: "accounts", Request: toytypes.FundAccountRequest{}, Response: toytypes.FundAccountResponse{}},
+		{Method: "GET", Path: "/api/accounts/{alias}/balance", Summary: "ETH balance of an alias or address", Tag: "accounts", Query: []openapi.Param{blockParam}, Response: toytypes.BalanceResponse{}},
+		{Method: "GET", Path: "/api/accounts/{alias}/tokens", Summary: "Balances of the registered tokens", Tag: "accounts", Query: []openapi.Param{blockParam}, Response: toytypes.TokenBalancesResponse{}},
+		{Method: "GET", Path: "/api/accounts/{alias}/nonce", Summary: "Latest and pending nonce", Tag: "accounts", Response: toytypes.NonceResponse{}},
+		{Method: "POST", Path: "/api/pending-nonce", Summary: "Pending nonce of a test account", Tag: "accounts", Request: toytypes.PendingNonceRequest{}, Response: toytypes.PendingNonceResponse{}},
+		{Method: "GET", Path: "/api/units/parse", Summary: "Convert an amount such as 1.5 ether into its raw value", Tag: "units", Query: []openapi.Param{{Name: "amount", Description: "e.g. 20 gwei or 100 USDC", Required: true}}, Response: toytypes.ParseAmountResponse{}},
+
+		{Method: "POST", Path: "/sign-tx", Summary: "Sign a transfer, legacy", Tag: "txs", Request: toytypes.SignTxRequest{}, Response: SignTxResponse{}},
+		{Method: "POST", Path: "/send-tx", Summary: "Send a transfer between test accounts, legacy", Tag: "txs", Request: toytypes.SignTxRequest{}, Response: SendTxResponse{}},
+		{Method: "POST", Path: "/api/sign-tx", Summary: "Sign a tx without sending it", Tag: "txs", Request: toytypes.SignTxRequest{}, Response: toytypes.SignTxAPIResponse{}},
+		{Method: "POST", Path: "/api/send-tx", Summary: "Send a transfer, call or deployment", Tag: "txs", Request: toytypes.SignTxRequest{}, Response: toytypes.SendTxAPIResponse{}},
+		{Method: "POST", Path: "/api/send-batch", Summary: "Send txs in order with managed nonces", Tag: "txs", Request: toytypes.SendBatchRequest{}, Response: toytypes.SendBatchResponse{}},
+		{Method: "GET", Path: "/api/txs", Summary: "Txs sent through DevServer", Tag: "txs", Response: []toytypes.TxRecord{}},
+		{Method: "GET", Path: "/api/tx/{hash}/receipt", Summary: "Receipt with the revert reason of a failed tx", Tag: "txs", Response: toytypes.TxReceiptResponse{}},
+		{Method: "GET", Path: "/api/tx/{hash}/trace", Summary: "Call tree of a tx", Tag: "txs", Response: toytypes.TxTraceResponse{}},
+		{Method: "GET", Path: "/api/tx/{hash}/state-diff", Summary: "Balance, nonce and storage changes of a tx", Tag: "txs", Response: toytypes.StateDiffResponse{}},
+		{Method: "POST", Path: "/api/tx/{hash}/speedup", Summary: "Resend a pending tx with higher fees", Tag: "txs", Request: toytypes.ReplaceTxRequest{}, Response: toytypes.ReplaceTxResponse{}},
+		{Method: "POST", Path: "/api/tx/{hash}/cancel", Summary: "Replace a pending tx with a self-transfer", Tag: "txs", Request: toytypes.ReplaceTxRequest{}, Response: toytypes.ReplaceTxResponse{}},
+		{Method: "POST", Path: "/api/simulate", Summary: "Run a call against state overrides without sending it", Tag: "txs", Request: toytypes.SimulateTxRequest{}, Response: toytypes.SimulateTxResponse{}},
+
+		{Method: "POST", Path: "/api/sign-message", Summary: "personal_sign a message", Tag: "signatures", Request: toytypes.SignMessageRequest{}, Response: toytypes.SignatureResponse{}},
+		{Method: "POST", Path: "/api/sign-typed-data", Summary: "Sign EIP-712 typed data", Tag: "signatures", Request: toytypes.SignTypedDataRequest{}, Response: toytypes.SignatureResponse{}},
+		{Method: "POST", Path: "/api/verify-signature", Summary: "Recover the signer of a message or typed data", Tag: "signatures", Request: toytypes.VerifySignatureRequest{}, Response: toytypes.VerifySignatureResponse{}},
+
+		{Method: "GET", Path: "/api/chain/capabilities", Summary: "Chain controls the node supports", Tag: "chain", Response: toytypes.ChainCapabilities{}},
+		{Method: "POST", Path: "/api/chain/snapshot", Summary: "Snapshot the chain state", Tag: "chain", Response: toytypes.ChainSnapshotResponse{}},
+		{Method: "POST", Path: "/api/chain/revert", Summary: "Revert to a snapshot", Tag: "chain", Request: toytypes.ChainRevertRequest{}, Response: toytypes.ChainHeadResponse{}},
+		{Method: "POST", Path: "/api/chain/mine", Summary: "Mine blocks", Tag: "chain", Request: toytypes.ChainMineRequest{}, Response: toytypes.ChainHeadResponse{}},
+		{Method: "POST", Path: "/api/chain/next-timestamp", Summary: "Set the timestamp of the next block", Tag: "chain", Request: toytypes.ChainTimeRequest{}, Response: toytypes.ChainHeadResponse{}},
+		{Method: "POST", Path: "/api/chain/increase-time", Summary: "Advance the chain time", Tag: "chain", Request: toytypes.ChainTimeRequest{}, Response: toytypes.ChainHeadResponse{}},
+
+		{Method: "POST", Path: "/api/deploy-contract", Summary: "Deploy bytecode from a test account", Tag: "contracts", Request: toytypes.DeployContractRequest{}, Response: toytypes.ContractDeploymentResponse{}},
+		{Method: "POST", Path: "/api/register-alias", Summary: "Register a deployed contract under an alias", Tag: "contracts", Request: contract.DeployedContractMetaJSON{}, Response: toytypes.AliasRegisterResponse{}},
+		{Method: "GET", Path: "/api/contracts", Summary: "Registered contracts without their ABIs", Tag: "contracts", Response: []contract.DeployedContractInfo{}},
+		{Method: "GET", Path: "/api/contracts/{address}", Summary: "A registered contract by address", Tag: "contracts", Response: contract.DeployedContractInfo{}},
+
+		{Method: "POST", Path: "/rpc", Summary: "JSON-RPC proxy to the node, signing eth_sendTransaction for test accounts; also upgrades to WebSocket", Tag: "rpc", Request: rpcproxy.Message{}, Produces: "application/json"},
+	}
+}
+
+// OpenAPI is DevServer's document, checked in as swagger/devserver.json
+func OpenAPI() *openapi.Document {
+	ops := append(servers.Operations(), swagger.Operations()...)
+	return openapi.Build(openapi.Info{
+		Title:       "DevServer API",
+		Description: "Signs and sends txs for the test accounts and fronts the dev chain's tooling.",
+		Version:     "1.0.0",
+	}, append(ops, Operations()...))
+}
@@ -0,0 +1,32 @@
+package devserver
+
+import (
+	"testing"
+
+	"eth-toy-client/core/openapi"
+	"eth-toy-client/core/siwe"
+	"eth-toy-client/servers/servers"
+	"eth-toy-client/swagger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	rec := &openapi.Recorder{}
+	servers.SetupPingRoute("devserver", rec)
+	servers.SetupConfigRoute(rec)
+	servers.SetupRootRoutes(servers.NewHealth(), rec)
+	registerRoutes(rec, routeDeps{nodeClient: &servers.NodeClient{}})
+	patterns := append(rec.Patterns(), siwe.Paths()...)
+
+	doc := OpenAPI()
+	require.Empty(t, doc.Missing(patterns), "routes without an entry in Operations")
+	require.Empty(t, doc.Unrouted(patterns), "documented operations no route serves")
+}
+
+func TestEmbeddedSpecIsUpToDate(t *testing.T) {
+	want, err := openapi.Marshal(OpenAPI())
+	require.NoError(t, err)
+	embedded, err := swagger.Spec(SpecFile)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(embedded), "swagger/%s is stale, run go generate ./swagger", SpecFile)
+}
@@ -0,0 +1,164 @@
+package devserver
+
+import (
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"eth-toy-client/servers/servers"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"math/big"
+	"net/http"
+)
+
+// replacementPriceBump mirrors geth's default txpool.pricebump: a replacement must raise
+// both the tip and the fee cap by at least this percentage
+const replacementPriceBump = 10
+
+// handleReplaceTx re-signs the nonce of a pending tx with bumped fees. A speedup keeps the
+// original call, a cancel turns it into a zero-value self-transfer.
+func handleReplaceTx(kind string, nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry, history *TxHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		txHash, ok := parseTxHash(w, r)
+		if !ok {
+			return
+		}
+
+		var req toytypes.ReplaceTxRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		ctx := r.Context()
+		original, isPending, err := nodeClient.Client.TransactionByHash(ctx, txHash)
+		if err != nil {
+			if err == ethereum.NotFound {
+				httpapi.Fail(w, httpapi.ErrNotFound, fmt.Sprintf("Tx '%s' not found", txHash.Hex()))
+				return
+			}
+			logger.ErrorContext(ctx, "❌ Failed to fetch tx", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+		if !isPending {
+			httpapi.FailWithDetails(w, httpapi.ErrConflict, fmt.Sprintf("Tx '%s' is already mined", txHash.Hex()),
+				map[string]any{"txHash": txHash.Hex()})
+			return
+		}
+
+		sender, err := types.Sender(types.LatestSignerForChainID(original.ChainId()), original)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidSignature, err.Error())
+			return
+		}
+		from, ok := accounts.ByAddress(sender)
+		if !ok {
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender %s is not a test account", sender.Hex()))
+			return
+		}
+
+		tipCap, feeCap, err := replacementFees(original, req)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+			return
+		}
+
+		replacement := &types.DynamicFeeTx{
+			ChainID:   original.ChainId(),
+			Nonce:     original.Nonce(),
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       original.Gas(),
+			To:        original.To(),
+			Value:     original.Value(),
+			Data:      original.Data(),
+		}
+		to := ""
+		if original.To() != nil {
+			to = original.To().Hex()
+		}
+		if kind == toytypes.TxKindCancel {
+			replacement.Gas = params.TxGas
+			replacement.To = &from.Address
+			replacement.Value = big.NewInt(0)
+			replacement.Data = nil
+			to = from.Name
+		}
+
+		signedTx, err := SignTx(original.ChainId(), types.NewTx(replacement), from.Signer)
+		if err != nil {
+			logger.ErrorContext(ctx, "❌ Signing failed", "error", err)
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
+			return
+		}
+		if err := sendTransaction(ctx, nodeClient.Client, signedTx, kind); err != nil {
+			logger.ErrorContext(ctx, "❌ Failed to send replacement", "error", err)
+			httpapi.Fail(w, httpapi.ErrTxRejected, err.Error())
+			return
+		}
+
+		history.Record(signedTx, kind, from.Name, to, txHash.Hex())
+		logger.InfoContext(ctx, "⏫ Replacement sent", "kind", kind, "replacement", signedTx.Hash().Hex(), "nonce", signedTx.Nonce(), "tipCap", tipCap, "feeCap", feeCap)
+
+		resp := &toytypes.ReplaceTxResponse{
+			TxHash:   signedTx.Hash().Hex(),
+			Replaces: txHash.Hex(),
+			Kind:     kind,
+			Nonce:    signedTx.Nonce(),
+		}
+		if req.Wait {
+			receipt, err := waitForReceipt(ctx, nodeClient.Client, signedTx.Hash())
+			if err != nil {
+				logger.ErrorContext(ctx, "❌ Failed waiting for receipt", "error", err)
+				httpapi.Fail(w, httpapi.ErrTimeout, err.Error())
+				return
+			}
+			resp.Receipt = buildReceiptResponse(ctx, nodeClient.Client, reg, receipt)
+		}
+
+		httpapi.WriteOK(w, resp)
+	}
+}
+
+// replacementFees picks the tip and fee cap of a replacement, defaulting to the smallest bump
+// the txpool accepts and rejecting explicit fees below it
+func replacementFees(original *types.Transaction, req toytypes.ReplaceTxRequest) (*big.Int, *big.Int, error) {
+	minTip := bumpFee(original.GasTipCap())
+	minFeeCap := bumpFee(original.GasFeeCap())
+
+	tipCap, err := feeOrDefault(req.GasTipCap, minTip)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid gasTipCap: %w", err)
+	}
+	feeCap, err := feeOrDefault(req.GasFeeCap, minFeeCap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid gasFeeCap: %w", err)
+	}
+	if tipCap.Cmp(minTip) < 0 {
+		return nil, nil, fmt.Errorf("gasTipCap must be at least %s wei", minTip)
+	}
+	if feeCap.Cmp(minFeeCap) < 0 {
+		return nil, nil, fmt.Errorf("gasFeeCap must be at least %s wei", minFeeCap)
+	}
+	if feeCap.Cmp(tipCap) < 0 {
+		feeCap = new(big.Int).Set(tipCap)
+	}
+	return tipCap, feeCap, nil
+}
+
+// bumpFee raises a fee by the replacement price bump, rounding up so tiny fees still increase
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+replacementPriceBump))
+	bumped.Div(bumped, big.NewInt(100))
+	return bumped.Add(bumped, big.NewInt(1))
+}
+
+func feeOrDefault(raw string, fallback *big.Int) (*big.Int, error) {
+	if raw == "" {
+		return fallback, nil
+	}
+	return units.ParseAmount(raw)
+}
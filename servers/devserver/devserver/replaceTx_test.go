@@ -0,0 +1,55 @@
+package devserver
+
+import (
+	"math/big"
+	"testing"
+
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func pendingTx(nonce uint64, tip, feeCap int64) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1337),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(feeCap),
+		Gas:       21_000,
+	})
+}
+
+func TestReplacementFeesDefaultToMinimumBump(t *testing.T) {
+	tip, feeCap, err := replacementFees(pendingTx(0, 1, 1_000_000_000), toytypes.ReplaceTxRequest{})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2), tip, "a 1 wei tip still has to go up")
+	require.Equal(t, big.NewInt(1_100_000_001), feeCap)
+}
+
+func TestReplacementFeesRejectUnderpriced(t *testing.T) {
+	_, _, err := replacementFees(pendingTx(0, 100, 1_000), toytypes.ReplaceTxRequest{GasTipCap: "105"})
+	require.Error(t, err)
+
+	tip, feeCap, err := replacementFees(pendingTx(0, 100, 1_000), toytypes.ReplaceTxRequest{GasTipCap: "5000"})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5000), tip)
+	require.Equal(t, big.NewInt(5000), feeCap, "fee cap is raised to cover the tip")
+}
+
+func TestTxHistoryLinksReplacements(t *testing.T) {
+	history := NewTxHistory()
+	original := pendingTx(3, 1, 1_000)
+	speedup := pendingTx(3, 2, 1_101)
+
+	history.Record(original, toytypes.TxKindSend, "bob", "alice", "")
+	history.Record(speedup, toytypes.TxKindSpeedup, "bob", "alice", original.Hash().Hex())
+
+	record, ok := history.Get(original.Hash().Hex())
+	require.True(t, ok)
+	require.Equal(t, speedup.Hash().Hex(), record.ReplacedBy)
+
+	all := history.All()
+	require.Len(t, all, 2)
+	require.Equal(t, original.Hash().Hex(), all[1].Replaces)
+	require.Equal(t, toytypes.TxKindSpeedup, all[1].Kind)
+}
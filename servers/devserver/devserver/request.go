@@ -0,0 +1,41 @@
+package devserver
+
+import (
+	"encoding/json"
+	"errors"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"io"
+	"net/http"
+)
+
+// decodeRequest reads the JSON body into req and checks its validate tags. An empty body decodes
+// as an empty request. On failure it has already answered and returns false.
+func decodeRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && !errors.Is(err, io.EOF) {
+		logger.WarnContext(r.Context(), "❌ Failed to decode JSON", "error", err)
+		httpapi.Fail(w, httpapi.ErrInvalidRequest, "Invalid JSON payload")
+		return false
+	}
+	if err := toytypes.Validate(req); err != nil {
+		logger.WarnContext(r.Context(), "⚠️ Invalid request", "error", err)
+		failValidation(w, err)
+		return false
+	}
+	return true
+}
+
+// failValidation answers with every invalid field listed under details.fields
+func failValidation(w http.ResponseWriter, err error) {
+	var fields toytypes.ValidationError
+	if !errors.As(err, &fields) {
+		httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+		return
+	}
+	httpapi.FailWithDetails(w, httpapi.ErrInvalidParameter, err.Error(), map[string]any{"fields": fields})
+}
+
+// fieldError reports a single field that failed a check only the handler can make
+func fieldError(w http.ResponseWriter, field, message string) {
+	failValidation(w, toytypes.ValidationError{{Field: field, Message: message}})
+}
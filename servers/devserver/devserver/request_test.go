@@ -0,0 +1,43 @@
+package devserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendTxRejectsInvalidFields(t *testing.T) {
+	code, resp := postJSON[toytypes.SendTxAPIResponse](t, handleSendTxAPI(nil, newTestStore(t), nil, nil), toytypes.SignTxRequest{
+		From:  "alice",
+		Value: "lots",
+		Data:  "0xnothex",
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorIs(t, resp.Error, httpapi.ErrInvalidParameter)
+
+	fields := resp.Error.Details["fields"].([]any)
+	require.Len(t, fields, 2)
+	require.Equal(t, "value", fields[0].(map[string]any)["field"])
+	require.Equal(t, "data", fields[1].(map[string]any)["field"])
+}
+
+func TestSignTxRejectsGarbageRecipient(t *testing.T) {
+	code, resp := postJSON[SignTxResponse](t, signTxHandler(nil, newTestStore(t)), toytypes.SignTxRequest{
+		From: "alice",
+		To:   "mallory",
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "to", resp.Error.Details["fields"].([]any)[0].(map[string]any)["field"])
+}
+
+func TestDecodeRequestKeepsMalformedJSONApart(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	handleSignMessage(newTestStore(t))(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{")))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"code":"InvalidRequest"`)
+}
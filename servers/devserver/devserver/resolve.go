@@ -0,0 +1,21 @@
+package devserver
+
+import (
+	contract "eth-toy-client/core/contracts"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// resolveAddress turns a test account alias, a registered contract alias or a hex address into an address
+func resolveAddress(name string, accounts *AccountStore, reg *contract.Registry) (common.Address, error) {
+	if acc, ok := accounts.Get(name); ok {
+		return acc.Address, nil
+	}
+	if info, ok := reg.GetByAlias(name); ok {
+		return common.HexToAddress(info.Address.Address), nil
+	}
+	if common.IsHexAddress(name) {
+		return common.HexToAddress(name), nil
+	}
+	return common.Address{}, fmt.Errorf("'%s' is neither a known alias nor an address", name)
+}
@@ -0,0 +1,134 @@
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"eth-toy-client/core/rpcproxy"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/servers"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// sendTxArgs are the eth_sendTransaction fields honoured when DevServer signs for a test account
+type sendTxArgs struct {
+	From                 string          `json:"from"` // an alias or an address
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                *hexutil.Uint64 `json:"nonce"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"`
+}
+
+// newRPCProxy fronts the node on /rpc; rec may be nil
+func newRPCProxy(nodeClient *servers.NodeClient, accounts *AccountStore, history *TxHistory, rec *rpcproxy.Recorder) *rpcproxy.Proxy {
+	proxy := rpcproxy.New(nodeClient.Config.HTTPURL, nodeClient.Config.WSURL)
+	proxy.Intercept("eth_sendTransaction", interceptSendTransaction(nodeClient, accounts, history))
+	if rec != nil {
+		proxy.Record(rec)
+	}
+	return proxy
+}
+
+// interceptSendTransaction signs eth_sendTransaction locally when from names a test account. Any
+// other sender, such as the node's unlocked dev account, is left to the node.
+func interceptSendTransaction(nodeClient *servers.NodeClient, accounts *AccountStore, history *TxHistory) rpcproxy.Interceptor {
+	return func(ctx context.Context, params json.RawMessage) (any, bool, error) {
+		var args []sendTxArgs
+		if err := json.Unmarshal(params, &args); err != nil || len(args) != 1 {
+			return nil, false, nil // the node reports malformed calls
+		}
+		from, ok := accounts.Get(args[0].From)
+		if !ok && common.IsHexAddress(args[0].From) {
+			from, ok = accounts.ByAddress(common.HexToAddress(args[0].From))
+		}
+		if !ok {
+			return nil, false, nil
+		}
+
+		chainID, err := nodeClient.Client.ChainID(ctx)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		defer nonceLocks.lock(from.Address)()
+		tx, err := buildProxiedTx(ctx, nodeClient, chainID, from.Address, args[0])
+		if err != nil {
+			return nil, true, err
+		}
+		signedTx, err := SignTx(chainID, tx, from.Signer)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to sign tx: %w", err)
+		}
+		if err := sendTransaction(ctx, nodeClient.Client, signedTx, txKindRPC); err != nil {
+			return nil, true, err
+		}
+
+		to := ""
+		if tx.To() != nil {
+			to = tx.To().Hex()
+		}
+		history.Record(signedTx, toytypes.TxKindSend, from.Name, to, "")
+		logger.InfoContext(ctx, "✍️ Signed eth_sendTransaction for test account", "alias", from.Name, "tx", signedTx.Hash().Hex())
+		return signedTx.Hash(), true, nil
+	}
+}
+
+// buildProxiedTx fills what the call leaves out from the pending nonce and the gas policy
+func buildProxiedTx(ctx context.Context, nodeClient *servers.NodeClient, chainID *big.Int, from common.Address, args sendTxArgs) (*types.Transaction, error) {
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	} else {
+		var err error
+		if nonce, err = nodeClient.Client.PendingNonceAt(ctx, from); err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+	}
+
+	gas := TxGas.Limit
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	}
+	tipCap, feeCap := TxGas.TipCap, TxGas.FeeCap
+	switch {
+	case args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil:
+		if args.MaxFeePerGas != nil {
+			feeCap = args.MaxFeePerGas.ToInt()
+		}
+		if args.MaxPriorityFeePerGas != nil {
+			tipCap = args.MaxPriorityFeePerGas.ToInt()
+		}
+	case args.GasPrice != nil:
+		tipCap, feeCap = args.GasPrice.ToInt(), args.GasPrice.ToInt()
+	}
+
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	var data []byte
+	switch {
+	case args.Input != nil:
+		data = *args.Input
+	case args.Data != nil:
+		data = *args.Data
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        args.To,
+		Value:     value,
+		Data:      data,
+	}), nil
+}
@@ -0,0 +1,103 @@
+package devserver
+
+import (
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/servers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEth is a node that accepts raw txs and answers eth_sendTransaction for its own account
+type fakeEth struct {
+	sent   []*types.Transaction
+	reject func(*types.Transaction) error // optional, refuses raw txs like a node would
+}
+
+var nodeTxHash = common.HexToHash("0x01")
+
+func (f *fakeEth) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1337)) }
+
+func (f *fakeEth) GetTransactionCount(common.Address, string) hexutil.Uint64 { return 7 }
+
+func (f *fakeEth) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	if f.reject != nil {
+		if err := f.reject(tx); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	f.sent = append(f.sent, tx)
+	return tx.Hash(), nil
+}
+
+func (f *fakeEth) SendTransaction(map[string]any) common.Hash { return nodeTxHash }
+
+func TestRPCProxySignsForTestAccounts(t *testing.T) {
+	node := &fakeEth{}
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", node))
+	defer srv.Stop()
+	nodeServer := httptest.NewServer(srv)
+	defer nodeServer.Close()
+
+	nodeClient := &servers.NodeClient{Client: ethclient.NewClient(rpc.DialInProc(srv))}
+	nodeClient.Config.HTTPURL = nodeServer.URL
+	accounts := newTestStore(t)
+	alice, _ := accounts.Get("alice")
+	bob, _ := accounts.Get("bob")
+	history := NewTxHistory()
+
+	proxy := httptest.NewServer(newRPCProxy(nodeClient, accounts, history, nil))
+	defer proxy.Close()
+	client, err := rpc.Dial(proxy.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var txHash common.Hash
+	require.NoError(t, client.Call(&txHash, "eth_sendTransaction", map[string]any{
+		"from":  "alice",
+		"to":    bob.Address,
+		"value": "0x10",
+	}))
+	require.Len(t, node.sent, 1)
+	tx := node.sent[0]
+	require.Equal(t, tx.Hash(), txHash)
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	require.NoError(t, err)
+	require.Equal(t, alice.Address, sender)
+	require.Equal(t, big.NewInt(16), tx.Value())
+	require.EqualValues(t, 7, tx.Nonce(), "the pending nonce fills a missing one")
+	require.Equal(t, TxGas.Limit, tx.Gas())
+
+	record, ok := history.Get(txHash.Hex())
+	require.True(t, ok)
+	require.Equal(t, toytypes.TxKindSend, record.Kind)
+	require.Equal(t, "alice", record.From)
+
+	require.NoError(t, client.Call(&txHash, "eth_sendTransaction", map[string]any{
+		"from":  bob.Address,
+		"to":    alice.Address,
+		"nonce": "0x2",
+		"gas":   "0x5208",
+	}))
+	require.Len(t, node.sent, 2, "addresses of test accounts are signed for too")
+	require.EqualValues(t, 2, node.sent[1].Nonce())
+	require.EqualValues(t, 21_000, node.sent[1].Gas())
+
+	require.NoError(t, client.Call(&txHash, "eth_sendTransaction", map[string]any{
+		"from": "0x00000000000000000000000000000000000000aa",
+	}))
+	require.Equal(t, nodeTxHash, txHash, "other senders are left to the node")
+	require.Len(t, node.sent, 2)
+}
@@ -0,0 +1,230 @@
+package devserver
+
+import (
+	"context"
+	"errors"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"eth-toy-client/servers/servers"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"net/http"
+)
+
+// batchItem is a validated entry of a batch with the nonce planned for it
+type batchItem struct {
+	from  *TestAccount
+	to    *common.Address
+	value *big.Int
+	data  []byte
+	nonce uint64
+}
+
+// handleSendBatch submits the txs in request order over the shared node client.
+// Every item's sender and nonce are resolved before anything is sent, and the senders stay
+// locked until the batch is submitted, so a concurrent send cannot take a planned nonce.
+func handleSendBatch(nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry, history *TxHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			logger.WarnContext(r.Context(), "⚠️ Invalid method", "method", r.Method)
+			httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST is allowed")
+			return
+		}
+
+		var req toytypes.SendBatchRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		ctx := r.Context()
+		chainID, err := nodeClient.Client.ChainID(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "❌ Failed to get chain ID", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		logger.InfoContext(ctx, "📦 Sending batch", "txs", len(req.Txs))
+		resp := submitBatch(ctx, nodeClient, chainID, req, accounts, reg, history)
+
+		if req.Wait {
+			for i := range resp.Results {
+				result := &resp.Results[i]
+				if result.Status != toytypes.BatchItemSent {
+					continue
+				}
+				receipt, err := waitForReceipt(ctx, nodeClient.Client, common.HexToHash(result.TxHash))
+				if err != nil {
+					logger.ErrorContext(ctx, "❌ Failed waiting for receipt of batch tx", "index", i, "error", err)
+					result.Error = err.Error()
+					continue
+				}
+				result.Receipt = buildReceiptResponse(ctx, nodeClient.Client, reg, receipt)
+			}
+		}
+
+		httpapi.WriteOK(w, resp)
+	}
+}
+
+// submitBatch plans the nonces of all items and sends them in order while holding the sender locks
+func submitBatch(
+	ctx context.Context,
+	nodeClient *servers.NodeClient,
+	chainID *big.Int,
+	req toytypes.SendBatchRequest,
+	accounts *AccountStore,
+	reg *contract.Registry,
+	history *TxHistory,
+) *toytypes.SendBatchResponse {
+	items := make([]*batchItem, len(req.Txs))
+	itemErrs := make([]error, len(req.Txs))
+	var senders []common.Address
+	for i, txReq := range req.Txs {
+		items[i], itemErrs[i] = parseBatchItem(txReq, accounts, reg)
+		if itemErrs[i] == nil {
+			senders = append(senders, items[i].from.Address)
+		}
+	}
+
+	unlock := nonceLocks.lock(senders...)
+	defer unlock()
+	planNonces(ctx, nodeClient, items, itemErrs)
+
+	resp := &toytypes.SendBatchResponse{Results: make([]toytypes.BatchTxResult, len(req.Txs))}
+	stopped := false
+	for i, txReq := range req.Txs {
+		result := &resp.Results[i]
+		result.Index = i
+		result.From = txReq.From
+
+		if stopped {
+			result.Status = toytypes.BatchItemSkipped
+			resp.Skipped++
+			continue
+		}
+
+		err := itemErrs[i]
+		var signedTx *types.Transaction
+		if err == nil {
+			signedTx, err = sendBatchItem(ctx, nodeClient, chainID, items[i])
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "❌ Batch tx failed", "index", i, "from", txReq.From, "error", err)
+			result.Status = toytypes.BatchItemFailed
+			result.Error = err.Error()
+			resp.Failed++
+			stopped = req.StopOnError
+			if itemErrs[i] == nil {
+				releaseNonce(items, itemErrs, i)
+			}
+			continue
+		}
+
+		history.Record(signedTx, toytypes.TxKindSend, txReq.From, txReq.To, "")
+		nonce := signedTx.Nonce()
+		result.Status = toytypes.BatchItemSent
+		result.TxHash = signedTx.Hash().Hex()
+		result.Nonce = &nonce
+		resp.Sent++
+		logger.InfoContext(ctx, "✅ Batch tx sent", "index", i, "tx", result.TxHash, "nonce", nonce)
+	}
+	return resp
+}
+
+// planNonces gives the valid items consecutive nonces per sender, starting at the pending nonce.
+// Items whose sender's nonce cannot be fetched get an error instead.
+func planNonces(ctx context.Context, nodeClient *servers.NodeClient, items []*batchItem, itemErrs []error) {
+	next := make(map[common.Address]uint64)
+	for i, item := range items {
+		if itemErrs[i] != nil {
+			continue
+		}
+		nonce, ok := next[item.from.Address]
+		if !ok {
+			var err error
+			if nonce, err = nodeClient.Client.PendingNonceAt(ctx, item.from.Address); err != nil {
+				itemErrs[i] = fmt.Errorf("failed to get nonce: %w", err)
+				continue
+			}
+		}
+		item.nonce = nonce
+		next[item.from.Address] = nonce + 1
+	}
+}
+
+// releaseNonce hands the nonce of a rejected item down to the sender's later items, so the node
+// sees no gap
+func releaseNonce(items []*batchItem, itemErrs []error, failed int) {
+	sender := items[failed].from.Address
+	for i := failed + 1; i < len(items); i++ {
+		if itemErrs[i] == nil && items[i].from.Address == sender {
+			items[i].nonce--
+		}
+	}
+}
+
+// sendBatchItem signs and submits one tx with its planned nonce
+func sendBatchItem(ctx context.Context, nodeClient *servers.NodeClient, chainID *big.Int, item *batchItem) (*types.Transaction, error) {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     item.nonce,
+		GasTipCap: TxGas.TipCap,
+		GasFeeCap: TxGas.FeeCap,
+		Gas:       TxGas.Limit,
+		To:        item.to,
+		Value:     item.value,
+		Data:      item.data,
+	})
+	signedTx, err := SignTx(chainID, tx, item.from.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	if err := sendTransaction(ctx, nodeClient.Client, signedTx, txKindBatch); err != nil {
+		return nil, fmt.Errorf("failed to send tx: %w", err)
+	}
+	return signedTx, nil
+}
+
+func parseBatchItem(req toytypes.SignTxRequest, accounts *AccountStore, reg *contract.Registry) (*batchItem, error) {
+	if err := toytypes.Validate(req); err != nil {
+		return nil, err
+	}
+	from, ok := accounts.Get(req.From)
+	if !ok {
+		return nil, fmt.Errorf("sender '%s' not found", req.From)
+	}
+	item := &batchItem{from: from, value: new(big.Int)}
+
+	if req.Value != "" {
+		value, err := units.ParseAmount(req.Value)
+		if err != nil {
+			return nil, err
+		}
+		item.value = value
+	}
+	if req.Data != "" {
+		data, err := toytypes.DecodeHex(req.Data)
+		if err != nil {
+			return nil, err
+		}
+		item.data = data
+	}
+	if req.To == "" {
+		if len(item.data) == 0 {
+			return nil, errors.New("contract deployment requires 'data' field")
+		}
+		return item, nil
+	}
+
+	to, err := resolveAddress(req.To, accounts, reg)
+	if err != nil {
+		return nil, err
+	}
+	item.to = &to
+	return item, nil
+}
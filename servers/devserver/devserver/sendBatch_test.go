@@ -0,0 +1,85 @@
+package devserver
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchHandler serves /api/send-batch against an in-process node
+func newBatchHandler(t *testing.T, node *fakeEth) http.HandlerFunc {
+	return handleSendBatch(newNodeClient(t, node), newTestStore(t), contract.NewRegistry(), NewTxHistory())
+}
+
+func TestSendBatchOrdersTxsAcrossAliases(t *testing.T) {
+	node := &fakeEth{}
+	code, resp := postJSON[toytypes.SendBatchResponse](t, newBatchHandler(t, node), toytypes.SendBatchRequest{
+		Txs: []toytypes.SignTxRequest{
+			{From: "alice", To: "bob", Value: "1"},
+			{From: "bob", To: "alice", Value: "2"},
+			{From: "alice", To: "bob", Value: "3"},
+		},
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 3, resp.Data.Sent)
+
+	require.Len(t, node.sent, 3)
+	for i, tx := range node.sent {
+		require.Equal(t, big.NewInt(int64(i+1)), tx.Value(), "txs reach the node in request order")
+	}
+	nonces := []uint64{7, 7, 8}
+	for i, result := range resp.Data.Results {
+		require.Equal(t, toytypes.BatchItemSent, result.Status)
+		require.Equal(t, nonces[i], *result.Nonce, "nonces count up per sender")
+	}
+}
+
+func TestSendBatchKeepsNoncesContiguousAfterFailure(t *testing.T) {
+	node := &fakeEth{reject: func(tx *types.Transaction) error {
+		if tx.Value().Int64() == 2 {
+			return errors.New("insufficient funds for gas * price + value")
+		}
+		return nil
+	}}
+	_, resp := postJSON[toytypes.SendBatchResponse](t, newBatchHandler(t, node), toytypes.SendBatchRequest{
+		Txs: []toytypes.SignTxRequest{
+			{From: "alice", To: "bob", Value: "1"},
+			{From: "alice", To: "bob", Value: "2"},
+			{From: "mallory", To: "bob", Value: "3"},
+			{From: "alice", To: "bob", Value: "4"},
+		},
+	})
+	require.Equal(t, 2, resp.Data.Sent)
+	require.Equal(t, 2, resp.Data.Failed)
+
+	results := resp.Data.Results
+	require.Equal(t, toytypes.BatchItemFailed, results[1].Status)
+	require.Contains(t, results[1].Error, "insufficient funds")
+	require.Equal(t, toytypes.BatchItemFailed, results[2].Status)
+	require.Nil(t, results[2].Nonce)
+	require.EqualValues(t, 7, *results[0].Nonce)
+	require.EqualValues(t, 8, *results[3].Nonce, "the rejected tx leaves no gap")
+}
+
+func TestSendBatchStopOnErrorSkipsTheRest(t *testing.T) {
+	node := &fakeEth{}
+	_, resp := postJSON[toytypes.SendBatchResponse](t, newBatchHandler(t, node), toytypes.SendBatchRequest{
+		Txs: []toytypes.SignTxRequest{
+			{From: "alice", To: "bob", Value: "1"},
+			{From: "mallory", To: "bob", Value: "2"},
+			{From: "bob", To: "alice", Value: "3"},
+		},
+		StopOnError: true,
+	})
+	require.Equal(t, 1, resp.Data.Sent)
+	require.Equal(t, 1, resp.Data.Failed)
+	require.Equal(t, 1, resp.Data.Skipped)
+	require.Equal(t, toytypes.BatchItemSkipped, resp.Data.Results[2].Status)
+	require.Len(t, node.sent, 1, "skipped items never reach the node")
+}
@@ -2,49 +2,46 @@ package devserver
 
 import (
 	"context"
-	"encoding/hex"
-	"encoding/json"
+	contract "eth-toy-client/core/contracts"
 	"eth-toy-client/core/httpapi"
 	"eth-toy-client/core/logutil"
 	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
 	"eth-toy-client/servers/servers"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
-	"log"
 	"math/big"
 	"net/http"
 )
 
-func handleSendTxAPI(nodeClient *servers.NodeClient, accounts *map[string]*TestAccount) http.HandlerFunc {
+func handleSendTxAPI(nodeClient *servers.NodeClient, accounts *AccountStore, reg *contract.Registry, history *TxHistory) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("Headers: %+v\n", r.Header)
 		if r.Method != http.MethodPost {
-			log.Printf("⚠️ Invalid method: %s", r.Method)
-			httpapi.WriteError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "Only POST is allowed")
+			logger.WarnContext(r.Context(), "⚠️ Invalid method", "method", r.Method)
+			httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST is allowed")
 			return
 		}
 
 		var req toytypes.SignTxRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			log.Printf("❌ Failed to decode JSON: %v", err)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidRequest", "Invalid JSON payload")
+		if !decodeRequest(w, r, &req) {
 			return
 		}
 
-		from, ok := (*accounts)[req.From]
+		from, ok := accounts.Get(req.From)
 		if !ok {
-			log.Printf("⚠️ Sender not found: %s", req.From)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidAccount", fmt.Sprintf("Sender '%s' not found", req.From))
+			logger.WarnContext(r.Context(), "⚠️ Sender not found", "alias", req.From)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender '%s' not found", req.From))
 			return
 		}
 
 		val := new(big.Int)
-		if req.Value == "" {
-			val.SetInt64(0)
-		} else if _, ok := val.SetString(req.Value, 10); !ok {
-			log.Printf("❌ Invalid value format: %s", req.Value)
-			httpapi.WriteError(w, http.StatusBadRequest, "InvalidValue", "Invalid value format")
-			return
+		if req.Value != "" {
+			var err error
+			if val, err = units.ParseAmount(req.Value); err != nil {
+				logger.WarnContext(r.Context(), "❌ Invalid value format", "value", req.Value)
+				fieldError(w, "value", err.Error())
+				return
+			}
 		}
 
 		var (
@@ -54,56 +51,68 @@ func handleSendTxAPI(nodeClient *servers.NodeClient, accounts *map[string]*TestA
 
 		if req.To == "" {
 			// 🚀 Contract Deployment
-			log.Printf("📨 /send-tx: deploying contract from=%s", req.From)
+			logger.InfoContext(r.Context(), "📨 Deploying contract", "from", req.From)
 
 			if req.Data == "" {
-				log.Printf("❌ Missing contract bytecode in data field")
-				httpapi.WriteError(w, http.StatusBadRequest, "MissingData", "Contract deployment requires 'data' field")
+				logger.WarnContext(r.Context(), "❌ Missing contract bytecode in data field")
+				httpapi.Fail(w, httpapi.ErrInvalidRequest, "Contract deployment requires 'data' field")
 				return
 			}
 
 			toAddr = nil
-			rawByte := []byte(req.Data)
-			if (len(rawByte) % 2) == 1 {
-				rawByte = append([]byte("0"), rawByte...)
+			var err error
+			if data, err = toytypes.DecodeHex(req.Data); err != nil {
+				fieldError(w, "data", err.Error())
+				return
 			}
-
-			destHexByte := make([]byte, len(rawByte)/2)
-			hex.Decode(destHexByte, rawByte)
-			data = destHexByte
-			logutil.Infof("Hex Bytes Length: %d", len(data))
+			logger.DebugContext(r.Context(), "Contract bytecode", "bytes", len(data))
 
 		} else {
 			// 🔁 Normal Transfer
-			toAccount, ok := (*accounts)[req.To]
-			if !ok {
-				log.Printf("⚠️ Recipient not found: %s", req.To)
-				httpapi.WriteError(w, http.StatusBadRequest, "InvalidAccount", fmt.Sprintf("Recipient '%s' not found", req.To))
+			addr, err := resolveAddress(req.To, accounts, reg)
+			if err != nil {
+				logger.WarnContext(r.Context(), "⚠️ Recipient not found", "alias", req.To)
+				httpapi.Fail(w, httpapi.ErrInvalidAccount, err.Error())
 				return
 			}
-			addr := toAccount.Address
 			toAddr = &addr
-			log.Printf("📨 /send-tx: from=%s → to=%s | value=%s", req.From, req.To, req.Value)
+			logger.InfoContext(r.Context(), "📨 Sending tx", "from", req.From, "to", req.To, "value", req.Value)
 		}
 
-		_, signedTx, err := BuildAndSignTx(from.PrivKey, from.Address, toAddr, val, nodeClient.Config.Port, data)
+		unlock := nonceLocks.lock(from.Address) // a running batch may have planned the pending nonce
+		_, signedTx, err := BuildAndSignTx(from.Signer, from.Address, toAddr, val, nodeClient.Client, data)
 		if err != nil {
-			log.Printf("❌ Signing failed: %v", err)
-			httpapi.WriteError(w, http.StatusInternalServerError, "SigningFailed", err.Error())
+			unlock()
+			logger.ErrorContext(r.Context(), "❌ Signing failed", "error", err)
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
 			return
 		}
 
-		err = nodeClient.Client.SendTransaction(context.Background(), signedTx)
+		err = sendTransaction(context.Background(), nodeClient.Client, signedTx, toytypes.TxKindSend)
+		unlock()
 		if err != nil {
-			log.Printf("❌ Failed to send tx: %v", err)
-			httpapi.WriteError(w, http.StatusInternalServerError, "SendTxFailed", err.Error())
+			logger.ErrorContext(r.Context(), "❌ Failed to send tx", "error", err)
+			httpapi.Fail(w, httpapi.ErrTxRejected, err.Error())
 			return
 		}
 
-		log.Printf("✅ Sent TX: %s", signedTx.Hash().Hex())
+		history.Record(signedTx, toytypes.TxKindSend, req.From, req.To, "")
+		logutil.Annotate(r.Context(), "tx", signedTx.Hash().Hex())
+		logger.InfoContext(r.Context(), "✅ Sent tx")
 
-		httpapi.WriteOK[toytypes.SendTxAPIResponse](w, &toytypes.SendTxAPIResponse{
+		resp := &toytypes.SendTxAPIResponse{
 			TxHash: signedTx.Hash().Hex(),
-		})
+		}
+		if req.Wait {
+			receipt, err := waitForReceipt(r.Context(), nodeClient.Client, signedTx.Hash())
+			if err != nil {
+				logger.ErrorContext(r.Context(), "❌ Failed waiting for receipt", "error", err)
+				httpapi.Fail(w, httpapi.ErrTimeout, err.Error())
+				return
+			}
+			resp.Receipt = buildReceiptResponse(r.Context(), nodeClient.Client, reg, receipt)
+		}
+
+		httpapi.WriteOK[toytypes.SendTxAPIResponse](w, resp)
 	}
 }
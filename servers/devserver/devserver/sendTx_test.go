@@ -0,0 +1,73 @@
+package devserver
+
+import (
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	contract "eth-toy-client/core/contracts"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/servers"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// newNodeClient connects to node in process
+func newNodeClient(t *testing.T, node *fakeEth) *servers.NodeClient {
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", node))
+	t.Cleanup(srv.Stop)
+	return &servers.NodeClient{Client: ethclient.NewClient(rpc.DialInProc(srv))}
+}
+
+func TestSendTxWaitsForTheSendersBatch(t *testing.T) {
+	node := &fakeEth{}
+	store := newTestStore(t)
+	alice, _ := store.Get("alice")
+	handler := handleSendTxAPI(newNodeClient(t, node), store, contract.NewRegistry(), NewTxHistory())
+
+	unlock := nonceLocks.lock(alice.Address) // a batch planning alice's nonces
+	done := make(chan int)
+	go func() {
+		code, _ := postJSON[toytypes.SendTxAPIResponse](t, handler, toytypes.SignTxRequest{From: "alice", To: "bob", Value: "1"})
+		done <- code
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("send-tx read the pending nonce while the batch held the sender")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	require.Equal(t, http.StatusOK, <-done)
+	require.Len(t, node.sent, 1)
+}
+
+func TestSendAndSignTxUseTheParsedValue(t *testing.T) {
+	node := &fakeEth{}
+	nodeClient := newNodeClient(t, node)
+	store := newTestStore(t)
+	halfEther, _ := new(big.Int).SetString("500000000000000000", 10)
+
+	code, sent := postJSON[toytypes.SendTxAPIResponse](t, handleSendTxAPI(nodeClient, store, contract.NewRegistry(), NewTxHistory()),
+		toytypes.SignTxRequest{From: "alice", To: "bob", Value: "0.5 ether"})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, node.sent, 1)
+	require.Equal(t, halfEther, node.sent[0].Value())
+	require.Equal(t, node.sent[0].Hash().Hex(), sent.Data.TxHash)
+
+	code, signed := postJSON[toytypes.SignTxAPIResponse](t, handleSignTx(nodeClient, store),
+		toytypes.SignTxRequest{From: "alice", To: "bob", Value: "0.5 ether"})
+	require.Equal(t, http.StatusOK, code)
+	raw, err := hex.DecodeString(signed.Data.SignedTx)
+	require.NoError(t, err)
+	tx := new(types.Transaction)
+	require.NoError(t, rlp.DecodeBytes(raw, tx))
+	require.Equal(t, halfEther, tx.Value())
+	require.Equal(t, tx.Hash().Hex(), signed.Data.TxHash, "the hash is the signed tx's")
+}
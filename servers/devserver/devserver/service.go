@@ -0,0 +1,64 @@
+package devserver
+
+import (
+	"context"
+	"eth-toy-client/config"
+	"eth-toy-client/core/devchain"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/rpcproxy"
+	"eth-toy-client/servers/servers"
+	"github.com/ethereum/go-ethereum/common"
+	"net/http"
+)
+
+// DevServer signs and sends txs for the test accounts and fronts the dev chain's tooling
+type DevServer struct {
+	// Chain answers /api/chain/*, e.g. a devchain.SimulatedController in tests; nil probes the node
+	Chain    devchain.Controller
+	recorder *rpcproxy.Recorder // set while /rpc calls are recorded
+}
+
+func (devServer *DevServer) Name() config.ServerName {
+	return config.Servers.DevServer
+}
+
+func (devServer *DevServer) InitService(shared *servers.Shared, serverConfig config.ServerConfig) (config.ServerConfig, http.Handler) {
+	nodeClient := shared.NodeClient
+	var accounts []string
+	err := nodeClient.RPCClient.Call(&accounts, "eth_accounts")
+	if err != nil || len(accounts) == 0 {
+		logutil.Fatal(logger, "❌ Failed to get dev account", "error", err)
+	}
+	devAddr := common.HexToAddress(accounts[0])
+	bal, err := nodeClient.Client.BalanceAt(context.Background(), devAddr, nil)
+	if err != nil {
+		logutil.Fatal(logger, "❌ Failed to obtain balance of dev account", "address", devAddr.Hex(), "error", err)
+	}
+	logger.Info("✅ Dev account", "address", devAddr.Hex(), "balance", bal.String())
+
+	cfg := config.Current()
+	Configure(cfg)
+	testAccount := LoadTestAccounts(cfg.AccountsConfig())
+	fundedAccounts := FundTestAccounts(devAddr, nodeClient.RPCClient, testAccount)
+	if path := cfg.RPCProxy.RecordFile; path != "" {
+		if devServer.recorder, err = rpcproxy.NewRecorder(path); err != nil {
+			logutil.Fatal(logger, "❌ Failed to open RPC recording", "error", err)
+		}
+		logger.Info("📼 Recording /rpc calls", "file", path)
+	}
+	handler := SetupRoutes(serverConfig, shared.Registry, devAddr, nodeClient, fundedAccounts, devServer.recorder, devServer.Chain)
+	return serverConfig, handler
+}
+
+// Start has nothing to launch, DevServer only answers requests
+func (devServer *DevServer) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop closes the /rpc recording, if any
+func (devServer *DevServer) Stop(ctx context.Context) error {
+	if devServer.recorder == nil {
+		return nil
+	}
+	return devServer.recorder.Close()
+}
@@ -1,42 +1,103 @@
 package devserver
 
 import (
+	"context"
 	"eth-toy-client/config"
 	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/devchain"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/rpcproxy"
+	"eth-toy-client/core/tracing"
+	toytypes "eth-toy-client/core/types"
 	"eth-toy-client/servers/servers"
 	"eth-toy-client/swagger"
 	"github.com/ethereum/go-ethereum/common"
 	"net/http"
 )
 
-type accountResponse struct {
-	Name       string `json:"name"`
-	Address    string `json:"address"`
-	PrivateKey string `json:"privateKey"`
-}
-
 func SetupRoutes(
 	config config.ServerConfig,
 	reg *contract.Registry,
 	devAccount common.Address,
 	nodeClient *servers.NodeClient,
-	accounts *map[string]*TestAccount) *http.ServeMux {
-	mux := http.NewServeMux()
+	accounts *AccountStore,
+	recorder *rpcproxy.Recorder, // recorder is nil unless /rpc is recorded
+	chain devchain.Controller) *http.ServeMux { // chain is nil to probe the node's dev RPC methods
+	if chain == nil {
+		var err error
+		if chain, err = devchain.NewRPCController(context.Background(), nodeClient.RPCClient); err != nil {
+			logutil.Fatal(logger, "❌ Failed to probe chain controls", "error", err)
+		}
+	}
 
+	history := NewTxHistory()
+	mux := http.NewServeMux()
 	servers.SetupPingRoute(config.Name, mux)
-	mux.HandleFunc("/dev-account", handleDevAccounts(devAccount))
-	mux.HandleFunc("/accounts", handleAccounts(accounts))
-	mux.HandleFunc("/info", handleInfo(nodeClient, accounts))
-	mux.HandleFunc("/sign-tx", signTxHandler(nodeClient, accounts))
-	mux.HandleFunc("/send-tx", handleSendTx(nodeClient, accounts))
-	mux.HandleFunc("/api/pending-nonce", handlePendingNonce(nodeClient, accounts))
-	mux.HandleFunc("/api/sign-tx", handleSignTx(nodeClient, accounts))
-	mux.HandleFunc("/api/send-tx", handleSendTxAPI(nodeClient, accounts))
-	mux.HandleFunc("/api/deploy-contract", deployContract(nodeClient, accounts))
-	mux.HandleFunc("/api/register-alias", handleRegisterAlias(reg))
-	mux.HandleFunc("/api/contracts", handleGetContracts(reg))
-	mux.HandleFunc("/api/contracts/", handleGetContractByAlias(reg))
-	mux.HandleFunc("/swagger/", swagger.HandleSwagger)
-
+	servers.SetupConfigRoute(mux)
+	registerRoutes(mux, routeDeps{
+		reg:        reg,
+		devAccount: devAccount,
+		nodeClient: nodeClient,
+		accounts:   accounts,
+		tracer:     tracing.NewTracer(nodeClient.RPCClient, reg),
+		history:    history,
+		chain:      chain,
+		proxy:      newRPCProxy(nodeClient, accounts, history, recorder),
+	})
 	return mux
 }
+
+// routeDeps is what the handlers are built from
+type routeDeps struct {
+	reg        *contract.Registry
+	devAccount common.Address
+	nodeClient *servers.NodeClient
+	accounts   *AccountStore
+	tracer     *tracing.Tracer
+	history    *TxHistory
+	chain      devchain.Controller
+	proxy      *rpcproxy.Proxy
+}
+
+// registerRoutes is DevServer's route table; every route needs an entry in Operations
+func registerRoutes(mux servers.Mux, d routeDeps) {
+	mux.HandleFunc("/dev-account", handleDevAccounts(d.devAccount))
+	mux.HandleFunc("/accounts", handleAccounts(d.accounts))
+	mux.HandleFunc("/info", handleInfo(d.nodeClient, d.accounts))
+	mux.HandleFunc("/sign-tx", signTxHandler(d.nodeClient, d.accounts))
+	mux.HandleFunc("/send-tx", handleSendTx(d.nodeClient, d.accounts))
+	mux.HandleFunc("GET /api/accounts", handleListAccounts(d.accounts))
+	mux.HandleFunc("POST /api/accounts", handleCreateAccount(d.nodeClient, d.devAccount, d.accounts))
+	mux.HandleFunc("DELETE /api/accounts/{alias}", handleDeleteAccount(d.accounts))
+	mux.HandleFunc("POST /api/accounts/{alias}/fund", handleFundAccount(d.nodeClient, d.devAccount, d.accounts))
+	mux.HandleFunc("GET /api/accounts/{alias}/balance", handleBalance(d.nodeClient, d.accounts, d.reg))
+	mux.HandleFunc("GET /api/accounts/{alias}/tokens", handleTokenBalances(d.nodeClient, d.accounts, d.reg))
+	mux.HandleFunc("GET /api/accounts/{alias}/nonce", handleNonce(d.nodeClient, d.accounts, d.reg))
+	mux.HandleFunc("GET /api/units/parse", handleParseAmount(registryTokens{caller: d.nodeClient.Client, reg: d.reg}))
+	mux.HandleFunc("/api/pending-nonce", handlePendingNonce(d.nodeClient, d.accounts))
+	mux.HandleFunc("/api/sign-tx", handleSignTx(d.nodeClient, d.accounts))
+	mux.HandleFunc("/api/send-tx", handleSendTxAPI(d.nodeClient, d.accounts, d.reg, d.history))
+	mux.HandleFunc("/api/send-batch", handleSendBatch(d.nodeClient, d.accounts, d.reg, d.history))
+	mux.HandleFunc("GET /api/txs", handleTxHistory(d.history))
+	mux.HandleFunc("GET /api/tx/{hash}/receipt", handleTxReceipt(d.nodeClient, d.reg))
+	mux.HandleFunc("GET /api/tx/{hash}/trace", handleTxTrace(d.tracer))
+	mux.HandleFunc("GET /api/tx/{hash}/state-diff", handleTxStateDiff(d.tracer, d.accounts))
+	mux.HandleFunc("POST /api/tx/{hash}/speedup", handleReplaceTx(toytypes.TxKindSpeedup, d.nodeClient, d.accounts, d.reg, d.history))
+	mux.HandleFunc("POST /api/tx/{hash}/cancel", handleReplaceTx(toytypes.TxKindCancel, d.nodeClient, d.accounts, d.reg, d.history))
+	mux.HandleFunc("POST /api/sign-message", handleSignMessage(d.accounts))
+	mux.HandleFunc("POST /api/sign-typed-data", handleSignTypedData(d.accounts))
+	mux.HandleFunc("POST /api/verify-signature", handleVerifySignature(d.accounts, d.reg))
+	mux.HandleFunc("GET /api/chain/capabilities", handleChainCapabilities(d.chain))
+	mux.HandleFunc("POST /api/chain/snapshot", handleChainSnapshot(d.chain))
+	mux.HandleFunc("POST /api/chain/revert", handleChainRevert(d.chain))
+	mux.HandleFunc("POST /api/chain/mine", handleChainMine(d.chain))
+	mux.HandleFunc("POST /api/chain/next-timestamp", handleChainNextTimestamp(d.chain))
+	mux.HandleFunc("POST /api/chain/increase-time", handleChainIncreaseTime(d.chain))
+	mux.HandleFunc("/api/simulate", handleSimulate(d.tracer, d.accounts, d.reg))
+	mux.HandleFunc("/api/deploy-contract", deployContract(d.nodeClient, d.accounts))
+	mux.HandleFunc("/api/register-alias", handleRegisterAlias(d.reg))
+	mux.HandleFunc("/api/contracts", handleGetContracts(d.reg))
+	mux.HandleFunc("/api/contracts/", handleGetContractByAlias(d.reg))
+	mux.Handle("/rpc", d.proxy)
+	mux.Handle("/swagger/", swagger.Handler(SpecFile))
+}
@@ -0,0 +1,169 @@
+package devserver
+
+import (
+	"encoding/json"
+	"errors"
+	"eth-toy-client/accounts"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"fmt"
+	gethaccounts "github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"net/http"
+)
+
+func handleSignMessage(accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.SignMessageRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		from, ok := accounts.Get(req.From)
+		if !ok {
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Signer '%s' not found", req.From))
+			return
+		}
+		message, err := decodeMessage(req.Message, req.Encoding)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+			return
+		}
+
+		signature, err := from.Signer.SignText(message)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Failed to sign message", "alias", req.From, "error", err)
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
+			return
+		}
+
+		logger.InfoContext(r.Context(), "✍️ Signed message", "alias", req.From, "bytes", len(message))
+		httpapi.WriteOK(w, &toytypes.SignatureResponse{
+			Address:   from.Address.Hex(),
+			Hash:      hexutil.Encode(gethaccounts.TextHash(message)),
+			Signature: hexutil.Encode(signature),
+		})
+	}
+}
+
+func handleSignTypedData(accounts *AccountStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.SignTypedDataRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		from, ok := accounts.Get(req.From)
+		if !ok {
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Signer '%s' not found", req.From))
+			return
+		}
+		typedData, hash, err := parseTypedData(req.TypedData)
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+			return
+		}
+
+		signature, err := from.Signer.SignTypedData(typedData)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Failed to sign typed data", "alias", req.From, "error", err)
+			httpapi.Fail(w, httpapi.ErrSigningFailed, err.Error())
+			return
+		}
+
+		logger.InfoContext(r.Context(), "✍️ Signed typed data", "alias", req.From, "primaryType", typedData.PrimaryType)
+		httpapi.WriteOK(w, &toytypes.SignatureResponse{
+			Address:   from.Address.Hex(),
+			Hash:      hash,
+			Signature: hexutil.Encode(signature),
+		})
+	}
+}
+
+func handleVerifySignature(accountStore *AccountStore, reg *contract.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.VerifySignatureRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		signature, err := hexutil.Decode(ensureHexPrefix(req.Signature))
+		if err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidSignature, fmt.Sprintf("Invalid signature: %v", err))
+			return
+		}
+
+		resp := &toytypes.VerifySignatureResponse{}
+		if len(req.TypedData) > 0 {
+			typedData, hash, err := parseTypedData(req.TypedData)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+				return
+			}
+			resp.Hash = hash
+			recovered, err := accounts.RecoverTypedData(typedData, signature)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidSignature, err.Error())
+				return
+			}
+			resp.Address = recovered.Hex()
+		} else {
+			message, err := decodeMessage(req.Message, req.Encoding)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+				return
+			}
+			resp.Hash = hexutil.Encode(gethaccounts.TextHash(message))
+			recovered, err := accounts.RecoverText(message, signature)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidSignature, err.Error())
+				return
+			}
+			resp.Address = recovered.Hex()
+		}
+
+		recovered := common.HexToAddress(resp.Address)
+		if acc, ok := accountStore.ByAddress(recovered); ok {
+			resp.Alias = acc.Name
+		}
+		if req.Address != "" {
+			expected, err := resolveAddress(req.Address, accountStore, reg)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidAccount, err.Error())
+				return
+			}
+			valid := expected == recovered
+			resp.Valid = &valid
+		}
+
+		httpapi.WriteOK(w, resp)
+	}
+}
+
+func decodeMessage(message, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", toytypes.MessageEncodingUTF8:
+		return []byte(message), nil
+	case toytypes.MessageEncodingHex:
+		return hexutil.Decode(ensureHexPrefix(message))
+	}
+	return nil, fmt.Errorf("unknown encoding '%s'", encoding)
+}
+
+func parseTypedData(raw json.RawMessage) (apitypes.TypedData, string, error) {
+	var typedData apitypes.TypedData
+	if len(raw) == 0 {
+		return typedData, "", errors.New("typedData is required")
+	}
+	if err := json.Unmarshal(raw, &typedData); err != nil {
+		return typedData, "", fmt.Errorf("invalid typed data: %w", err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return typedData, "", fmt.Errorf("invalid typed data: %w", err)
+	}
+	return typedData, hexutil.Encode(hash), nil
+}
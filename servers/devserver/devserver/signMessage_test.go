@@ -0,0 +1,62 @@
+package devserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func postJSON[T any](t *testing.T, handler http.HandlerFunc, body interface{}) (int, *httpapi.APIResponse[T]) {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload)))
+
+	var resp httpapi.APIResponse[T]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	return recorder.Code, &resp
+}
+
+func TestSignAndVerifyMessage(t *testing.T) {
+	store := newTestStore(t)
+	verify := handleVerifySignature(store, contract.NewRegistry())
+
+	code, signed := postJSON[toytypes.SignatureResponse](t, handleSignMessage(store), toytypes.SignMessageRequest{
+		From:    "alice",
+		Message: "Sign in to dApp",
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	code, verified := postJSON[toytypes.VerifySignatureResponse](t, verify, toytypes.VerifySignatureRequest{
+		Message:   "Sign in to dApp",
+		Signature: signed.Data.Signature,
+		Address:   "alice",
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, signed.Data.Address, verified.Data.Address)
+	require.Equal(t, "alice", verified.Data.Alias)
+	require.True(t, *verified.Data.Valid)
+
+	_, verified = postJSON[toytypes.VerifySignatureResponse](t, verify, toytypes.VerifySignatureRequest{
+		Message:   "Sign in to dApp",
+		Signature: signed.Data.Signature,
+		Address:   "bob",
+	})
+	require.False(t, *verified.Data.Valid)
+}
+
+func TestSignTypedDataRejectsMalformedPayload(t *testing.T) {
+	code, resp := postJSON[toytypes.SignatureResponse](t, handleSignTypedData(newTestStore(t)), toytypes.SignTypedDataRequest{
+		From:      "alice",
+		TypedData: json.RawMessage(`{"primaryType": "Mail"}`),
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorIs(t, resp.Error, httpapi.ErrInvalidParameter)
+}
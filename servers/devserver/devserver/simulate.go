@@ -0,0 +1,109 @@
+package devserver
+
+import (
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/tracing"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"net/http"
+	"strings"
+)
+
+const defaultSimulationGas = 3_000_000
+
+func handleSimulate(tracer *tracing.Tracer, accounts *AccountStore, reg *contract.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			logger.WarnContext(r.Context(), "⚠️ Invalid method", "method", r.Method)
+			httpapi.Fail(w, httpapi.ErrMethodNotAllowed, "Only POST is allowed")
+			return
+		}
+
+		var req toytypes.SimulateTxRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+
+		from, ok := accounts.Get(req.From)
+		if !ok {
+			logger.WarnContext(r.Context(), "⚠️ Sender not found", "alias", req.From)
+			httpapi.Fail(w, httpapi.ErrInvalidAccount, fmt.Sprintf("Sender '%s' not found", req.From))
+			return
+		}
+
+		args := tracing.CallArgs{
+			From: from.Address,
+			Gas:  hexutil.Uint64(defaultSimulationGas),
+		}
+		if req.Gas != nil {
+			args.Gas = hexutil.Uint64(*req.Gas)
+		}
+		if req.To != "" {
+			to, err := resolveAddress(req.To, accounts, reg)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidAccount, err.Error())
+				return
+			}
+			args.To = &to
+		}
+		if req.Value != "" {
+			value, err := units.ParseAmount(req.Value)
+			if err != nil {
+				logger.WarnContext(r.Context(), "❌ Invalid value format", "value", req.Value)
+				fieldError(w, "value", err.Error())
+				return
+			}
+			args.Value = (*hexutil.Big)(value)
+		}
+		if req.Data != "" {
+			data, err := toytypes.DecodeHex(req.Data)
+			if err != nil {
+				fieldError(w, "data", err.Error())
+				return
+			}
+			args.Data = data
+		}
+
+		overrides := make(map[common.Address]tracing.OverrideAccount, len(req.StateOverrides))
+		for name, override := range req.StateOverrides {
+			addr, err := resolveAddress(name, accounts, reg)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+				return
+			}
+			account, err := tracing.NewOverrideAccount(override)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrInvalidParameter, fmt.Sprintf("Override for '%s': %v", name, err))
+				return
+			}
+			overrides[addr] = account
+		}
+
+		block := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		if req.BlockNumber != nil {
+			block = rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*req.BlockNumber))
+		}
+
+		logger.InfoContext(r.Context(), "🔮 Simulating call", "from", req.From, "to", req.To, "value", req.Value, "overrides", len(overrides))
+		result, err := tracer.Simulate(r.Context(), args, block, overrides)
+		if err != nil {
+			logger.ErrorContext(r.Context(), "❌ Simulation failed", "error", err)
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+
+		httpapi.WriteOK(w, result)
+	}
+}
+
+func ensureHexPrefix(data string) string {
+	if strings.HasPrefix(data, "0x") || strings.HasPrefix(data, "0X") {
+		return data
+	}
+	return "0x" + data
+}
@@ -5,7 +5,6 @@ import (
 	"eth-toy-client/config"
 	contract "eth-toy-client/core/contracts"
 	"eth-toy-client/core/httpapi"
-	"eth-toy-client/core/logutil"
 	toytypes "eth-toy-client/core/types"
 	devserver "eth-toy-client/servers/devserver/devserver/test/contracts/mockusdc"
 	"github.com/ethereum/go-ethereum/common"
@@ -107,7 +106,7 @@ func TestDeployContract(t *testing.T) {
 	code, err := client.CodeAt(ctx, receipt.ContractAddress, nil)
 	require.NoError(t, err, "❌ failed to fetch contract code")
 	require.NotNil(t, code, "❌ code is nil")
-	logutil.Infof("ℹ️contract code: %x", string(code))
+	t.Logf("ℹ️contract code: %x", string(code))
 	require.True(t, len(code) > 0, "❌ empty contract code")
 
 }
@@ -0,0 +1,76 @@
+package devserver
+
+import (
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"github.com/ethereum/go-ethereum/core/types"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TxHistory remembers the txs DevServer sent, including which ones replaced which
+type TxHistory struct {
+	mu      sync.RWMutex
+	records []*toytypes.TxRecord
+	byHash  map[string]*toytypes.TxRecord
+}
+
+func NewTxHistory() *TxHistory {
+	return &TxHistory{byHash: make(map[string]*toytypes.TxRecord)}
+}
+
+// Record adds a sent tx; when replaces is set the original entry is linked to the new one
+func (h *TxHistory) Record(tx *types.Transaction, kind, from, to, replaces string) {
+	record := &toytypes.TxRecord{
+		TxHash:             tx.Hash().Hex(),
+		Kind:               kind,
+		From:               from,
+		To:                 to,
+		Nonce:              tx.Nonce(),
+		GasTipCap:          tx.GasTipCap().String(),
+		GasFeeCap:          tx.GasFeeCap().String(),
+		GasTipCapFormatted: units.Format(tx.GasTipCap(), units.Gwei),
+		GasFeeCapFormatted: units.Format(tx.GasFeeCap(), units.Gwei),
+		Replaces:           replaces,
+		SentAt:             time.Now(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record)
+	h.byHash[record.TxHash] = record
+	if original, ok := h.byHash[replaces]; ok {
+		original.ReplacedBy = record.TxHash
+	}
+}
+
+// Get returns a copy of the record for a tx hash
+func (h *TxHistory) Get(txHash string) (toytypes.TxRecord, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	record, ok := h.byHash[txHash]
+	if !ok {
+		return toytypes.TxRecord{}, false
+	}
+	return *record, true
+}
+
+// All returns copies of every record in the order they were sent
+func (h *TxHistory) All() []toytypes.TxRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	all := make([]toytypes.TxRecord, 0, len(h.records))
+	for _, record := range h.records {
+		all = append(all, *record)
+	}
+	return all
+}
+
+func handleTxHistory(history *TxHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		all := history.All()
+		httpapi.WriteOK(w, &all)
+	}
+}
@@ -0,0 +1,62 @@
+package devserver
+
+import (
+	"context"
+	"eth-toy-client/core/metrics"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"strings"
+)
+
+// txKindDeploy, txKindBatch and txKindRPC label txs in the metrics only; history records them as sends
+const (
+	txKindDeploy = "deploy"
+	txKindBatch  = "batch"
+	txKindRPC    = "rpc"
+)
+
+// txErrorCodes maps the node's rejection messages to metric codes. The messages come back over
+// JSON-RPC as plain strings, so the txpool's error values cannot be matched with errors.Is.
+var txErrorCodes = []struct {
+	message string
+	code    string
+}{
+	{"nonce too low", "nonce_too_low"},
+	{"nonce too high", "nonce_too_high"},
+	{"already known", "already_known"},
+	{"replacement transaction underpriced", "replacement_underpriced"},
+	{"underpriced", "underpriced"},
+	{"insufficient funds", "insufficient_funds"},
+	{"gas limit", "gas_limit"},
+	{"intrinsic gas too low", "gas_limit"},
+}
+
+// classifyTxError returns the metric code of a rejected tx
+func classifyTxError(err error) string {
+	msg := strings.ToLower(err.Error())
+	for _, e := range txErrorCodes {
+		if strings.Contains(msg, e.message) {
+			return e.code
+		}
+	}
+	return "other"
+}
+
+// isNonceConflict tells whether the tx lost a race for its nonce
+func isNonceConflict(code string) bool {
+	return code == "nonce_too_low" || code == "already_known" || code == "replacement_underpriced"
+}
+
+// sendTransaction sends a signed tx and counts it as sent or failed under kind
+func sendTransaction(ctx context.Context, client *ethclient.Client, tx *types.Transaction, kind string) error {
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		code := classifyTxError(err)
+		metrics.TxsFailed.WithLabelValues(kind, code).Inc()
+		if isNonceConflict(code) {
+			metrics.NonceConflicts.Inc()
+		}
+		return err
+	}
+	metrics.TxsSent.WithLabelValues(kind).Inc()
+	return nil
+}
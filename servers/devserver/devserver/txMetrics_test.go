@@ -0,0 +1,25 @@
+package devserver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyTxError(t *testing.T) {
+	cases := map[string]string{
+		"nonce too low: next nonce 5, tx nonce 4": "nonce_too_low",
+		"already known":                              "already_known",
+		"replacement transaction underpriced":        "replacement_underpriced",
+		"transaction underpriced: tip needed 1":      "underpriced",
+		"insufficient funds for gas * price + value": "insufficient_funds",
+		"exceeds block gas limit":                    "gas_limit",
+		"connection refused":                         "other",
+	}
+	for msg, code := range cases {
+		require.Equal(t, code, classifyTxError(errors.New(msg)), msg)
+	}
+	require.True(t, isNonceConflict("already_known"))
+	require.False(t, isNonceConflict("insufficient_funds"))
+}
@@ -0,0 +1,73 @@
+package devserver
+
+import (
+	"context"
+	"errors"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/core/units"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"net/http"
+	"strings"
+)
+
+var errTokenDecimals = errors.New("failed to read token decimals")
+
+// registryTokens resolves token symbols against the registered ERC-20s, matching the alias or the on-chain symbol()
+type registryTokens struct {
+	caller ethereum.ContractCaller
+	reg    *contract.Registry
+}
+
+func (t registryTokens) TokenUnit(ctx context.Context, symbol string) (units.Unit, error) {
+	for _, info := range t.reg.All() {
+		if tokenStandard(info.ParsedABI) != toytypes.TokenStandardERC20 {
+			continue
+		}
+		token := common.HexToAddress(info.Address.Address)
+		if !strings.EqualFold(info.Alias, symbol) {
+			var onChain string
+			if callView(ctx, t.caller, info.ParsedABI, token, nil, "symbol", &onChain) != nil || !strings.EqualFold(onChain, symbol) {
+				continue
+			}
+		}
+		var decimals uint8
+		if err := callView(ctx, t.caller, info.ParsedABI, token, nil, "decimals", &decimals); err != nil {
+			return units.Unit{}, fmt.Errorf("%w of %s: %v", errTokenDecimals, info.Alias, err)
+		}
+		return units.Unit{Name: symbol, Decimals: decimals}, nil
+	}
+	return units.Unit{}, fmt.Errorf("%w '%s': no registered ERC-20 has that symbol", units.ErrUnknownUnit, symbol)
+}
+
+// handleParseAmount converts "1.5 ether", "20 gwei" or "100 USDC" into the raw integer amount
+func handleParseAmount(tokens units.TokenResolver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("amount")
+		if raw == "" {
+			fieldError(w, "amount", "is required")
+			return
+		}
+		amount, err := units.Parse(r.Context(), raw, tokens)
+		if errors.Is(err, errTokenDecimals) {
+			httpapi.Fail(w, httpapi.ErrNodeError, err.Error())
+			return
+		}
+		if err != nil {
+			fieldError(w, "amount", err.Error())
+			return
+		}
+
+		httpapi.WriteOK(w, &toytypes.ParseAmountResponse{
+			Input:     raw,
+			Value:     amount.Value.String(),
+			Unit:      amount.Unit.Name,
+			Decimals:  amount.Unit.Decimals,
+			Token:     amount.Token,
+			Formatted: units.Format(amount.Value, amount.Unit),
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package devserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmountResolvesRegisteredTokens(t *testing.T) {
+	parsed, err := contract.ParseABI(testERC20ABI)
+	require.NoError(t, err)
+	reg := contract.NewRegistry()
+	require.NoError(t, reg.Add(contract.DeployedContractInfo{
+		Alias:     "stable",
+		Address:   toytypes.ContractAddress{Address: "0x5FbDB2315678afecb367f032d93F642f64180aa3"},
+		ParsedABI: parsed,
+	}))
+	handler := handleParseAmount(registryTokens{
+		caller: &fakeToken{abi: parsed, returns: map[string][]interface{}{"symbol": {"USDC"}, "decimals": {uint8(6)}}},
+		reg:    reg,
+	})
+	parse := func(amount string) (int, *httpapi.APIResponse[toytypes.ParseAmountResponse]) {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest(http.MethodGet, "/api/units/parse?amount="+url.QueryEscape(amount), nil))
+		var resp httpapi.APIResponse[toytypes.ParseAmountResponse]
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		return recorder.Code, &resp
+	}
+
+	code, resp := parse("100 USDC")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "100000000", resp.Data.Value)
+	require.Equal(t, uint8(6), resp.Data.Decimals)
+	require.True(t, resp.Data.Token)
+
+	_, resp = parse("1.5 ether")
+	require.Equal(t, "1500000000000000000", resp.Data.Value)
+	require.Equal(t, "1.5 ether", resp.Data.Formatted)
+	require.False(t, resp.Data.Token)
+
+	code, resp = parse("3 DAI")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.ErrorIs(t, resp.Error, httpapi.ErrInvalidParameter)
+}
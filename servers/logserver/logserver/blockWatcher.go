@@ -0,0 +1,46 @@
+package logserver
+
+import (
+	"context"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/metrics"
+	"eth-toy-client/core/tracing"
+	"eth-toy-client/logbus"
+	"eth-toy-client/servers/servers"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// InitBlockWatcher follows new blocks until ctx ends and publishes the events that don't come from
+// contract logs: ErrorLog for failed transactions and InternalTransaction for nested calls
+func InitBlockWatcher(ctx context.Context, nodeClient *servers.NodeClient, broadcaster logbus.LogBroadcaster, registry *contract.Registry, status *servers.Status) {
+	tracer := tracing.NewTracer(nodeClient.RPCClient, registry)
+	keepSubscribed(ctx, "new head", status, func(ctx context.Context) error {
+		headers := make(chan *types.Header)
+		sub, err := nodeClient.WSClient.SubscribeNewHead(ctx, headers)
+		if err != nil {
+			return err
+		}
+		defer sub.Unsubscribe()
+		status.Set(nil)
+		logger.Info("🎧 Watching new blocks...")
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-sub.Err():
+				return subscriptionError(err)
+			case header := <-headers:
+				blockNumber := header.Number.Uint64()
+				for _, event := range FailedTxEvents(ctx, nodeClient.Client, registry, blockNumber) {
+					broadcaster.Publish(event)
+				}
+				for _, event := range InternalTxEvents(ctx, tracer, blockNumber) {
+					broadcaster.Publish(event)
+				}
+				if head, err := nodeClient.Client.BlockNumber(ctx); err == nil {
+					metrics.ObserveBlock(blockNumber, head)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package logserver
+
+import (
+	"context"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/revert"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/logbus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"time"
+)
+
+// FailedTxEvents decodes the revert reason of every failed tx in the block into an ErrorLog event.
+// A tx whose replay fails still gets one, with the replay error as an Unknown reason.
+func FailedTxEvents(ctx context.Context, client *ethclient.Client, registry *contract.Registry, blockNumber uint64) []logbus.LogEvent {
+	blockNr := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNumber))
+	receipts, err := client.BlockReceipts(ctx, blockNr)
+	if err != nil {
+		logger.Error("❌ Failed to fetch receipts", "block", blockNumber, "error", err)
+		return nil
+	}
+
+	var events []logbus.LogEvent
+	for _, receipt := range receipts {
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			continue
+		}
+
+		tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+		if err != nil {
+			logger.Error("❌ Failed to fetch failed tx", "tx", receipt.TxHash.Hex(), "error", err)
+			continue
+		}
+		target := common.Address{}
+		if tx.To() != nil {
+			target = *tx.To()
+		}
+
+		reason, err := revert.Explain(ctx, client, receipt, registry.ABIsFor(target)...)
+		if err != nil {
+			// The tx still failed, e.g. the node pruned the parent state; publish why it is unexplained
+			logger.Warn("⚠️ Failed to explain tx", "tx", receipt.TxHash.Hex(), "error", err)
+			reason = &toytypes.RevertReason{Kind: revert.KindUnknown, Message: err.Error()}
+		}
+		logger.Info("💥 Tx reverted", "tx", receipt.TxHash.Hex(), "block", blockNumber, "reason", revert.String(reason))
+		events = append(events, errorLogEvent(tx, receipt, reason))
+	}
+	return events
+}
+
+func errorLogEvent(tx *types.Transaction, receipt *types.Receipt, reason *toytypes.RevertReason) logbus.LogEvent {
+	args := map[string]interface{}{
+		"kind":    reason.Kind,
+		"message": reason.Message,
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		args["from"] = from.Hex()
+	}
+	if reason.Selector != "" {
+		args["selector"] = reason.Selector
+	}
+	if reason.PanicCode != nil {
+		args["panicCode"] = *reason.PanicCode
+	}
+	if reason.ErrorName != "" {
+		args["errorName"] = reason.ErrorName
+		args["args"] = reason.Args
+	}
+
+	contractAddress := receipt.ContractAddress.Hex()
+	if tx.To() != nil {
+		contractAddress = tx.To().Hex()
+	}
+
+	return logbus.LogEvent{
+		Contract:  contractAddress,
+		TxHash:    receipt.TxHash.Hex(),
+		Block:     receipt.BlockNumber.Uint64(),
+		Timestamp: time.Now().Unix(),
+		Args:      args,
+		LogType:   logbus.ErrorLog,
+	}
+}
@@ -0,0 +1,70 @@
+package logserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/revert"
+	"eth-toy-client/logbus"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEth serves one block with a failed tx whose replay fails with callErr
+type fakeEth struct {
+	tx      *types.Transaction
+	receipt *types.Receipt
+	callErr error
+}
+
+func (f *fakeEth) GetBlockReceipts(block json.RawMessage) []*types.Receipt {
+	return []*types.Receipt{f.receipt}
+}
+
+func (f *fakeEth) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	return f.tx
+}
+
+func (f *fakeEth) Call(args json.RawMessage, block string) (hexutil.Bytes, error) {
+	return nil, f.callErr
+}
+
+func TestFailedTxEventsPublishUnexplainedFailures(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1337)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1337), Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), To: &to,
+	})
+	require.NoError(t, err)
+	receipt := &types.Receipt{
+		Type:        types.DynamicFeeTxType,
+		Status:      types.ReceiptStatusFailed,
+		Logs:        []*types.Log{},
+		TxHash:      tx.Hash(),
+		BlockNumber: big.NewInt(5),
+	}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	require.NoError(t, server.RegisterName("eth", &fakeEth{tx: tx, receipt: receipt, callErr: errors.New("missing trie node")}))
+	client := ethclient.NewClient(rpc.DialInProc(server))
+
+	events := FailedTxEvents(context.Background(), client, contract.NewRegistry(), 5)
+	require.Len(t, events, 1, "a failed tx is published even when its replay fails")
+	event := events[0]
+	require.Equal(t, logbus.ErrorLog, event.LogType)
+	require.Equal(t, tx.Hash().Hex(), event.TxHash)
+	require.Equal(t, to.Hex(), event.Contract)
+	require.Equal(t, revert.KindUnknown, event.Args["kind"])
+	require.Contains(t, event.Args["message"], "missing trie node")
+}
@@ -0,0 +1,63 @@
+package logserver
+
+import (
+	"context"
+	"eth-toy-client/core/tracing"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/logbus"
+	"time"
+)
+
+// InternalTxEvents traces every transaction of the block and turns each nested call into an InternalTransaction event
+func InternalTxEvents(ctx context.Context, tracer *tracing.Tracer, blockNumber uint64) []logbus.LogEvent {
+	traces, err := tracer.RawBlockCallTraces(ctx, blockNumber)
+	if err != nil {
+		logger.Error("❌ Failed to trace block", "block", blockNumber, "error", err)
+		return nil
+	}
+
+	var events []logbus.LogEvent
+	for _, trace := range traces {
+		root := tracer.DecodeFrame(trace.Result, 0)
+		for _, frame := range tracing.Flatten(root) {
+			events = append(events, internalTxEvent(trace.TxHash.Hex(), blockNumber, frame))
+		}
+	}
+	return events
+}
+
+func internalTxEvent(txHash string, blockNumber uint64, frame toytypes.CallTraceFrame) logbus.LogEvent {
+	args := map[string]interface{}{
+		"type":    frame.Type,
+		"depth":   frame.Depth,
+		"from":    frame.From,
+		"to":      frame.To,
+		"gas":     frame.Gas,
+		"gasUsed": frame.GasUsed,
+	}
+	if frame.Value != "" {
+		args["value"] = frame.Value
+	}
+	if frame.Alias != "" {
+		args["alias"] = frame.Alias
+	}
+	if frame.Method != "" {
+		args["method"] = frame.Method
+		args["args"] = frame.Args
+	}
+	if frame.Error != "" {
+		args["error"] = frame.Error
+	}
+	if frame.Revert != nil {
+		args["revert"] = frame.Revert.Message
+	}
+
+	return logbus.LogEvent{
+		Contract:  frame.To,
+		TxHash:    txHash,
+		Block:     blockNumber,
+		Timestamp: time.Now().Unix(),
+		Args:      args,
+		LogType:   logbus.InternalTransaction,
+	}
+}
@@ -0,0 +1,30 @@
+package logserver
+
+import (
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/openapi"
+	toytypes "eth-toy-client/core/types"
+	"eth-toy-client/servers/servers"
+	"eth-toy-client/swagger"
+)
+
+// SpecFile is LogServer's document in the swagger package
+const SpecFile = "logserver.json"
+
+// Operations documents registerRoutes
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "POST", Path: "/api/register-contract", Summary: "Register a contract so its logs get decoded", Tag: "contracts", Request: contract.DeployedContractMetaJSON{}, Response: toytypes.AliasRegisterResponse{}},
+		{Method: "GET", Path: "/api/contract/{address}", Summary: "A registered contract by address", Tag: "contracts", Response: contract.DeployedContractInfo{}},
+	}
+}
+
+// OpenAPI is LogServer's document, checked in as swagger/logserver.json
+func OpenAPI() *openapi.Document {
+	ops := append(servers.Operations(), swagger.Operations()...)
+	return openapi.Build(openapi.Info{
+		Title:       "LogServer API",
+		Description: "Decodes contract logs and block events and fans them out to its consumers.",
+		Version:     "1.0.0",
+	}, append(ops, Operations()...))
+}
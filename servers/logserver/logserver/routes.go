@@ -8,6 +8,7 @@ import (
 	"eth-toy-client/core/logutil"
 	toytypes "eth-toy-client/core/types"
 	"eth-toy-client/servers/servers"
+	"eth-toy-client/swagger"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"net/http"
 	"strings"
@@ -17,22 +18,29 @@ import (
 func SetupRoutes(config config.ServerConfig, contractRegistry *contract.Registry) *http.ServeMux {
 	mux := http.NewServeMux()
 	servers.SetupPingRoute(config.Name, mux)
-	mux.HandleFunc("/api/register-contract", registerContract(contractRegistry))
-	mux.Handle("/api/contract/", http.StripPrefix("/contract", getContract(contractRegistry)))
+	servers.SetupConfigRoute(mux)
+	registerRoutes(mux, contractRegistry)
 	return mux
 }
 
+// registerRoutes is LogServer's route table; every route needs an entry in Operations
+func registerRoutes(mux servers.Mux, contractRegistry *contract.Registry) {
+	mux.HandleFunc("/api/register-contract", registerContract(contractRegistry))
+	mux.Handle("/api/contract/", http.StripPrefix("/api/contract", getContract(contractRegistry)))
+	mux.Handle("/swagger/", swagger.Handler(SpecFile))
+}
+
 func getContract(registry *contract.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		address := r.URL.Path[len("/"):]
 		if address == "" {
-			httpapi.WriteError(w, 400, "❌ MissingAlias", "Alias must be specified in the path")
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Alias must be specified in the path")
 			return
 		}
 		contactAddress := toytypes.ContractAddress{Address: address}
 		meta, err := registry.Get(contactAddress)
 		if !err {
-			httpapi.WriteError(w, 404, "❌ AliasNotFound", "Failed to get contract address "+contactAddress.Address)
+			httpapi.Fail(w, httpapi.ErrNotFound, "Failed to get contract address "+contactAddress.Address)
 			return
 		}
 		httpapi.WriteOK(w, &meta)
@@ -43,11 +51,11 @@ func registerContract(reg *contract.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var meta contract.DeployedContractMetaJSON
 		if err := json.NewDecoder(r.Body).Decode(&meta); err != nil {
-			httpapi.WriteError(w, 400, "❌ InvalidRequest", "Could not parse JSON")
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Could not parse JSON")
 			return
 		}
 		if meta.Alias == "" || meta.Address == "" {
-			httpapi.WriteError(w, 400, "❌ MissingFields", "Alias, address are required")
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Alias, address are required")
 			return
 		}
 		if meta.Timestamp == 0 {
@@ -57,17 +65,18 @@ func registerContract(reg *contract.Registry) http.HandlerFunc {
 		parsedABI, err := abi.JSON(strings.NewReader(meta.ABI))
 
 		if err != nil {
-			logutil.Errorf("❌ Error parsing ABI: %v", err)
-			httpapi.WriteError(w, 400, "❌ InvalidABI", "Could not parse ABI")
+			logger.WarnContext(r.Context(), "❌ Error parsing ABI", "error", err)
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, "Could not parse ABI")
 			return
 		}
 
 		info := meta.ToDeployedContractInfo(true)
 		info.ParsedABI = &parsedABI
 
-		logutil.Infof("📦 Registering alias: %s → %s", meta.Alias, meta.Address)
+		logutil.Annotate(r.Context(), "alias", meta.Alias)
+		logger.InfoContext(r.Context(), "📦 Registering alias", "address", meta.Address)
 		if err := reg.Add(*info); err != nil {
-			httpapi.WriteError(w, 400, "DuplicateAlias", err.Error())
+			httpapi.Fail(w, httpapi.ErrConflict, err.Error())
 			return
 		}
 
@@ -75,7 +84,6 @@ func registerContract(reg *contract.Registry) http.HandlerFunc {
 			Status: "ok",
 			Alias:  meta.Alias,
 		}
-		logutil.Infof("✅ sending: %v", res)
 		httpapi.WriteOK(w, &res)
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"eth-toy-client/config"
 	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/metrics"
 	toytypes "eth-toy-client/core/types"
 	"eth-toy-client/logbus"
 	"eth-toy-client/logsub"
@@ -12,55 +14,57 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/core/types"
-	"log"
+	"log/slog"
 	"net/http"
+	"sync"
 )
 
-type LogServer struct{}
+var logger = logutil.For("logserver")
+
+// LogServer decodes contract logs and block events and fans them out to its consumers
+type LogServer struct {
+	nodeClient  *servers.NodeClient
+	registry    *contract.Registry
+	broadcaster logbus.LogBroadcaster
+	events      chan logbus.LogEvent
+	consumer    *ConsoleConsumer
+
+	logsStatus  *servers.Status
+	headsStatus *servers.Status
+	cancel      context.CancelFunc
+	workers     sync.WaitGroup
+	consumed    chan struct{}
+}
 
 type LogDecoder struct {
 	registry *contract.Registry
 }
 
 func (logDecoder *LogDecoder) DecodeLog(logEvent types.Log) (logbus.LogEvent, error) {
-	fmt.Printf("Decodong log: %s\n", logEvent)
 	contractAddr := toytypes.ContractAddress{Address: logEvent.Address.Hex()}
 	evt := logsub.DecodeGenericLog(logEvent)
 	info, ok := logDecoder.registry.Get(contractAddr)
 	if !ok {
-		log.Printf("No info about contract %s", contractAddr)
+		logger.Debug("No info about contract", "contract", contractAddr.Address)
 		return evt, nil
 	}
 	if len(logEvent.Topics) == 0 {
-		log.Printf("No topics in log %s", logEvent)
+		logger.Debug("No topics in log", "contract", contractAddr.Address, "tx", logEvent.TxHash.Hex())
 		return evt, nil
 	}
 
-	fmt.Printf("TopicsLen: %v\n", len(logEvent.Topics))
-
 	for name, event := range info.ParsedABI.Events {
 		if logEvent.Topics[0] == event.ID {
 			out := map[string]interface{}{}
 			err := info.ParsedABI.UnpackIntoMap(out, name, logEvent.Data)
 			if err != nil {
-				log.Printf("❌ Failed to decode %s: %v", name, err)
+				logger.Error("❌ Failed to decode event", "event", name, "tx", logEvent.TxHash.Hex(), "error", err)
 
 			} else {
-				fmt.Printf("📢 Event: %s\n", name)
-				fmt.Printf("   Block: %d\n", logEvent.BlockNumber)
-				fmt.Printf("   BlockHash: %v\n", logEvent.BlockHash.Hex())
-				fmt.Printf("   Tx: %s\n", logEvent.TxHash.Hex())
-				fmt.Printf("   LogIndex: %d\n", logEvent.Index)
-				fmt.Printf("   Contract:%s\n", logEvent.Address.Hex())
-
-				fmt.Printf("🔍 Event Structure Details for '%s':\n", name)
-				eventDetails := event.Inputs
-
 				indexedArgs := make([]abi.Argument, 0)
 				noneIndexedArgs := make([]abi.Argument, 0)
 
-				for i, input := range eventDetails {
-					fmt.Printf("%d   Name: %s, Type: %s, Indexed: %v\n", i, input.Name, input.Type.String(), input.Indexed)
+				for _, input := range event.Inputs {
 					if input.Indexed {
 						indexedArgs = append(indexedArgs, input)
 					} else {
@@ -69,21 +73,30 @@ func (logDecoder *LogDecoder) DecodeLog(logEvent types.Log) (logbus.LogEvent, er
 				}
 
 				if len(noneIndexedArgs) != len(out) {
-					log.Printf("⚠️ Mismatch between noneIndexedArgs length (%d) and out length (%d)", len(noneIndexedArgs), len(out))
+					logger.Warn("⚠️ Mismatch between non-indexed args and decoded data", "event", name, "args", len(noneIndexedArgs), "decoded", len(out))
 				}
 
 				if len(indexedArgs) != (len(logEvent.Topics) - 1) {
-					log.Printf("⚠️ Mismatch between IdexedArgs length (%d) and topics (%d)", len(indexedArgs), len(logEvent.Topics))
+					logger.Warn("⚠️ Mismatch between indexed args and topics", "event", name, "args", len(indexedArgs), "topics", len(logEvent.Topics))
 				}
 
+				args := make([]any, 0, len(indexedArgs)+len(out))
 				indexedValues := logEvent.Topics[1:]
 				for i, input := range indexedArgs {
-					fmt.Printf("  %s : %s\n", input.Name, indexedValues[i].Hex())
+					if i < len(indexedValues) {
+						args = append(args, slog.String(input.Name, indexedValues[i].Hex()))
+					}
 				}
-
 				for k, v := range out {
-					fmt.Printf("   %s: %v\n", k, v)
+					args = append(args, slog.Any(k, v))
 				}
+				logger.Debug("📢 Decoded event",
+					"event", name,
+					"block", logEvent.BlockNumber,
+					"tx", logEvent.TxHash.Hex(),
+					"logIndex", logEvent.Index,
+					"contract", logEvent.Address.Hex(),
+					slog.Group("args", args...))
 			}
 		}
 	}
@@ -92,54 +105,118 @@ func (logDecoder *LogDecoder) DecodeLog(logEvent types.Log) (logbus.LogEvent, er
 }
 
 func (logServer *LogServer) Name() config.ServerName {
-	return "LogServer"
+	return config.Servers.LogServer
 }
 
-func (logServer *LogServer) InitService(nodeClient *servers.NodeClient, serverConfig config.ServerConfig) (config.ServerConfig, http.Handler) {
-	contractRegistry := contract.NewRegistry()
-	broadcaster := logbus.NewLogBroadcaster()
-	eventBus := make(chan logbus.LogEvent, 10)
-
-	consoleConsumer := &ConsoleConsumer{
+func (logServer *LogServer) InitService(shared *servers.Shared, serverConfig config.ServerConfig) (config.ServerConfig, http.Handler) {
+	logServer.nodeClient = shared.NodeClient
+	logServer.registry = shared.Registry
+	logServer.broadcaster = shared.Broadcaster
+	logServer.events = make(chan logbus.LogEvent, 10)
+	logServer.consumer = &ConsoleConsumer{
 		Name:             "ConsoleConsumer",
-		ContractRegistry: contractRegistry,
-		Events:           eventBus}
-	go consoleConsumer.Consume()
+		ContractRegistry: logServer.registry,
+		Events:           logServer.events}
+	logServer.logsStatus = servers.NewStatus(errNotSubscribed)
+	logServer.headsStatus = servers.NewStatus(errNotSubscribed)
 
-	broadcaster.Subscribe(eventBus)
-	logDecoder := &LogDecoder{
-		registry: contractRegistry,
-	}
+	handlers := SetupRoutes(serverConfig, logServer.registry)
+	return serverConfig, handlers
+}
 
-	go InitLogListener(nodeClient, broadcaster, logDecoder)
+// Start subscribes to logs and new heads and starts the console consumer
+func (logServer *LogServer) Start(ctx context.Context) error {
+	ctx, logServer.cancel = context.WithCancel(ctx)
+	logServer.consumed = make(chan struct{})
+	go func() {
+		defer close(logServer.consumed)
+		logServer.consumer.Consume()
+	}()
+	logServer.broadcaster.SubscribeAs(logServer.consumer.Name, logServer.events)
+
+	decoder := &LogDecoder{registry: logServer.registry}
+	logServer.workers.Add(2)
+	go func() {
+		defer logServer.workers.Done()
+		InitLogListener(ctx, logServer.nodeClient, logServer.broadcaster, decoder, logServer.logsStatus)
+	}()
+	go func() {
+		defer logServer.workers.Done()
+		InitBlockWatcher(ctx, logServer.nodeClient, logServer.broadcaster, logServer.registry, logServer.headsStatus)
+	}()
+	return nil
+}
 
-	handlers := SetupRoutes(serverConfig, contractRegistry)
-	return serverConfig, handlers
+// Stop ends the subscriptions, then lets the consumer drain the events already published
+func (logServer *LogServer) Stop(ctx context.Context) error {
+	if logServer.cancel == nil {
+		return nil
+	}
+	logServer.cancel()
+	if err := waitFor(ctx, logServer.workers.Wait); err != nil {
+		return fmt.Errorf("subscriptions did not stop: %w", err)
+	}
+	logServer.broadcaster.Unsubscribe(logServer.events)
+	close(logServer.events)
+	if err := waitFor(ctx, func() { <-logServer.consumed }); err != nil {
+		return fmt.Errorf("consumer did not drain: %w", err)
+	}
+	logger.Info("🧹 Log listener and broadcaster drained")
+	return nil
 }
 
-func InitLogListener(nodeClient *servers.NodeClient, broadcaster logbus.LogBroadcaster, decoder logsub.Decoder) {
-	logsCh := make(chan types.Log)
-	query := ethereum.FilterQuery{}
+// HealthChecks reports whether the log and new head subscriptions are live
+func (logServer *LogServer) HealthChecks() map[string]servers.HealthCheck {
+	return map[string]servers.HealthCheck{
+		"logSubscription":     logServer.logsStatus.Check,
+		"newHeadSubscription": logServer.headsStatus.Check,
+	}
+}
 
-	sub, err := nodeClient.WSClient.SubscribeFilterLogs(context.Background(), query, logsCh)
-	if err != nil {
-		log.Fatalf("❌ Failed to subscribe to logs: %v", err)
+// waitFor runs wait and gives up when ctx ends first
+func waitFor(ctx context.Context, wait func()) error {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	log.Println("🎧 Listening for logs...")
-	for {
-		select {
-		case err := <-sub.Err():
-			log.Printf("⚠️ Subscription error: %v", err)
-
-		case logEvent := <-logsCh:
-			//log.Printf("📄 Received log: %+v", logEvent)
-			event, errX := decoder.DecodeLog(logEvent)
-			if errX != nil {
-				log.Printf("❌ Failed to decode log: %v", err)
-				continue
-			}
-			broadcaster.Publish(event)
+}
 
+// InitLogListener publishes every decoded contract log until ctx ends, resubscribing after errors
+func InitLogListener(ctx context.Context, nodeClient *servers.NodeClient, broadcaster logbus.LogBroadcaster, decoder logsub.Decoder, status *servers.Status) {
+	keepSubscribed(ctx, "log", status, func(ctx context.Context) error {
+		logsCh := make(chan types.Log)
+		sub, err := nodeClient.WSClient.SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, logsCh)
+		if err != nil {
+			return err
 		}
-	}
+		defer sub.Unsubscribe()
+		status.Set(nil)
+		logger.Info("🎧 Listening for logs...")
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case err := <-sub.Err():
+				return subscriptionError(err)
+			case logEvent := <-logsCh:
+				//log.Printf("📄 Received log: %+v", logEvent)
+				metrics.LogEventsReceived.Inc()
+				event, err := decoder.DecodeLog(logEvent)
+				if err != nil {
+					metrics.LogEventsDecoded.WithLabelValues("error").Inc()
+					logger.Error("❌ Failed to decode log", "tx", logEvent.TxHash.Hex(), "error", err)
+					continue
+				}
+				metrics.LogEventsDecoded.WithLabelValues("ok").Inc()
+				broadcaster.Publish(event)
+			}
+		}
+	})
 }
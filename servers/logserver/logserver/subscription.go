@@ -0,0 +1,42 @@
+package logserver
+
+import (
+	"context"
+	"errors"
+	"eth-toy-client/core/metrics"
+	"eth-toy-client/servers/servers"
+	"time"
+)
+
+// resubscribeDelay is the pause between a failed subscription and the next attempt
+const resubscribeDelay = 2 * time.Second
+
+var errNotSubscribed = errors.New("not subscribed yet")
+
+// keepSubscribed runs serve until ctx ends. serve subscribes and returns the error that ended its
+// subscription; the status turns unhealthy and a new subscription follows after resubscribeDelay.
+func keepSubscribed(ctx context.Context, name string, status *servers.Status, serve func(ctx context.Context) error) {
+	for {
+		err := serve(ctx)
+		if ctx.Err() != nil {
+			status.Set(ctx.Err())
+			return
+		}
+		status.Set(err)
+		logger.Warn("⚠️ Subscription error, retrying", "subscription", name, "retryIn", resubscribeDelay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+		metrics.SubscriptionReconnects.WithLabelValues(name).Inc()
+	}
+}
+
+// subscriptionError names the nil error a subscription reports once it is closed
+func subscriptionError(err error) error {
+	if err == nil {
+		return errors.New("subscription closed")
+	}
+	return err
+}
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"eth-toy-client/core/logutil"
 	"eth-toy-client/servers/logserver/logserver"
 	"eth-toy-client/servers/servers"
 )
 
 func main() {
 	logServer := &logserver.LogServer{}
-	servers.StartMicroService(logServer)
-	select {}
+	if err := servers.RunMicroService(logServer); err != nil {
+		logutil.Fatal(logutil.For("logserver"), "❌ Server stopped", "error", err)
+	}
 }
@@ -4,7 +4,6 @@ import (
 	"eth-toy-client/config"
 	contract "eth-toy-client/core/contracts"
 	"eth-toy-client/core/httpapi"
-	"eth-toy-client/core/logutil"
 	toytypes "eth-toy-client/core/types"
 	"eth-toy-client/kit/mockusdc"
 	"eth-toy-client/servers/servers"
@@ -47,7 +46,7 @@ func TestContractAliasNotFound(t *testing.T) {
 	_, apiError, err := httpapi.ParseAPIResponse[contract.DeployedContractMetaJSON](res)
 	require.NoError(t, err, "❌ failed to parse response")
 	require.NotNil(t, apiError, "❌ expected non-nil apiError")
-	logutil.Infof("apiError: %v", apiError)
+	t.Logf("apiError: %v", apiError)
 }
 
 func TestServeInvalidUrl(t *testing.T) {
@@ -84,6 +83,9 @@ func TestRegisterContractAddress(t *testing.T) {
 	res2, apiError, err := servers.GetContract(contractAddress)
 	require.NoError(t, err, "❌ failed to get contract, expected nil error")
 	require.Nil(t, apiError, "❌ failed to get contract, expected nil apiError")
-	require.Equal(t, &payload, res2, "❌ payload mismatch")
+	require.Equal(t, payload.Alias, res2.Alias, "❌ alias mismatch")
+	require.Equal(t, payload.Address, res2.Address.Address, "❌ address mismatch")
+	require.Equal(t, payload.TxHash, res2.TxHash, "❌ txHash mismatch")
+	require.Equal(t, payload.ABI, res2.ABI, "❌ ABI mismatch")
 
 }
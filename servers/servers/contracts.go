@@ -1,18 +1,29 @@
 package servers
 
 import (
-	"eth-toy-client/config"
+	"context"
+	"errors"
+	"eth-toy-client/client"
 	contract "eth-toy-client/core/contracts"
 	"eth-toy-client/core/httpapi"
 	toytypes "eth-toy-client/core/types"
 )
 
 func RegisterContract(payload contract.DeployedContractMetaJSON) (*toytypes.AliasRegisterResponse, *httpapi.APIError, error) {
-	registerURL := config.Servers.LogServer.GetServerConfig().GetServerUrl("register-contract")
-	return httpapi.PostWithAPIResponse[toytypes.AliasRegisterResponse](registerURL, payload)
+	res, err := client.DefaultLogServerClient().RegisterContract(context.Background(), payload)
+	return splitAPIError(res, err)
 }
 
-func GetContract(contractAddress toytypes.ContractAddress) (*contract.DeployedContractMetaJSON, *httpapi.APIError, error) {
-	contractURL := config.Servers.LogServer.GetServerConfig().GetServerUrl("contract/" + contractAddress.Address)
-	return httpapi.PostWithAPIResponseNoPayload[contract.DeployedContractMetaJSON](contractURL)
+func GetContract(contractAddress toytypes.ContractAddress) (*contract.DeployedContractInfo, *httpapi.APIError, error) {
+	res, err := client.DefaultLogServerClient().Contract(context.Background(), contractAddress.Address)
+	return splitAPIError(res, err)
+}
+
+// splitAPIError keeps the (result, apiError, err) shape these helpers have always returned
+func splitAPIError[T any](res *T, err error) (*T, *httpapi.APIError, error) {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		return nil, &apiErr.APIError, nil
+	}
+	return res, nil, err
 }
@@ -2,10 +2,17 @@ package servers
 
 import (
 	"eth-toy-client/config"
+	"eth-toy-client/core/httpapi"
 	"net/http"
 )
 
-func SetupPingRoute(name config.ServerName, mux *http.ServeMux) {
+// Mux is what routes are registered on, a *http.ServeMux or an openapi.Recorder listing them
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+func SetupPingRoute(name config.ServerName, mux Mux) {
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		_, err := w.Write([]byte(name + " says pong"))
 		if err != nil {
@@ -13,3 +20,10 @@ func SetupPingRoute(name config.ServerName, mux *http.ServeMux) {
 		}
 	})
 }
+
+// SetupConfigRoute serves the effective configuration with secrets redacted
+func SetupConfigRoute(mux Mux) {
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, config.Current().Redacted())
+	})
+}
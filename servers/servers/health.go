@@ -0,0 +1,140 @@
+package servers
+
+import (
+	"context"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds every health check so a hung node cannot hang /readyz
+const checkTimeout = 2 * time.Second
+
+// HealthCheck reports a failing dependency as an error
+type HealthCheck func(ctx context.Context) error
+
+// HealthChecker is implemented by services whose readiness depends on more than the dev node,
+// e.g. LogServer's subscriptions
+type HealthChecker interface {
+	HealthChecks() map[string]HealthCheck
+}
+
+// Status is a health flag that a background worker keeps up to date, e.g. whether its
+// subscription is live. Its Check method is a HealthCheck.
+type Status struct {
+	mu  sync.RWMutex
+	err error
+}
+
+// NewStatus starts unhealthy until the worker reports otherwise
+func NewStatus(initial error) *Status {
+	return &Status{err: initial}
+}
+
+func (s *Status) Set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *Status) Check(context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+// Health runs the registered checks for /healthz and /readyz
+type Health struct {
+	mu     sync.RWMutex
+	state  string
+	checks map[string]HealthCheck
+}
+
+func NewHealth() *Health {
+	return &Health{state: toytypes.HealthStarting, checks: make(map[string]HealthCheck)}
+}
+
+func (h *Health) Register(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// SetState moves the lifecycle between starting, ok and stopping
+func (h *Health) SetState(state string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state = state
+}
+
+// Check runs every check concurrently. The service is ready once started and while all checks pass.
+func (h *Health) Check(ctx context.Context) *toytypes.HealthResponse {
+	h.mu.RLock()
+	state := h.state
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	checks := make([]HealthCheck, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	results := make([]toytypes.HealthCheckResult, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, names[i], checks[i])
+		}()
+	}
+	wg.Wait()
+
+	resp := &toytypes.HealthResponse{Status: state, Checks: results}
+	if state != toytypes.HealthOK {
+		return resp
+	}
+	resp.Ready = true
+	for _, result := range results {
+		if !result.OK {
+			resp.Status = toytypes.HealthDegraded
+			resp.Ready = false
+		}
+	}
+	return resp
+}
+
+func runCheck(ctx context.Context, name string, check HealthCheck) toytypes.HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	start := time.Now()
+	err := check(ctx)
+	result := toytypes.HealthCheckResult{Name: name, OK: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// SetupHealthRoutes serves /healthz, which answers 200 while the process is up, and /readyz, which
+// answers 503 Unavailable until the service has started and whenever a check fails
+func SetupHealthRoutes(health *Health, mux Mux) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, health.Check(r.Context()))
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		resp := health.Check(r.Context())
+		if !resp.Ready {
+			httpapi.FailWithDetails(w, httpapi.ErrUnavailable, "Service is "+resp.Status,
+				map[string]any{"status": resp.Status, "checks": resp.Checks})
+			return
+		}
+		httpapi.WriteOK(w, resp)
+	})
+}
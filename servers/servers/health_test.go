@@ -0,0 +1,63 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"eth-toy-client/core/httpapi"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func serveHealth(t *testing.T, health *Health, path string) (int, *toytypes.HealthResponse, *httpapi.APIError) {
+	mux := http.NewServeMux()
+	SetupHealthRoutes(health, mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	data, apiErr, err := httpapi.ParseAPIResponse[toytypes.HealthResponse](rec.Result())
+	require.NoError(t, err)
+	return rec.Code, data, apiErr
+}
+
+func TestReadinessFollowsLifecycleAndChecks(t *testing.T) {
+	health := NewHealth()
+	subscription := NewStatus(errors.New("not subscribed yet"))
+	health.Register("node", func(context.Context) error { return nil })
+	health.Register("subscription", subscription.Check)
+
+	code, _, apiErr := serveHealth(t, health, "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code, "not ready before Start")
+	require.ErrorIs(t, apiErr, httpapi.ErrUnavailable)
+	require.Equal(t, toytypes.HealthStarting, apiErr.Details["status"])
+
+	health.SetState(toytypes.HealthOK)
+	code, _, apiErr = serveHealth(t, health, "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code, "a failing check keeps it unready")
+	require.Equal(t, toytypes.HealthDegraded, apiErr.Details["status"])
+
+	subscription.Set(nil)
+	code, resp, _ := serveHealth(t, health, "/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, resp.Ready)
+	require.Equal(t, []string{"node", "subscription"}, []string{resp.Checks[0].Name, resp.Checks[1].Name})
+
+	health.SetState(toytypes.HealthStopping)
+	code, _, _ = serveHealth(t, health, "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code, "not ready while draining")
+}
+
+func TestHealthzReportsFailingChecks(t *testing.T) {
+	health := NewHealth()
+	health.SetState(toytypes.HealthOK)
+	health.Register("node", func(context.Context) error { return errors.New("connection refused") })
+
+	code, resp, _ := serveHealth(t, health, "/healthz")
+	require.Equal(t, http.StatusOK, code, "liveness does not depend on the node")
+	require.Equal(t, toytypes.HealthDegraded, resp.Status)
+	require.False(t, resp.Ready)
+	require.Equal(t, "connection refused", resp.Checks[0].Error)
+}
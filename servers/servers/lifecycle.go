@@ -0,0 +1,181 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"eth-toy-client/config"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/metrics"
+	"eth-toy-client/core/siwe"
+	toytypes "eth-toy-client/core/types"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds draining in-flight requests and stopping the services' workers
+const shutdownTimeout = 10 * time.Second
+
+type MicroService interface {
+	Name() config.ServerName
+	InitService(shared *Shared, serverConfig config.ServerConfig) (config.ServerConfig, http.Handler)
+	// Start launches background workers once the server listens; ctx ends when shutdown begins
+	Start(ctx context.Context) error
+	// Stop drains the workers after HTTP requests are drained, giving up when ctx expires
+	Stop(ctx context.Context) error
+}
+
+// RunMicroService loads the configuration and runs one service until SIGINT or SIGTERM
+func RunMicroService(microService MicroService) error {
+	if _, err := config.Init(os.Args[1:]); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return RunUntilSignal(microService)
+}
+
+// RunUntilSignal hosts the services in this process until SIGINT or SIGTERM
+func RunUntilSignal(microServices ...MicroService) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return NewLifecycle(microServices...).Run(ctx)
+}
+
+// Lifecycle hosts MicroServices in one process, each on its own port, sharing one node connection,
+// contract registry and log broadcaster
+type Lifecycle struct {
+	services []MicroService
+}
+
+func NewLifecycle(microServices ...MicroService) *Lifecycle {
+	return &Lifecycle{services: microServices}
+}
+
+// hosted is a service with its server and health
+type hosted struct {
+	service MicroService
+	config  config.ServerConfig
+	health  *Health
+	server  *http.Server
+}
+
+// Run serves the services until ctx ends or an HTTP server fails. Shutdown turns /readyz
+// unavailable, drains HTTP requests, stops the services in reverse order and finally closes the
+// node connections.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	if len(l.services) == 0 {
+		return errors.New("no services to run")
+	}
+	nodeClient := EstablishConnectionToDevNode(l.services[0].Name().GetServerConfig().DevNodeConfig)
+	defer nodeClient.Close()
+	shared := NewShared(nodeClient)
+
+	serviceCtx, cancelServices := context.WithCancel(ctx)
+	defer cancelServices()
+	serveErr := make(chan error, len(l.services))
+	var all, started []*hosted
+	var runErr error
+	for _, service := range l.services {
+		h, err := serve(service, shared, serveErr)
+		if err != nil {
+			runErr = err
+			break
+		}
+		all = append(all, h)
+	}
+	for _, h := range all {
+		if runErr != nil {
+			break
+		}
+		if err := h.service.Start(serviceCtx); err != nil {
+			runErr = fmt.Errorf("failed to start %s: %w", h.config.Name, err)
+			break
+		}
+		started = append(started, h)
+		h.health.SetState(toytypes.HealthOK)
+		logger.Info("✅ Server is ready", "server", h.config.Name)
+	}
+
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			logger.Info("🛑 Shutting down...")
+		case err := <-serveErr:
+			runErr = fmt.Errorf("HTTP server failed: %w", err)
+		}
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	for _, h := range all {
+		h.health.SetState(toytypes.HealthStopping)
+	}
+	for _, h := range all {
+		if err := h.server.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("⚠️ Server did not drain HTTP requests", "server", h.config.Name, "error", err)
+		}
+	}
+	cancelServices()
+	for i := len(started) - 1; i >= 0; i-- {
+		h := started[i]
+		if err := h.service.Stop(shutdownCtx); err != nil {
+			runErr = errors.Join(runErr, fmt.Errorf("failed to stop %s: %w", h.config.Name, err))
+		}
+		logger.Info("👋 Server stopped", "server", h.config.Name)
+	}
+	return runErr
+}
+
+// serve initializes a service and starts its HTTP server; failures of the running server go to serveErr
+func serve(service MicroService, shared *Shared, serveErr chan<- error) (*hosted, error) {
+	serverConfig := service.Name().GetServerConfig()
+	logger.Info("📡 Starting server", "server", serverConfig.Name, "port", serverConfig.Port)
+	serverConfig, handler := service.InitService(shared, serverConfig)
+
+	h := &hosted{service: service, config: serverConfig, health: NewHealth()}
+	h.health.Register("node", shared.NodeClient.Ping)
+	if checker, ok := service.(HealthChecker); ok {
+		for name, check := range checker.HealthChecks() {
+			h.health.Register(name, check)
+		}
+	}
+	handler = rootHandler(serverConfig, h.health, shared.Auth, handler)
+
+	listener, err := net.Listen("tcp", ":"+serverConfig.Port)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to listen on port %s: %w", serverConfig.Name, serverConfig.Port, err)
+	}
+	h.server = &http.Server{Handler: handler}
+	go func() {
+		if err := h.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- fmt.Errorf("%s: %w", serverConfig.Name, err)
+		}
+	}()
+	logger.Info("🌐 Server is listening", "server", serverConfig.Name, "ping", serverConfig.GetServerUrl("ping"))
+	return h, nil
+}
+
+// SetupRootRoutes registers the operational routes every server answers in front of its own
+func SetupRootRoutes(health *Health, mux Mux) {
+	SetupHealthRoutes(health, mux)
+	mux.Handle("GET /metrics", metrics.Handler())
+	SetupLogLevelRoutes(mux)
+}
+
+// rootHandler adds the health, metrics and log level routes in front of the service's routes,
+// applies SIWE when auth is set, instruments every request and gives it a request ID
+func rootHandler(serverConfig config.ServerConfig, health *Health, auth *siwe.Authenticator, handler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	SetupRootRoutes(health, mux)
+	mux.Handle("/", handler)
+	handler = mux
+
+	if auth != nil {
+		handler = auth.Protect(handler)
+	}
+	handler = metrics.Instrument(string(serverConfig.Name), handler)
+	return logutil.Middleware(logger.With("server", serverConfig.Name), handler)
+}
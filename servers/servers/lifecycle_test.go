@@ -0,0 +1,66 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eth-toy-client/accounts"
+	"eth-toy-client/config"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/siwe"
+	toytypes "eth-toy-client/core/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostedServersShareSIWESessions(t *testing.T) {
+	alice := accounts.MustDerive(accounts.DefaultConfig())[0]
+	auth := siwe.NewAuthenticator(siwe.Config{Domain: "localhost", ChainID: 1337})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, &struct{}{})
+	})
+	devServer := rootHandler(config.ServerConfig{Name: "DevServer"}, NewHealth(), auth, ok)
+	logServer := rootHandler(config.ServerConfig{Name: "LogServer"}, NewHealth(), auth, ok)
+	serve := func(handler http.Handler, method, path, token string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(devServer, http.MethodGet, siwe.NoncePath, "", nil)
+	nonce, _, err := httpapi.ParseAPIResponse[toytypes.SiweNonceResponse](rec.Result())
+	require.NoError(t, err)
+	msg := (&siwe.Message{
+		Domain:   "localhost",
+		Address:  alice.Address,
+		URI:      "http://localhost",
+		Version:  "1",
+		ChainID:  1337,
+		Nonce:    nonce.Nonce,
+		IssuedAt: time.Now(),
+	}).String()
+	signature, err := alice.Signer.SignText([]byte(msg))
+	require.NoError(t, err)
+	body, err := json.Marshal(toytypes.SiweVerifyRequest{Message: msg, Signature: hexutil.Encode(signature)})
+	require.NoError(t, err)
+
+	rec = serve(logServer, http.MethodPost, siwe.VerifyPath, "", body)
+	require.Equal(t, http.StatusOK, rec.Code, "a nonce from one server verifies on another")
+	session, _, err := httpapi.ParseAPIResponse[toytypes.SiweSessionResponse](rec.Result())
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusUnauthorized, serve(devServer, http.MethodGet, "/api/accounts", "", nil).Code)
+	require.Equal(t, http.StatusOK, serve(devServer, http.MethodGet, "/api/accounts", session.Token, nil).Code,
+		"one sign-in serves every hosted server")
+
+	serve(logServer, http.MethodPost, siwe.LogoutPath, session.Token, nil)
+	require.Equal(t, http.StatusUnauthorized, serve(devServer, http.MethodGet, "/api/accounts", session.Token, nil).Code)
+}
@@ -0,0 +1,41 @@
+package servers
+
+import (
+	"encoding/json"
+	"errors"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+)
+
+// SetupLogLevelRoutes serves the log level on GET /loglevel and changes it on PUT /loglevel. The
+// level is process-wide, so with several servers in one process it changes for all of them.
+func SetupLogLevelRoutes(mux Mux) {
+	mux.HandleFunc("GET /loglevel", func(w http.ResponseWriter, r *http.Request) {
+		httpapi.WriteOK(w, &toytypes.LogLevelResponse{Level: logutil.Level()})
+	})
+	mux.HandleFunc("PUT /loglevel", func(w http.ResponseWriter, r *http.Request) {
+		var req toytypes.LogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidRequest, "Invalid JSON payload")
+			return
+		}
+		if err := toytypes.Validate(req); err != nil {
+			var fields toytypes.ValidationError
+			if errors.As(err, &fields) {
+				httpapi.FailWithDetails(w, httpapi.ErrInvalidParameter, err.Error(), map[string]any{"fields": fields})
+				return
+			}
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+			return
+		}
+		previous := logutil.Level()
+		if err := logutil.SetLevel(req.Level); err != nil {
+			httpapi.Fail(w, httpapi.ErrInvalidParameter, err.Error())
+			return
+		}
+		logger.InfoContext(r.Context(), "🎚️ Log level changed", "from", previous, "to", logutil.Level())
+		httpapi.WriteOK(w, &toytypes.LogLevelResponse{Level: logutil.Level()})
+	})
+}
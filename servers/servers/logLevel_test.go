@@ -0,0 +1,32 @@
+package servers
+
+import (
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/logutil"
+	toytypes "eth-toy-client/core/types"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelCanChangeAtRuntime(t *testing.T) {
+	t.Cleanup(func() { _ = logutil.SetLevel("info") })
+	mux := http.NewServeMux()
+	SetupLogLevelRoutes(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(`{"level":"debug"}`)))
+	resp, _, err := httpapi.ParseAPIResponse[toytypes.LogLevelResponse](rec.Result())
+	require.NoError(t, err)
+	require.Equal(t, "debug", resp.Level)
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(`{"level":"verbose"}`)))
+	_, apiErr, err := httpapi.ParseAPIResponse[toytypes.LogLevelResponse](rec.Result())
+	require.NoError(t, err)
+	require.ErrorIs(t, apiErr, httpapi.ErrInvalidParameter)
+	require.Equal(t, "debug", logutil.Level(), "a rejected level keeps the current one")
+}
@@ -0,0 +1,22 @@
+package servers
+
+import (
+	"eth-toy-client/config"
+	"eth-toy-client/core/openapi"
+	"eth-toy-client/core/siwe"
+	toytypes "eth-toy-client/core/types"
+)
+
+// Operations documents the routes every server answers, see SetupRootRoutes, SetupPingRoute,
+// SetupConfigRoute and the SIWE endpoints
+func Operations() []openapi.Operation {
+	return append(siwe.Operations(), []openapi.Operation{
+		{Method: "GET", Path: "/ping", Summary: "Answers with a pong line", Tag: "ops", Produces: "text/plain"},
+		{Method: "GET", Path: "/config", Summary: "Effective configuration with secrets redacted", Tag: "ops", Response: config.Config{}},
+		{Method: "GET", Path: "/healthz", Summary: "Liveness report, always 200 while the process serves", Tag: "ops", Response: toytypes.HealthResponse{}},
+		{Method: "GET", Path: "/readyz", Summary: "Readiness report, 503 until started and while a check fails", Tag: "ops", Response: toytypes.HealthResponse{}},
+		{Method: "GET", Path: "/metrics", Summary: "Prometheus metrics in the text format", Tag: "ops", Produces: "text/plain"},
+		{Method: "GET", Path: "/loglevel", Summary: "Current log level", Tag: "ops", Response: toytypes.LogLevelResponse{}},
+		{Method: "PUT", Path: "/loglevel", Summary: "Change the log level at runtime", Tag: "ops", Request: toytypes.LogLevelRequest{}, Response: toytypes.LogLevelResponse{}},
+	}...)
+}
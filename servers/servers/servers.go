@@ -1,15 +1,20 @@
 package servers
 
 import (
+	"context"
 	"eth-toy-client/config"
+	contract "eth-toy-client/core/contracts"
+	"eth-toy-client/core/logutil"
+	"eth-toy-client/core/siwe"
+	"eth-toy-client/logbus"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
-	"log"
 	"math/big"
-	"net/http"
 	"time"
 )
 
+var logger = logutil.For("servers")
+
 type ChainId *big.Int
 type Nonce *big.Int
 
@@ -20,7 +25,7 @@ func PingDevNode(rpcClient *rpc.Client) bool {
 }
 
 func ConnectToDevNode(config config.DevNodeConfig) (*rpc.Client, <-chan struct{}, error) {
-	client, err := rpc.Dial("http://localhost:" + config.Port)
+	client, err := rpc.Dial(config.HTTPURL)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -29,11 +34,11 @@ func ConnectToDevNode(config config.DevNodeConfig) (*rpc.Client, <-chan struct{}
 	go func() {
 		for {
 			if PingDevNode(client) {
-				log.Printf("✅ Geth dev node is ready on port %s", config.Port)
+				logger.Info("✅ Geth dev node is ready", "url", config.HTTPURL)
 				close(ready)
 				return
 			}
-			log.Println("⏳ Waiting for Geth to be ready...")
+			logger.Info("⏳ Waiting for Geth to be ready...")
 			time.Sleep(1 * time.Second)
 		}
 	}()
@@ -48,52 +53,65 @@ type NodeClient struct {
 	WSClient  *ethclient.Client
 }
 
-func EstablishConnectionToDevNode(name config.ServerName) (config.ServerConfig, *NodeClient) {
-	serverConfig := name.GetServerConfig()
-	log.Printf("📡 starting Server: %+v", serverConfig)
-	rpcClient, readyChannel, err := ConnectToDevNode(serverConfig.DevNodeConfig)
+// Ping checks node connectivity over HTTP RPC, it is the "node" health check
+func (nodeClient *NodeClient) Ping(ctx context.Context) error {
+	var version string
+	return nodeClient.RPCClient.CallContext(ctx, &version, "web3_clientVersion")
+}
+
+// Close closes the HTTP and WebSocket connections to the node
+func (nodeClient *NodeClient) Close() {
+	nodeClient.WSClient.Close()
+	nodeClient.Client.Close()
+}
+
+// Shared is what the services hosted in one process have in common
+type Shared struct {
+	NodeClient  *NodeClient
+	Registry    *contract.Registry
+	Broadcaster logbus.LogBroadcaster
+	// Auth holds the SIWE nonces and sessions of every hosted server, so one sign-in serves them
+	// all; nil unless Sign-In with Ethereum is enabled
+	Auth *siwe.Authenticator
+}
+
+func NewShared(nodeClient *NodeClient) *Shared {
+	shared := &Shared{
+		NodeClient:  nodeClient,
+		Registry:    contract.NewRegistry(),
+		Broadcaster: logbus.NewLogBroadcaster(),
+	}
+	if authConfig := config.Current().SIWEConfig(); authConfig != nil {
+		logger.Info("🔐 Sign-In with Ethereum required", "allowlisted", len(authConfig.Allowlist))
+		shared.Auth = siwe.NewAuthenticator(*authConfig)
+	}
+	return shared
+}
+
+func EstablishConnectionToDevNode(nodeConfig config.DevNodeConfig) *NodeClient {
+	rpcClient, readyChannel, err := ConnectToDevNode(nodeConfig)
 	if err != nil {
-		log.Fatalf("Error starting dev node: %v", err)
+		logutil.Fatal(logger, "❌ Failed to dial dev node", "error", err)
 	}
 
 	select {
 	case <-readyChannel:
-		log.Println("🚦 Node is readyChannel. Proceed.")
+		logger.Debug("🚦 Node is ready. Proceed.")
 	case <-time.After(5 * time.Second):
-		log.Fatal("🕒 Timeout waiting for dev node to start.")
+		logutil.Fatal(logger, "🕒 Timeout waiting for dev node to start")
 	}
 
 	client := ethclient.NewClient(rpcClient)
-	wsClient, err := ethclient.Dial("ws://127.0.0.1:" + serverConfig.DevNodeConfig.WebSocketPort)
+	wsClient, err := ethclient.Dial(nodeConfig.WSURL)
 	if err != nil {
-		log.Fatalf("❌ Failed to connect to WebSocket: %v", err)
+		logutil.Fatal(logger, "❌ Failed to connect to WebSocket", "url", nodeConfig.WSURL, "error", err)
 	}
-	log.Println("✅ Connected to Geth via WebSocket")
-
-	return serverConfig,
-		&NodeClient{
-			Config:    serverConfig.DevNodeConfig,
-			Client:    client,
-			RPCClient: rpcClient,
-			WSClient:  wsClient,
-		}
-}
-
-type MicroService interface {
-	Name() config.ServerName
-	InitService(nodeClient *NodeClient, serverConfig config.ServerConfig) (config.ServerConfig, http.Handler)
-}
+	logger.Info("✅ Connected to Geth via WebSocket", "url", nodeConfig.WSURL)
 
-func StartMicroService(microService MicroService) {
-	serverConfig, nodeClient := EstablishConnectionToDevNode(microService.Name())
-	defer nodeClient.Client.Close()
-	defer nodeClient.RPCClient.Close()
-	_, handler := microService.InitService(nodeClient, serverConfig)
-	go func() {
-		log.Println("🌐 " + string(serverConfig.Name) + " You can ping http://localhost:" + serverConfig.Port + "/ping ...")
-		err := http.ListenAndServe(":"+serverConfig.Port, handler)
-		if err != nil {
-			log.Fatalf("❌ Failed to start HTTP server: %v", err)
-		}
-	}()
+	return &NodeClient{
+		Config:    nodeConfig,
+		Client:    client,
+		RPCClient: rpcClient,
+		WSClient:  wsClient,
+	}
 }
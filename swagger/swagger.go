@@ -0,0 +1,71 @@
+// Package swagger serves the servers' OpenAPI documents and Swagger UI, both embedded in the
+// binary so the explorer works offline. ui holds the vendored swagger-ui-dist files. The documents
+// are generated from the route tables and core/types:
+//
+//	go generate ./swagger
+package swagger
+
+import (
+	"embed"
+	"eth-toy-client/core/httpapi"
+	"eth-toy-client/core/openapi"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:generate go run ../cmd/openapi -out .
+
+//go:embed *.json
+var specs embed.FS
+
+//go:embed ui
+var ui embed.FS
+
+// explorer is Swagger UI, served from the root of ui
+var explorer = func() fs.FS {
+	sub, err := fs.Sub(ui, "ui")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}()
+
+// Spec returns an embedded document, e.g. Spec("devserver.json")
+func Spec(name string) ([]byte, error) {
+	return specs.ReadFile(name)
+}
+
+// Handler serves Swagger UI on /swagger/ and the document named spec on /swagger/openapi.json
+func Handler(spec string) http.Handler {
+	files := http.StripPrefix("/swagger/", http.FileServerFS(explorer))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/swagger/")
+		switch name {
+		case "openapi.json":
+			doc, err := Spec(spec)
+			if err != nil {
+				httpapi.Fail(w, httpapi.ErrNotFound, err.Error())
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(doc)
+		default:
+			if name != "" {
+				if _, err := fs.Stat(explorer, name); err != nil {
+					httpapi.Fail(w, httpapi.ErrNotFound, "No such file "+r.URL.Path)
+					return
+				}
+			}
+			files.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Operations documents the routes Handler answers
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/swagger/", Summary: "Swagger UI", Tag: "ops", Produces: "text/html"},
+		{Method: "GET", Path: "/swagger/openapi.json", Summary: "This OpenAPI document", Tag: "ops", Produces: "application/json"},
+	}
+}
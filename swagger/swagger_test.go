@@ -0,0 +1,37 @@
+package swagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerServesSwaggerUI(t *testing.T) {
+	handler := Handler("devserver.json")
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/swagger/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "swagger-ui-bundle.js")
+
+	rec = get("/swagger/swagger-initializer.js")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `url: "openapi.json"`)
+
+	rec = get("/swagger/swagger-ui-bundle.js")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Type"), "javascript")
+
+	rec = get("/swagger/openapi.json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	rec = get("/swagger/missing.js")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
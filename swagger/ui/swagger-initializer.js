@@ -0,0 +1,18 @@
+// Points Swagger UI at the document served next to it. Bearer tokens from /api/auth/verify are
+// entered through Authorize and only needed when the server requires Sign-In with Ethereum.
+window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "openapi.json",
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};